@@ -0,0 +1,308 @@
+package scheduler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Role is what a caller of the admin API may do.
+type Role int
+
+const (
+	// The caller may do nothing.
+	RoleNone Role = iota
+
+	// The caller may list the entries and read their history.
+	RoleReader
+
+	// The caller may only trigger entries from a webhook, see Trigger, and
+	// read nothing, so the token of the system sending the webhooks gives
+	// away no more than that.
+	RoleTrigger
+
+	// The caller may read as RoleReader, trigger as RoleTrigger, and also
+	// add, run, pause and remove entries.
+	RoleOperator
+)
+
+// allows reports whether the role may do what need does. RoleTrigger is
+// apart from the others, which each may do what the one before does.
+func (r Role) allows(need Role) bool {
+	if need == RoleTrigger || r == RoleTrigger {
+		return r == need || r == RoleOperator
+	}
+	return r >= need
+}
+
+// Authenticator works out the role of the caller of an admin request.
+type Authenticator func(r *http.Request) Role
+
+// TokenAuth authenticates callers by the bearer token of their Authorization
+// header, giving each token its role.
+func TokenAuth(tokens map[string]Role) Authenticator {
+	return func(r *http.Request) Role {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			return RoleNone
+		}
+		for t, role := range tokens {
+			if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+				return role
+			}
+		}
+		return RoleNone
+	}
+}
+
+// Admin is an HTTP handler for administering a Cron:
+//
+//	GET    /entries                the entries                RoleReader
+//	POST   /entries                add one, see AddDefinition RoleOperator
+//	GET    /entries/{name}/history the run history of one     RoleReader
+//	POST   /entries/{name}/run     run it now, see RunNow     RoleOperator
+//	DELETE /entries/{name}         remove it                  RoleOperator
+//	POST   /trigger/{name}         run it early, see Trigger  RoleTrigger
+//	GET    /history                runs of all the entries    RoleReader
+//	GET    /concurrency            runs going at once         RoleReader
+//	GET    /running                the runs in flight         RoleReader
+//	POST   /bulk/{op}              act on many, see Bulk      RoleOperator
+//
+// POST /entries takes a JobDefinition as JSON, and returns the entry it
+// added with a 201. GET /history takes the filters and pages of
+// ParseHistoryQuery, and returns a HistoryPage. GET /concurrency takes the
+// same filters, and the width of the buckets as bucket, such as 5m, and
+// returns the buckets of Concurrency. GET /running returns the RunningInfo
+// of each run in flight, see Running. POST /bulk/{op}, op being pause,
+// resume, remove or run, picks the entries by the name and tag parameters
+// of a Selector, and returns a BulkResult for each. POST
+// /trigger/{name} takes the idempotency key from the Idempotency-Key header
+// or the key parameter, and answers a 202 if it started a run, a 200 if the
+// key was seen already.
+//
+// Requests without the role get a 401 if the caller has no role at all, a
+// 403 otherwise.
+type Admin struct {
+	cron *Cron
+	auth Authenticator
+}
+
+// NewAdmin returns the admin API of c, whose callers auth authenticates.
+func NewAdmin(c *Cron, auth Authenticator) *Admin {
+	return &Admin{cron: c, auth: auth}
+}
+
+func (a *Admin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h, role, name := a.route(r)
+	if h == nil {
+		http.NotFound(w, r)
+		return
+	}
+	switch got := a.auth(r); {
+	case got == RoleNone:
+		http.Error(w, "unauthenticated", http.StatusUnauthorized)
+	case !got.allows(role):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	default:
+		h(w, r, name)
+	}
+}
+
+// adminHandler handles a request about the named entry, or for POST
+// /bulk/{op}, with the name of the operation.
+type adminHandler func(w http.ResponseWriter, r *http.Request, name string)
+
+// route returns the handler of the request, the role it needs and the name
+// of the entry it is about.
+func (a *Admin) route(r *http.Request) (h adminHandler, role Role, name string) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) == 1 && parts[0] == "history" && r.Method == http.MethodGet {
+		return a.query, RoleReader, ""
+	}
+	if len(parts) == 1 && parts[0] == "concurrency" && r.Method == http.MethodGet {
+		return a.concurrency, RoleReader, ""
+	}
+	if len(parts) == 1 && parts[0] == "running" && r.Method == http.MethodGet {
+		return a.running, RoleReader, ""
+	}
+	if len(parts) == 2 && parts[0] == "bulk" && r.Method == http.MethodPost {
+		return a.bulk, RoleOperator, parts[1]
+	}
+	if len(parts) == 2 && parts[0] == "trigger" && r.Method == http.MethodPost {
+		return a.trigger, RoleTrigger, parts[1]
+	}
+	if parts[0] != "entries" {
+		return nil, RoleNone, ""
+	}
+	if len(parts) > 1 {
+		name = parts[1]
+	}
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		return a.entries, RoleReader, ""
+	case len(parts) == 1 && r.Method == http.MethodPost:
+		return a.add, RoleOperator, ""
+	case len(parts) == 2 && r.Method == http.MethodDelete:
+		return a.remove, RoleOperator, name
+	case len(parts) == 3 && parts[2] == "history" && r.Method == http.MethodGet:
+		return a.history, RoleReader, name
+	case len(parts) == 3 && parts[2] == "run" && r.Method == http.MethodPost:
+		return a.run, RoleOperator, name
+	}
+	return nil, RoleNone, ""
+}
+
+// adminEntry is how the admin API shows an entry.
+type adminEntry struct {
+	Name        string            `json:"name"`
+	Version     int               `json:"version"`
+	Tags        []string          `json:"tags,omitempty"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Interval    time.Duration     `json:"interval"`
+	NextTime    time.Time         `json:"next_time"`
+	DisplayTime time.Time         `json:"display_time"`
+	LastOutcome Outcome           `json:"last_outcome,omitempty"`
+	Runs        int               `json:"runs"`
+	Pending     int               `json:"pending"`
+	Disabled    bool              `json:"disabled"`
+	Progress    ProgressReport    `json:"progress"`
+}
+
+func newAdminEntry(e *Entry) adminEntry {
+	return adminEntry{
+		Name:        e.Name,
+		Version:     e.Version,
+		Tags:        e.Tags,
+		Namespace:   e.Namespace,
+		Metadata:    e.Metadata,
+		Interval:    e.Interval,
+		NextTime:    e.NextTime,
+		DisplayTime: e.DisplayTime(e.NextTime),
+		LastOutcome: e.LastOutcome,
+		Runs:        e.Runs,
+		Pending:     e.Pending,
+		Disabled:    e.Disabled,
+		Progress:    e.Progress,
+	}
+}
+
+func (a *Admin) entries(w http.ResponseWriter, r *http.Request, _ string) {
+	entries := []adminEntry{}
+	for _, e := range a.cron.Entries() {
+		entries = append(entries, newAdminEntry(e))
+	}
+	writeJSON(w, entries)
+}
+
+func (a *Admin) add(w http.ResponseWriter, r *http.Request, _ string) {
+	var def JobDefinition
+	if err := json.NewDecoder(r.Body).Decode(&def); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	name, err := a.cron.AddDefinition(def)
+	switch {
+	case errors.Is(err, ErrDuplicateName):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil && name == "":
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	e, ok := a.cron.Entry(name)
+	if !ok {
+		// Removed in the meantime, or a one-shot that ran already.
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newAdminEntry(e))
+}
+
+func (a *Admin) history(w http.ResponseWriter, r *http.Request, name string) {
+	if _, ok := a.cron.Entry(name); !ok {
+		http.Error(w, ErrNoSuchEntry.Error(), http.StatusNotFound)
+		return
+	}
+	history := a.cron.History(name)
+	if history == nil {
+		history = []RunRecord{}
+	}
+	writeJSON(w, history)
+}
+
+func (a *Admin) query(w http.ResponseWriter, r *http.Request, _ string) {
+	q, err := ParseHistoryQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, a.cron.QueryHistory(q))
+}
+
+func (a *Admin) concurrency(w http.ResponseWriter, r *http.Request, _ string) {
+	q, err := ParseHistoryQuery(r.URL.Query())
+	var bucket time.Duration
+	if s := r.URL.Query().Get("bucket"); s != "" && err == nil {
+		if bucket, err = time.ParseDuration(s); err != nil {
+			err = fmt.Errorf("scheduler: bad bucket: %w", err)
+		}
+	}
+	var buckets []ConcurrencyBucket
+	if err == nil {
+		buckets, err = a.cron.Concurrency(q, bucket)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, buckets)
+}
+
+func (a *Admin) running(w http.ResponseWriter, r *http.Request, _ string) {
+	writeJSON(w, a.cron.Running())
+}
+
+func (a *Admin) run(w http.ResponseWriter, r *http.Request, name string) {
+	record, err := a.cron.RunNow(name)
+	switch {
+	case errors.Is(err, ErrNoSuchEntry):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeJSON(w, record)
+	}
+}
+
+func (a *Admin) remove(w http.ResponseWriter, r *http.Request, name string) {
+	if !a.cron.removeJob(name) {
+		http.Error(w, ErrNoSuchEntry.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Admin) bulk(w http.ResponseWriter, r *http.Request, op string) {
+	q := r.URL.Query()
+	results, err := a.cron.Bulk(BulkOp(op), Selector{Name: q.Get("name"), Tag: q.Get("tag")})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, results)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
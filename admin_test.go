@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func adminRequest(t *testing.T, h http.Handler, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminRoles(t *testing.T) {
+	cron := New()
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "job")
+	cron.Start()
+	defer cron.Stop()
+	admin := NewAdmin(cron, TokenAuth(map[string]Role{"r": RoleReader, "op": RoleOperator}))
+
+	for _, tc := range []struct {
+		method, path, token string
+		code                int
+	}{
+		{"GET", "/entries", "", http.StatusUnauthorized},
+		{"GET", "/entries", "wrong", http.StatusUnauthorized},
+		{"GET", "/entries", "r", http.StatusOK},
+		{"GET", "/entries/job/history", "r", http.StatusOK},
+		{"POST", "/entries/job/run", "r", http.StatusForbidden},
+		{"DELETE", "/entries/job", "r", http.StatusForbidden},
+		{"POST", "/entries/missing/run", "op", http.StatusNotFound},
+		{"POST", "/entries/job/run", "op", http.StatusOK},
+		{"DELETE", "/entries/job", "op", http.StatusNoContent},
+		{"GET", "/entries/job/history", "op", http.StatusNotFound},
+	} {
+		if w := adminRequest(t, admin, tc.method, tc.path, tc.token); w.Code != tc.code {
+			t.Errorf("%s %s as %q: expected %d, got %d", tc.method, tc.path, tc.token, tc.code, w.Code)
+		}
+	}
+}
+
+func TestAdminEntries(t *testing.T) {
+	cron := New()
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "job", WithTags("nightly"))
+	admin := NewAdmin(cron, TokenAuth(map[string]Role{"r": RoleReader}))
+
+	w := adminRequest(t, admin, "GET", "/entries", "r")
+	var entries []adminEntry
+	if err := json.NewDecoder(w.Body).Decode(&entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name != "job" || entries[0].Tags[0] != "nightly" {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+}
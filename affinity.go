@@ -0,0 +1,24 @@
+package scheduler
+
+// WithAffinity pins the entry to the instances of a fleet sharing its
+// entries that have all the given labels, see WithInstanceLabels, such as
+// "has-gpu" or "eu-region", so its runs happen where the resources or the
+// data they need are. The other instances ignore its triggers, and refuse
+// RunNow with ErrNotDispatched.
+func WithAffinity(labels ...string) EntryOption {
+	return func(e *Entry) {
+		e.Affinity = append(e.Affinity, labels...)
+	}
+}
+
+// missingLabels returns the labels of the affinity of e the instance
+// doesn't have, none if the entry may run on it.
+func (c *Cron) missingLabels(e *Entry) []string {
+	var missing []string
+	for _, label := range e.Affinity {
+		if !c.instanceLabels[label] {
+			missing = append(missing, label)
+		}
+	}
+	return missing
+}
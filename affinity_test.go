@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAffinity(t *testing.T) {
+	skipped := make(chan Event, 10)
+	cron := New(WithInstanceLabels("eu-region"), WithEventHandler(func(ev Event) {
+		if ev.Type == EventTriggerSkipped {
+			skipped <- ev
+		}
+	}))
+	ran := make(chan string, 10)
+	start := time.Now().Add(50 * time.Millisecond)
+	cron.AddFunc(start, time.Hour, func() { ran <- "eu" }, "eu", WithAffinity("eu-region"))
+	cron.AddFunc(start, time.Hour, func() { ran <- "gpu" }, "gpu", WithAffinity("eu-region", "has-gpu"))
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case ev := <-skipped:
+		if ev.Name != "gpu" || ev.Reason != ReasonAffinity {
+			t.Errorf("unexpected skip %+v", ev)
+		}
+	case <-time.After(ONE_SECOND):
+		t.Fatal("expected the pinned entry to be skipped")
+	}
+	if name := <-ran; name != "eu" {
+		t.Errorf("expected only the entry with matching labels to run, got %q", name)
+	}
+	if _, err := cron.RunNow("gpu"); !errors.Is(err, ErrNotDispatched) {
+		t.Errorf("expected RunNow to be refused elsewhere, got %v", err)
+	}
+	if s := cron.Explain("gpu"); !strings.Contains(s, "lacks the labels has-gpu") {
+		t.Errorf("expected Explain to tell why, got\n%s", s)
+	}
+	select {
+	case name := <-ran:
+		t.Errorf("expected %q not to run", name)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,69 @@
+package scheduler
+
+import "time"
+
+// WithMaxRuns retires the entry after n runs: it is removed from the
+// schedule, and once its last run is over it is moved to the archive (see
+// Archived).
+func WithMaxRuns(n int) EntryOption {
+	return func(e *Entry) {
+		e.MaxRuns = n
+	}
+}
+
+// AddOnceFunc adds a func to be run once, at the given time or right away if
+// that time is past. The entry is archived once it has run.
+func (c *Cron) AddOnceFunc(at time.Time, cmd func(), name string, opts ...EntryOption) error {
+	return c.AddJob(at, 0, FuncJob(cmd), name, append(opts, WithMaxRuns(1))...)
+}
+
+// Archived returns the entries that were retired within the archive TTL
+// (see WithArchiveTTL), oldest first, so recently finished work can still be
+// looked at.
+func (c *Cron) Archived() []*Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expireArchive(time.Now())
+	var archived []*Entry
+	for _, e := range c.archive {
+		cp := *e
+		archived = append(archived, &cp)
+	}
+	return archived
+}
+
+// retire removes the entries that dispatched their last run from the
+// schedule. It is called from the run loop.
+func (c *Cron) retire() {
+	c.mu.Lock()
+	retired := c.retiring
+	c.retiring = nil
+	c.mu.Unlock()
+	for _, e := range retired {
+		c.unlink(e)
+		c.spread(e.Interval)
+	}
+}
+
+// archiveEntry moves the entry to the archive once its last run is over.
+// The caller must hold c.mu.
+func (c *Cron) archiveEntry(e *Entry) {
+	now := time.Now()
+	cp := e.copy()
+	cp.NextTime = time.Time{}
+	cp.RetiredAt = now
+	c.expireArchive(now)
+	c.archive = append(c.archive, cp)
+	go c.emit(Event{Type: EventRetired, Name: e.Name, Version: e.Version, Metadata: e.Metadata})
+	go c.publishChange(EventRetired, cp)
+}
+
+// expireArchive drops the archived entries older than the TTL. The caller
+// must hold c.mu.
+func (c *Cron) expireArchive(now time.Time) {
+	i := 0
+	for i < len(c.archive) && now.Sub(c.archive[i].RetiredAt) > c.archiveTTL {
+		i++
+	}
+	c.archive = c.archive[i:]
+}
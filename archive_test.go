@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOneShotIsArchived(t *testing.T) {
+	cron := New()
+	ran := make(chan struct{}, 2)
+	cron.AddOnceFunc(time.Now().Add(-time.Minute), func() { ran <- struct{}{} }, "once")
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case <-ran:
+	case <-time.After(ONE_SECOND):
+		t.Fatal("one-shot entry did not run")
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if n := len(cron.Entries()); n != 0 {
+		t.Errorf("expected the entry to be gone from the schedule, got %d entries", n)
+	}
+	archived := cron.Archived()
+	if len(archived) != 1 || archived[0].Name != "once" || archived[0].Runs != 1 || archived[0].RetiredAt.IsZero() {
+		t.Errorf("unexpected archive %+v", archived)
+	}
+	select {
+	case <-ran:
+		t.Error("one-shot entry ran twice")
+	default:
+	}
+}
+
+func TestMaxRunsAndArchiveTTL(t *testing.T) {
+	cron := New(WithArchiveTTL(200 * time.Millisecond))
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), 50*time.Millisecond, func() {}, "thrice", WithMaxRuns(3))
+	cron.Start()
+	defer cron.Stop()
+
+	waitForHistory(t, cron, "thrice", 3)
+	time.Sleep(50 * time.Millisecond)
+	if archived := cron.Archived(); len(archived) != 1 || archived[0].Runs != 3 {
+		t.Fatalf("expected the entry to be archived after 3 runs, got %+v", archived)
+	}
+	time.Sleep(250 * time.Millisecond)
+	if n := len(cron.History("thrice")); n != 3 {
+		t.Errorf("expected exactly 3 runs, got %d", n)
+	}
+	if archived := cron.Archived(); len(archived) != 0 {
+		t.Errorf("expected the archive to expire, got %+v", archived)
+	}
+}
@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// The most runs a single Backfill may enqueue.
+const backfillLimit = 10000
+
+// Backfill enqueues a run for every time the named entry was due in
+// [from, to), as its schedule would have run it, typically after an outage.
+// The runs go through the usual dispatch, marked as backfill in the
+// history, one every WithBackfillRate so as not to flood whatever they
+// talk to. Backfill returns how many runs it enqueues; they are enqueued in
+// the background, and stop if the entry is removed.
+func (c *Cron) Backfill(name string, from, to time.Time) (int, error) {
+	var def *Entry
+	c.inLoop(func() {
+		if e := c.lookup(name); e != nil {
+			c.mu.Lock()
+			def = e.definition()
+			c.mu.Unlock()
+		}
+	})
+	if def == nil {
+		return 0, ErrNoSuchEntry
+	}
+
+	times := def.occurrences(from, to)
+	if len(times) > backfillLimit {
+		return 0, fmt.Errorf("scheduler: backfilling %q would enqueue %d runs, more than %d", name, len(times), backfillLimit)
+	}
+	go c.backfill(name, times)
+	return len(times), nil
+}
+
+func (c *Cron) backfill(name string, times []time.Time) {
+	for i, t := range times {
+		if i > 0 {
+			time.Sleep(c.backfillRate)
+		}
+		found := false
+		c.inLoop(func() {
+			if e := c.lookup(name); e != nil {
+				found = true
+				c.dispatchTrigger(e, trigger{scheduled: t, backfill: true})
+			}
+		})
+		if !found {
+			return
+		}
+	}
+}
+
+// occurrences returns the times in [from, to) the entry's schedule is due.
+func (e *Entry) occurrences(from, to time.Time) []time.Time {
+	s := e.schedule()
+	if s == nil {
+		return nil
+	}
+	loc := e.location
+	if loc == nil {
+		loc = from.Location()
+	}
+	var times []time.Time
+	for t := s.Next(from.Add(-time.Nanosecond).In(loc)); !t.IsZero() && t.Before(to) && len(times) <= backfillLimit; t = s.Next(t) {
+		times = append(times, t)
+	}
+	return times
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackfill(t *testing.T) {
+	cron := New(WithBackfillRate(20 * time.Millisecond))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cron.AddFunc(start, time.Hour, func() {}, "hourly")
+	cron.Start()
+	defer cron.Stop()
+
+	n, err := cron.Backfill("hourly", start.Add(90*time.Minute), start.Add(5*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 runs, for 02:00, 03:00 and 04:00, got %d", n)
+	}
+
+	runs := waitForHistory(t, cron, "hourly", 3)
+	for i, run := range runs {
+		want := start.Add(time.Duration(i+2) * time.Hour)
+		if !run.Backfill || !run.Scheduled.Equal(want) {
+			t.Errorf("run %d: expected a backfill of %v, got %+v", i, want, run)
+		}
+	}
+	if d := runs[2].Start.Sub(runs[0].Start); d < 40*time.Millisecond {
+		t.Errorf("expected the runs to be spaced out, got %v between the first and last", d)
+	}
+}
+
+func TestBackfillErrors(t *testing.T) {
+	cron := New()
+	cron.AddFunc(time.Now().AddDate(0, 0, -2), time.Second, func() {}, "often")
+	if _, err := cron.Backfill("missing", time.Now().Add(-time.Hour), time.Now()); err != ErrNoSuchEntry {
+		t.Errorf("expected ErrNoSuchEntry, got %v", err)
+	}
+	if _, err := cron.Backfill("often", time.Now().AddDate(0, 0, -1), time.Now()); err == nil {
+		t.Error("expected an error for too many runs")
+	}
+}
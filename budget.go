@@ -0,0 +1,118 @@
+package scheduler
+
+import "time"
+
+// WithCost declares what a run of the entry costs against the budgets that
+// match it. Entries without a cost are free.
+func WithCost(cost float64) EntryOption {
+	return func(e *Entry) {
+		e.Cost = cost
+	}
+}
+
+// Budget caps the total cost of the runs of the matching entries within
+// each window of time. Windows are aligned to the zero time, so an hourly
+// budget resets at the top of each hour.
+type Budget struct {
+	// The entries the budget applies to: those with the tag, in the
+	// namespace, or both if both are set. A budget with neither applies to
+	// every entry.
+	Tag       string
+	Namespace string
+
+	// How much may be spent per window. A budget whose Window isn't
+	// positive is ignored, see WithBudget.
+	Limit  float64
+	Window time.Duration
+
+	// By default a run that would go over the budget is skipped and
+	// recorded as OutcomeOverBudget. With Defer set, it waits for the next
+	// window instead, unless it costs more than the whole Limit.
+	Defer bool
+}
+
+// BudgetUsage is how much of a budget is spent in the current window.
+type BudgetUsage struct {
+	Budget
+	WindowStart time.Time
+	Spent       float64
+}
+
+type budgetState struct {
+	Budget
+	windowStart time.Time
+	spent       float64
+}
+
+func (b *Budget) matches(e *Entry) bool {
+	if b.Tag != "" && !e.HasTag(b.Tag) {
+		return false
+	}
+	if b.Namespace != "" && e.Namespace != b.Namespace {
+		return false
+	}
+	return true
+}
+
+// roll starts a new window if the current one is over.
+func (b *budgetState) roll(now time.Time) {
+	if start := now.Truncate(b.Window); !start.Equal(b.windowStart) {
+		b.windowStart = start
+		b.spent = 0
+	}
+}
+
+// BudgetUsage returns the state of the budgets, in the order they were
+// given.
+func (c *Cron) BudgetUsage() []BudgetUsage {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var usage []BudgetUsage
+	for _, b := range c.budgets {
+		b.roll(now)
+		usage = append(usage, BudgetUsage{Budget: b.Budget, WindowStart: b.windowStart, Spent: b.spent})
+	}
+	return usage
+}
+
+// charge spends the cost of a run of the entry on the budgets it matches.
+// It returns false if the run should be skipped, and blocks until the next
+// window if it should be deferred.
+func (c *Cron) charge(e *Entry) bool {
+	if e.Cost <= 0 || len(c.budgets) == 0 {
+		return true
+	}
+	for {
+		now := time.Now()
+		c.mu.Lock()
+		var over *budgetState
+		for _, b := range c.budgets {
+			if !b.matches(e) {
+				continue
+			}
+			b.roll(now)
+			if b.spent+e.Cost > b.Limit {
+				over = b
+				break
+			}
+		}
+		if over == nil {
+			for _, b := range c.budgets {
+				if b.matches(e) {
+					b.spent += e.Cost
+				}
+			}
+			c.mu.Unlock()
+			return true
+		}
+		wait := over.windowStart.Add(over.Window).Sub(now)
+		deferrable := over.Defer && e.Cost <= over.Limit
+		c.mu.Unlock()
+
+		if !deferrable {
+			return false
+		}
+		time.Sleep(wait)
+	}
+}
@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBudgetSkipsRunsOverLimit(t *testing.T) {
+	cron := New(WithBudget(Budget{Tag: "cloud", Limit: 5, Window: time.Hour}))
+	for _, name := range []string{"a", "b", "c"} {
+		cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() {}, name,
+			WithTags("cloud"), WithCost(2))
+	}
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() {}, "untagged", WithCost(2))
+	cron.Start()
+	defer cron.Stop()
+
+	outcomes := map[Outcome]int{}
+	for _, name := range []string{"a", "b", "c"} {
+		outcomes[waitForHistory(t, cron, name, 1)[0].Outcome]++
+	}
+	if outcomes[OutcomeSuccess] != 2 || outcomes[OutcomeOverBudget] != 1 {
+		t.Errorf("expected 2 runs and 1 skip, got %v", outcomes)
+	}
+	if got := waitForHistory(t, cron, "untagged", 1)[0].Outcome; got != OutcomeSuccess {
+		t.Errorf("expected entries outside the budget to run, got %q", got)
+	}
+	if usage := cron.BudgetUsage(); usage[0].Spent != 4 {
+		t.Errorf("expected 4 spent, got %v", usage[0].Spent)
+	}
+}
+
+func TestBudgetDefersToNextWindow(t *testing.T) {
+	window := 300 * time.Millisecond
+	cron := New(WithBudget(Budget{Namespace: "batch", Limit: 1, Window: window, Defer: true}))
+	for _, name := range []string{"a", "b"} {
+		cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() {}, name,
+			WithNamespace("batch"), WithCost(1))
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	a := waitForHistory(t, cron, "a", 1)[0]
+	b := waitForHistory(t, cron, "b", 1)[0]
+	if a.Outcome != OutcomeSuccess || b.Outcome != OutcomeSuccess {
+		t.Fatalf("expected both runs to happen, got %q and %q", a.Outcome, b.Outcome)
+	}
+	first, second := a.Start, b.Start
+	if second.Before(first) {
+		first, second = second, first
+	}
+	if !second.Truncate(window).After(first.Truncate(window)) {
+		t.Errorf("expected the second run to wait for the next window, got %v and %v", first, second)
+	}
+}
+
+// A budget without a window caps nothing, so it is ignored.
+func TestBudgetWithoutWindow(t *testing.T) {
+	cron := New(WithBudget(Budget{Limit: 1}))
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "job", WithCost(2))
+	cron.Start()
+	defer cron.Stop()
+
+	if r, _ := cron.RunNow("job"); r.Outcome != OutcomeSuccess {
+		t.Errorf("expected the run outside any budget, got %q", r.Outcome)
+	}
+	if usage := cron.BudgetUsage(); len(usage) != 0 {
+		t.Errorf("expected no budget, got %v", usage)
+	}
+}
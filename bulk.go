@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"errors"
+	"path"
+	"sync"
+)
+
+// ErrEmptySelector is returned by Bulk for a Selector that would pick every
+// entry.
+var ErrEmptySelector = errors.New("scheduler: a bulk operation needs a name pattern or a tag")
+
+// Pause disables the named entry: its triggers are ignored until Resume.
+// Runs already going carry on.
+func (c *Cron) Pause(name string) error {
+	return c.setDisabled(name, true)
+}
+
+// Resume enables the named entry again, after Pause or PanicDisable, and
+// closes its circuit if it is open, see WithCircuitBreaker.
+func (c *Cron) Resume(name string) error {
+	return c.setDisabled(name, false)
+}
+
+func (c *Cron) setDisabled(name string, disabled bool) error {
+	found := false
+	c.inLoop(func() {
+		if e := c.lookup(name); e != nil {
+			found = true
+			c.mu.Lock()
+			e.Disabled = disabled
+			// Either way the circuit no longer decides.
+			e.closeCircuit()
+			c.mu.Unlock()
+		}
+	})
+	if !found {
+		return ErrNoSuchEntry
+	}
+	return nil
+}
+
+// Selector picks the entries a bulk operation applies to: those whose name
+// matches the Name pattern, in the syntax of path.Match, and that have the
+// Tag. An empty field matches any entry, but not both.
+type Selector struct {
+	Name string
+	Tag  string
+}
+
+// matches reports whether the selector picks e.
+func (s Selector) matches(e *Entry) bool {
+	if s.Tag != "" && !e.HasTag(s.Tag) {
+		return false
+	}
+	if s.Name == "" {
+		return true
+	}
+	ok, _ := path.Match(s.Name, e.Name)
+	return ok
+}
+
+// BulkOp is what Bulk does to each entry.
+type BulkOp string
+
+const (
+	BulkPause  BulkOp = "pause"
+	BulkResume BulkOp = "resume"
+	BulkRemove BulkOp = "remove"
+
+	// Run the entries now, see RunNow. The runs go concurrently.
+	BulkRun BulkOp = "run"
+)
+
+// BulkResult is the result of a bulk operation on one entry.
+type BulkResult struct {
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+
+	// The run, for BulkRun.
+	Run *RunRecord `json:"run,omitempty"`
+}
+
+// Bulk applies op to the entries s picks and returns the result of each, in
+// the order of Entries.
+func (c *Cron) Bulk(op BulkOp, s Selector) ([]BulkResult, error) {
+	if s.Name == "" && s.Tag == "" {
+		return nil, ErrEmptySelector
+	}
+	if _, err := path.Match(s.Name, ""); err != nil {
+		return nil, err
+	}
+	var apply func(name string, r *BulkResult) error
+	switch op {
+	case BulkPause:
+		apply = func(name string, _ *BulkResult) error { return c.Pause(name) }
+	case BulkResume:
+		apply = func(name string, _ *BulkResult) error { return c.Resume(name) }
+	case BulkRemove:
+		apply = func(name string, _ *BulkResult) error {
+			c.RemoveJob(name)
+			return nil
+		}
+	case BulkRun:
+		apply = func(name string, r *BulkResult) error {
+			record, err := c.RunNow(name)
+			if err == nil {
+				r.Run = &record
+			}
+			return err
+		}
+	default:
+		return nil, errors.New("scheduler: unknown bulk operation " + string(op))
+	}
+
+	results := []BulkResult{}
+	for _, e := range c.Entries() {
+		if s.matches(e) {
+			results = append(results, BulkResult{Name: e.Name})
+		}
+	}
+	var wg sync.WaitGroup
+	for i := range results {
+		r := &results[i]
+		do := func() {
+			if err := apply(r.Name, r); err != nil {
+				r.Error = err.Error()
+			}
+		}
+		if op != BulkRun {
+			do()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			do()
+		}()
+	}
+	wg.Wait()
+	return results, nil
+}
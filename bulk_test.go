@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBulk(t *testing.T) {
+	cron := New()
+	for _, name := range []string{"etl-users", "etl-orders", "report"} {
+		cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, name, WithTags("nightly"))
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	results, err := cron.Bulk(BulkPause, Selector{Name: "etl-*"})
+	if err != nil || len(results) != 2 {
+		t.Fatalf("expected the two etl entries paused, got %v, %v", results, err)
+	}
+	for _, e := range cron.Entries() {
+		if e.Disabled != (e.Name != "report") {
+			t.Errorf("%s: unexpected Disabled %v", e.Name, e.Disabled)
+		}
+	}
+
+	results, _ = cron.Bulk(BulkRun, Selector{Tag: "nightly"})
+	if len(results) != 3 {
+		t.Fatalf("expected a result for each nightly entry, got %v", results)
+	}
+	for _, r := range results {
+		if paused := r.Name != "report"; paused != (r.Error != "") || paused == (r.Run != nil) {
+			t.Errorf("%s: expected paused entries not to run, got %+v", r.Name, r)
+		}
+	}
+
+	cron.Bulk(BulkResume, Selector{Name: "etl-*"})
+	cron.Bulk(BulkRemove, Selector{Name: "etl-users"})
+	if entries := cron.Entries(); len(entries) != 2 || entries[0].Disabled || entries[1].Disabled {
+		t.Errorf("expected two enabled entries left, got %v", entries)
+	}
+
+	if _, err := cron.Bulk(BulkRemove, Selector{}); err != ErrEmptySelector {
+		t.Errorf("expected ErrEmptySelector, got %v", err)
+	}
+	if _, err := cron.Bulk("explode", Selector{Tag: "nightly"}); err == nil {
+		t.Error("expected an unknown operation to fail")
+	}
+}
+
+func TestAdminBulk(t *testing.T) {
+	cron := New()
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "a", WithTags("batch"))
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "b", WithTags("batch"))
+	cron.Start()
+	defer cron.Stop()
+	admin := NewAdmin(cron, TokenAuth(map[string]Role{"r": RoleReader, "op": RoleOperator}))
+
+	if w := adminRequest(t, admin, "POST", "/bulk/pause?tag=batch", "r"); w.Code != http.StatusForbidden {
+		t.Errorf("expected readers not to pause, got %d", w.Code)
+	}
+	if w := adminRequest(t, admin, "POST", "/bulk/pause", "op"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected an empty selector to be refused, got %d", w.Code)
+	}
+	w := adminRequest(t, admin, "POST", "/bulk/pause?tag=batch", "op")
+	var results []BulkResult
+	if err := json.Unmarshal(w.Body.Bytes(), &results); err != nil || len(results) != 2 {
+		t.Fatalf("expected two results, got %d %s", w.Code, w.Body)
+	}
+	for _, e := range cron.Entries() {
+		if !e.Disabled {
+			t.Errorf("expected %s paused", e.Name)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotDispatched is returned by RunNow when the entry doesn't take the
+// run: it is disabled, retired, or has too many runs pending.
+var ErrNotDispatched = errors.New("scheduler: run was not dispatched")
+
+// WithResultCache marks the job of the entry as idempotent: a run triggered
+// within fresh of the end of the last successful one, such as a manual run
+// right after a scheduled one, is skipped and recorded as OutcomeCached.
+// RunNow then returns the record of that last successful run.
+func WithResultCache(fresh time.Duration) EntryOption {
+	return func(e *Entry) {
+		e.CacheFor = fresh
+	}
+}
+
+// RunNow runs the named entry right away, through the usual dispatch, and
+// waits for the run to be over. It returns the record of the run, which for
+// a run skipped by the result cache is the cached one. If the next scheduled
+// run of the entry is due within the dedup window, the two are coalesced,
+// see WithRunNowDedup.
+func (c *Cron) RunNow(name string) (RunRecord, error) {
+	done := make(chan RunRecord, 1)
+	if err := c.runNow(name, done); err != nil {
+		return RunRecord{}, err
+	}
+	return <-done, nil
+}
+
+// runNow dispatches a manual run of the named entry, whose record goes to
+// done unless it is nil.
+func (c *Cron) runNow(name string, done chan RunRecord) error {
+	found, taken := false, false
+	c.inLoop(func() {
+		if e := c.lookup(name); e != nil {
+			found = true
+			t := trigger{scheduled: time.Now(), manual: true, done: done}
+			imminent := c.imminent(e)
+			if imminent {
+				t.scheduled, t.coalesced, t.reason = e.NextTime, true, ReasonCoalesced
+			}
+			taken = c.dispatchTrigger(e, t)
+			if taken && imminent {
+				c.log(SubsystemDispatch).Info("manual run coalesced with the scheduled one", "entry", e.Name, "scheduled", t.scheduled)
+				e.Next()
+				c.reschedule(e)
+			}
+		}
+	})
+	if !found {
+		return ErrNoSuchEntry
+	}
+	if !taken {
+		return ErrNotDispatched
+	}
+	return nil
+}
+
+// imminent reports whether the next scheduled run of e is due within the
+// dedup window of RunNow. It is called from the run loop.
+func (c *Cron) imminent(e *Entry) bool {
+	return c.runNowDedup > 0 && !e.NextTime.IsZero() && e.index >= 0 &&
+		time.Until(e.NextTime) <= c.runNowDedup
+}
+
+// cachedResult returns the last successful run of the entry, if the result
+// cache says it is fresh enough to skip the upcoming one.
+func (c *Cron) cachedResult(e *Entry) (RunRecord, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e.CacheFor <= 0 || e.lastSuccess == nil || time.Since(e.lastSuccess.End) > e.CacheFor {
+		return RunRecord{}, false
+	}
+	return *e.lastSuccess, true
+}
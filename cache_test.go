@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunNow(t *testing.T) {
+	cron := New()
+	cron.AddProgressFunc(time.Now().Add(time.Hour), time.Hour, func(p *Progress) {
+		fmt.Fprint(p.Output(), "done")
+	}, "job")
+	cron.Start()
+	defer cron.Stop()
+
+	r, err := cron.RunNow("job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Manual || r.Outcome != OutcomeSuccess || r.Output != "done" {
+		t.Errorf("unexpected record %+v", r)
+	}
+	if _, err := cron.RunNow("missing"); err != ErrNoSuchEntry {
+		t.Errorf("expected ErrNoSuchEntry, got %v", err)
+	}
+}
+
+func TestResultCache(t *testing.T) {
+	cron := New()
+	var runs int32
+	cron.AddProgressFunc(time.Now().Add(50*time.Millisecond), time.Hour, func(p *Progress) {
+		fmt.Fprint(p.Output(), atomic.AddInt32(&runs, 1))
+	}, "idempotent", WithResultCache(300*time.Millisecond))
+	cron.Start()
+	defer cron.Stop()
+
+	scheduled := waitForHistory(t, cron, "idempotent", 1)[0]
+	r, err := cron.RunNow("idempotent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Cached || r.Output != "1" || !r.End.Equal(scheduled.End) {
+		t.Errorf("expected the cached result of the scheduled run, got %+v", r)
+	}
+	if n := atomic.LoadInt32(&runs); n != 1 {
+		t.Errorf("expected the job to run once, got %d", n)
+	}
+	if last := cron.History("idempotent")[1]; last.Outcome != OutcomeCached {
+		t.Errorf("expected the skip in the history, got %q", last.Outcome)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if r, _ := cron.RunNow("idempotent"); r.Cached || r.Output != "2" {
+		t.Errorf("expected a fresh run once the cache went stale, got %+v", r)
+	}
+}
+
+func TestRunNowDedup(t *testing.T) {
+	var runs atomic.Int32
+	cron := New(WithRunNowDedup(time.Second))
+	next := time.Now().Add(300 * time.Millisecond)
+	cron.AddFunc(next, time.Hour, func() { runs.Add(1) }, "job")
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "later")
+	cron.Start()
+	defer cron.Stop()
+
+	r, err := cron.RunNow("job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Coalesced || !r.Manual || !r.Scheduled.Equal(next) {
+		t.Errorf("expected a run coalesced with the one due at %v, got %+v", next, r)
+	}
+	if r, _ := cron.RunNow("later"); r.Coalesced {
+		t.Errorf("run far from the schedule coalesced: %+v", r)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	if n := runs.Load(); n != 1 {
+		t.Errorf("expected a single run, got %d", n)
+	}
+	for _, e := range cron.Entries() {
+		if e.Name == "job" && !e.NextTime.Equal(next.Add(time.Hour)) {
+			t.Errorf("expected the schedule to move on to %v, got %v", next.Add(time.Hour), e.NextTime)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package scheduler
+
+import "math/rand"
+
+// Canary rolls an updated definition out gradually, see WithCanary.
+type Canary struct {
+	// Percentage of the triggers, between 0 and 100, that run the new job
+	// while the previous one keeps running the others. Zero means trial
+	// runs: a trigger runs the new job, and the previous one runs the
+	// triggers that come while that trial is going.
+	Percent float64
+
+	// How many runs of the new job must succeed before it is promoted, at
+	// least one.
+	Runs int
+}
+
+// WithCanary makes an update of an existing entry a canary: the job of the
+// previous definition keeps running alongside the new one until the new one
+// has succeeded c.Runs times, when it is promoted and runs every trigger. A
+// failed run of the new job rolls the entry back to the previous definition.
+// The other settings of the entry are those of the new definition from the
+// start. Adding a new entry with WithCanary runs it as usual.
+func WithCanary(c Canary) EntryOption {
+	if c.Runs < 1 {
+		c.Runs = 1
+	}
+	return func(e *Entry) {
+		e.Canary = &c
+	}
+}
+
+// canaryState is the previous definition an entry keeps running while its
+// new one is a canary.
+type canaryState struct {
+	stable *Entry
+
+	// Successful runs of the new job so far, whether the trial run of a
+	// zero percent canary is going, and whether a run of the new job failed.
+	passed int
+	trial  bool
+	failed bool
+}
+
+// startCanary makes entry with a Canary a canary of prev, the definition it
+// replaces.
+func startCanary(entry, prev *Entry) {
+	if entry.Canary == nil || entry.rollback {
+		return
+	}
+	stable := prev
+	if prev.canary != nil {
+		stable = prev.canary.stable
+	}
+	entry.canary = &canaryState{stable: stable.definition()}
+	entry.Stable = stable.Version
+}
+
+// pickCanary decides whether trigger t of e runs the new job or the stable
+// one, setting it up on its view. The caller must hold c.mu.
+func (e *Entry) pickCanary(t *trigger) {
+	cs := e.canary
+	if cs == nil {
+		return
+	}
+	if cs.failed {
+		t.canary = false
+	} else if e.Canary.Percent > 0 {
+		t.canary = rand.Float64()*100 < e.Canary.Percent
+	} else if !cs.trial {
+		t.canary, cs.trial = true, true
+	}
+	if !t.canary {
+		t.view.Job = cs.stable.Job
+		t.view.Version = cs.stable.Version
+	}
+}
+
+// canaryDone accounts for a run of the new job of e that ended with outcome.
+// It returns whether that promoted the job, or else the version to roll back
+// to if the canary failed. The caller must hold c.mu.
+func (e *Entry) canaryDone(outcome Outcome) (promoted bool, rollbackTo int) {
+	cs := e.canary
+	if cs == nil || cs.failed {
+		return false, 0
+	}
+	cs.trial = false
+	switch {
+	case outcome == OutcomeSuccess:
+	case outcome.skipped():
+		// The new job didn't run, so it neither passed nor failed.
+		return false, 0
+	default:
+		cs.failed = true
+		return false, cs.stable.Version
+	}
+	cs.passed++
+	if cs.passed >= e.Canary.Runs {
+		e.canary = nil
+		e.Stable = 0
+		return true, 0
+	}
+	return false, 0
+}
+
+// canaryOver is called once a run of the new job of e is over, to account for
+// it with canaryDone and then promote the job or roll it back.
+func (c *Cron) canaryOver(e *Entry, t trigger, outcome Outcome) {
+	if !t.canary {
+		return
+	}
+	c.mu.Lock()
+	promoted, rollbackTo := e.canaryDone(outcome)
+	c.mu.Unlock()
+	switch {
+	case promoted:
+		c.log(SubsystemLifecycle).Info("canary promoted", "entry", e.Name, "version", t.view.Version)
+		c.emit(Event{Type: EventPromoted, Name: e.Name, Version: t.view.Version, Metadata: t.view.Metadata})
+	case rollbackTo != 0:
+		c.log(SubsystemLifecycle).Info("canary failed, rolling back", "entry", e.Name, "version", t.view.Version, "to", rollbackTo)
+		// Rolling back goes through the run loop, which may be waiting on
+		// this run to stop.
+		go c.Rollback(e.Name, rollbackTo)
+	}
+}
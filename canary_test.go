@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+// waitForEvent waits for an event of the given type and returns it.
+func waitForEvent(t *testing.T, events <-chan Event, typ EventType) Event {
+	t.Helper()
+	timeout := time.After(2 * ONE_SECOND)
+	for {
+		select {
+		case e := <-events:
+			if e.Type == typ {
+				return e
+			}
+		case <-timeout:
+			t.Fatalf("no %s event", typ)
+		}
+	}
+}
+
+func TestCanaryPromoted(t *testing.T) {
+	events := make(chan Event, 100)
+	cron := New(WithEventHandler(func(e Event) { events <- e }))
+	ran := make(chan string, 10)
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() { ran <- "v1" }, "job")
+	cron.Start()
+	defer cron.Stop()
+
+	cron.UpdateJob(time.Now().Add(time.Hour), time.Hour, FuncJob(func() { ran <- "v2" }), "job", WithCanary(Canary{Runs: 2}))
+	if stable := cron.Entries()[0].Stable; stable != 1 {
+		t.Fatalf("expected version 1 to stay as the stable one, got %d", stable)
+	}
+	for i := 0; i < 2; i++ {
+		r, err := cron.RunNow("job")
+		if err != nil || r.Outcome != OutcomeSuccess || r.Version != 2 {
+			t.Fatalf("expected a successful trial of version 2, got %+v (%v)", r, err)
+		}
+	}
+	if e := waitForEvent(t, events, EventPromoted); e.Version != 2 {
+		t.Errorf("expected version 2 to be promoted, got %d", e.Version)
+	}
+	if stable := cron.Entries()[0].Stable; stable != 0 {
+		t.Errorf("expected no stable version once promoted, got %d", stable)
+	}
+}
+
+// With a percentage, the previous job keeps the other triggers.
+func TestCanaryPercent(t *testing.T) {
+	cron := New()
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "job")
+	cron.Start()
+	defer cron.Stop()
+
+	cron.UpdateJob(time.Now().Add(time.Hour), time.Hour, FuncJob(func() {}), "job", WithCanary(Canary{Percent: 50, Runs: 1000}))
+	versions := map[int]int{}
+	for i := 0; i < 100; i++ {
+		r, _ := cron.RunNow("job")
+		versions[r.Version]++
+	}
+	if versions[1] == 0 || versions[2] == 0 {
+		t.Errorf("expected both versions to run, got %v", versions)
+	}
+}
+
+func TestCanaryFailureRollsBack(t *testing.T) {
+	events := make(chan Event, 100)
+	cron := New(WithEventHandler(func(e Event) { events <- e }))
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "job")
+	cron.Start()
+	defer cron.Stop()
+
+	cron.UpdateJob(time.Now().Add(time.Hour), time.Hour, FuncJob(func() { panic("bad deploy") }), "job", WithCanary(Canary{}))
+	if r, _ := cron.RunNow("job"); r.Outcome != OutcomePanic {
+		t.Fatalf("expected the canary to panic, got %+v", r)
+	}
+	if e := waitForEvent(t, events, EventRolledBack); e.Version != 3 {
+		t.Errorf("expected a rollback to version 3, got %d", e.Version)
+	}
+	if r, _ := cron.RunNow("job"); r.Outcome != OutcomeSuccess {
+		t.Errorf("expected the previous job to run again, got %+v", r)
+	}
+}
@@ -0,0 +1,107 @@
+package scheduler
+
+import "time"
+
+// JobWrapper decorates the job of a run, such as to take a lock or time it
+// around the job. It is handed the Progress of the run, see ProgressJob, so
+// wrappers of plain jobs get it too.
+type JobWrapper func(ProgressJob) ProgressJob
+
+// Chain is a sequence of wrappers, the first being the outermost one, so
+// cross-cutting concerns such as logging, metrics or tracing can be put
+// together once and shared between schedulers and entries:
+//
+//	observed := NewChain(logRuns, timeRuns)
+//	New(WithChain(observed...))
+//	cron.AddJob(..., WithChainOverride(observed.Append(lock)...))
+type Chain []JobWrapper
+
+// NewChain returns a chain of the given wrappers.
+func NewChain(wrappers ...JobWrapper) Chain {
+	return append(Chain(nil), wrappers...)
+}
+
+// Append returns the chain with the given wrappers inside it, leaving ch as
+// it is.
+func (ch Chain) Append(wrappers ...JobWrapper) Chain {
+	return append(append(Chain(nil), ch...), wrappers...)
+}
+
+// Then returns j wrapped in the chain.
+func (ch Chain) Then(j ProgressJob) ProgressJob {
+	for i := len(ch) - 1; i >= 0; i-- {
+		j = ch[i](j)
+	}
+	return j
+}
+
+// DelayIfStillRunning returns a wrapper holding each run of the jobs it
+// wraps back until the previous one is over, so they run one at a time.
+// OverlapSerialize does so for the runs of one version of an entry; the
+// wrapper, being the same across updates when passed to WithEntryChain
+// again, also keeps a new version from starting while the last run of the
+// old one is still going, and may be shared by entries that must not run at
+// once. A run cancelled while it waits fails with the error of its context.
+func DelayIfStillRunning() JobWrapper {
+	turn := make(chan struct{}, 1)
+	return func(j ProgressJob) ProgressJob {
+		return ProgressFuncJob(func(p *Progress) {
+			select {
+			case turn <- struct{}{}:
+			default:
+				start := time.Now()
+				select {
+				case turn <- struct{}{}:
+				case <-p.Context().Done():
+					p.fail(p.Context().Err())
+					return
+				}
+				p.Logger().Info("run delayed behind the previous one", "delay", time.Since(start))
+			}
+			defer func() { <-turn }()
+			j.Run(p)
+		})
+	}
+}
+
+// WithEntryChain wraps the job of the entry in the given wrappers, inside
+// those of the scheduler, see WithChain. The first wrapper is the outermost
+// one, so the chain of
+//
+//	New(WithChain(a, b)).AddJob(..., WithEntryChain(c, d))
+//
+// runs a(b(c(d(job)))).
+func WithEntryChain(wrappers ...JobWrapper) EntryOption {
+	return func(e *Entry) {
+		e.Chain = append(e.Chain, wrappers...)
+	}
+}
+
+// WithChainOverride wraps the job of the entry in the given wrappers in
+// place of those of the scheduler, such as to leave out one that doesn't
+// suit it. With none, the job of the entry runs bare.
+func WithChainOverride(wrappers ...JobWrapper) EntryOption {
+	return func(e *Entry) {
+		e.Chain = NewChain(wrappers...)
+		e.ChainOverride = true
+	}
+}
+
+// chain returns j wrapped in the chain of the scheduler, unless the entry
+// overrides it, and that of the entry.
+func (c *Cron) chain(e *Entry, j Job) Job {
+	wrappers := c.wrappers
+	if e.ChainOverride {
+		wrappers = nil
+	}
+	if len(wrappers) == 0 && len(e.Chain) == 0 {
+		return j
+	}
+	var pj ProgressJob
+	if p, ok := j.(progressJob); ok {
+		pj = p.job
+	} else {
+		pj = ProgressFuncJob(func(*Progress) { j.Run() })
+	}
+	return progressJob{wrappers.Then(e.Chain.Then(pj))}
+}
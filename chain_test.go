@@ -0,0 +1,130 @@
+package scheduler
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestChainOrder(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	trace := func(name string) JobWrapper {
+		return func(j ProgressJob) ProgressJob {
+			return ProgressFuncJob(func(p *Progress) {
+				mu.Lock()
+				calls = append(calls, name)
+				mu.Unlock()
+				j.Run(p)
+			})
+		}
+	}
+	cron := New(WithChain(trace("a"), trace("b")))
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {
+		mu.Lock()
+		calls = append(calls, "job")
+		mu.Unlock()
+	}, "job", WithEntryChain(trace("c"), trace("d")))
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "plain")
+	cron.Start()
+	defer cron.Stop()
+
+	if _, err := cron.RunNow("job"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cron.RunNow("plain"); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.Join(calls, ","); got != "a,b,c,d,job,a,b" {
+		t.Errorf("expected the global chain around the entry chain, got %s", got)
+	}
+}
+
+// Wrappers hand progress jobs their Progress.
+func TestChainKeepsProgress(t *testing.T) {
+	cron := New(WithChain(func(j ProgressJob) ProgressJob { return j }))
+	cron.AddProgressFunc(time.Now().Add(time.Hour), time.Hour, func(p *Progress) {
+		p.Report(50, "halfway")
+	}, "job")
+	cron.Start()
+	defer cron.Stop()
+
+	cron.RunNow("job")
+	if got := cron.Entries()[0].Progress; got.Percent != 50 || got.Message != "halfway" {
+		t.Errorf("expected the job's progress to be reported, got %+v", got)
+	}
+}
+
+func TestChainOverride(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	trace := func(name string) JobWrapper {
+		return func(j ProgressJob) ProgressJob {
+			return ProgressFuncJob(func(p *Progress) {
+				mu.Lock()
+				calls = append(calls, name)
+				mu.Unlock()
+				j.Run(p)
+			})
+		}
+	}
+	base := NewChain(trace("a"), trace("b"))
+	cron := New(WithChain(base...))
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "own", WithChainOverride(base[1:].Append(trace("c"))...))
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "bare", WithChainOverride())
+	cron.Start()
+	defer cron.Stop()
+
+	for _, name := range []string{"own", "bare"} {
+		if _, err := cron.RunNow(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.Join(calls, ","); got != "b,c" {
+		t.Errorf("expected the entry chains in place of the global one, got %s", got)
+	}
+	if len(base) != 2 {
+		t.Errorf("expected Append to leave the chain as it is, got %d wrappers", len(base))
+	}
+}
+
+func TestDelayIfStillRunning(t *testing.T) {
+	var mu sync.Mutex
+	going, most := 0, 0
+	job := func() {
+		mu.Lock()
+		going++
+		most = max(most, going)
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		going--
+		mu.Unlock()
+	}
+	serial := DelayIfStillRunning()
+	cron := New()
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, job, "a", WithEntryChain(serial))
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, job, "b", WithEntryChain(serial))
+	cron.Start()
+	defer cron.Stop()
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"a", "b", "a"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if _, err := cron.RunNow(name); err != nil {
+				t.Error(err)
+			}
+		}(name)
+	}
+	wg.Wait()
+	if most != 1 {
+		t.Errorf("expected the runs one at a time, got %d at once", most)
+	}
+}
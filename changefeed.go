@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefinitionChange is a change of what the scheduler will run, as sent to a
+// ChangeSink: an entry was added, updated, rolled back, removed or retired.
+type DefinitionChange struct {
+	Type    EventType `json:"type"`
+	Time    time.Time `json:"time"`
+	Name    string    `json:"name"`
+	Version int       `json:"version"`
+
+	// The definition, for the changes that leave the entry on the schedule.
+	Definition *DefinitionDoc `json:"definition,omitempty"`
+}
+
+// DefinitionDoc is the definition of an entry, as far as it can be told
+// outside the process.
+type DefinitionDoc struct {
+	Start     time.Time              `json:"start"`
+	Interval  time.Duration          `json:"interval,omitempty"`
+	Schedule  string                 `json:"schedule,omitempty"`
+	Kind      string                 `json:"kind,omitempty"`
+	Params    map[string]interface{} `json:"params,omitempty"`
+	Tags      []string               `json:"tags,omitempty"`
+	Namespace string                 `json:"namespace,omitempty"`
+	Metadata  map[string]string      `json:"metadata,omitempty"`
+}
+
+// ChangeSink receives the changes of the definitions of the entries, see
+// WithChangeFeed. A Kafka producer, for instance, publishes them to a topic.
+type ChangeSink interface {
+	Publish(DefinitionChange) error
+}
+
+// ChangeSinkFunc is a ChangeSink calling itself.
+type ChangeSinkFunc func(DefinitionChange) error
+
+func (f ChangeSinkFunc) Publish(c DefinitionChange) error { return f(c) }
+
+// WebhookSink is a ChangeSink POSTing each change as JSON to a URL. Any
+// status but a 2xx is an error.
+type WebhookSink struct {
+	URL string
+
+	// The client to POST with, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+func (s WebhookSink) Publish(c DefinitionChange) error {
+	body, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Post(s.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("scheduler: webhook %s: %s", s.URL, resp.Status)
+	}
+	return nil
+}
+
+// How many times a change is retried before it is dropped, and the backoff
+// before the first retry, doubled for each further one.
+const (
+	changeRetries = 3
+	changeBackoff = 100 * time.Millisecond
+)
+
+// changeFeed hands the changes over to the sink, in order, from a goroutine
+// of its own.
+type changeFeed struct {
+	sink    ChangeSink
+	changes chan DefinitionChange
+}
+
+// publishChange sends a change of e to the change feed, if any: e is the new
+// definition, or for a removal the last one.
+func (c *Cron) publishChange(typ EventType, e *Entry) {
+	f := c.changeFeed
+	if f == nil {
+		return
+	}
+	change := DefinitionChange{Type: typ, Time: time.Now(), Name: e.Name, Version: e.Version}
+	if typ != EventRemoved && typ != EventRetired {
+		change.Definition = definitionDoc(e)
+	}
+	c.eventsMu.Lock()
+	if f.changes == nil {
+		f.changes = make(chan DefinitionChange, eventBuffer)
+		go c.feed(f.changes)
+	}
+	c.eventsMu.Unlock()
+	f.changes <- change
+}
+
+// feed publishes the changes, retrying those that fail.
+func (c *Cron) feed(changes <-chan DefinitionChange) {
+	for change := range changes {
+		backoff := changeBackoff
+		for attempt := 0; ; attempt++ {
+			err := c.changeFeed.sink.Publish(change)
+			if err == nil {
+				break
+			}
+			if attempt == changeRetries {
+				c.log(SubsystemLifecycle).Error(err, "publishing definition change failed, dropped", "entry", change.Name, "type", change.Type)
+				break
+			}
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+}
+
+// definitionDoc returns the definition of e for the change feed.
+func definitionDoc(e *Entry) *DefinitionDoc {
+	d := &DefinitionDoc{
+		Start:     e.setStartTime,
+		Interval:  e.Interval,
+		Kind:      e.Kind,
+		Params:    e.Params,
+		Tags:      append([]string(nil), e.Tags...),
+		Namespace: e.Namespace,
+		Metadata:  cloneMetadata(e.Metadata),
+	}
+	if s, ok := e.Schedule.(fmt.Stringer); ok {
+		d.Schedule = s.String()
+	} else if e.Schedule != nil {
+		d.Schedule = fmt.Sprintf("%T", e.Schedule)
+	}
+	return d
+}
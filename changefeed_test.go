@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestChangeFeed(t *testing.T) {
+	changes := make(chan DefinitionChange, 10)
+	cron := New(WithChangeFeed(ChangeSinkFunc(func(c DefinitionChange) error {
+		changes <- c
+		return nil
+	})))
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "job", WithTags("nightly"))
+	cron.Start()
+	defer cron.Stop()
+	cron.AddFunc(time.Now().Add(time.Hour), 2*time.Hour, func() {}, "job")
+	cron.RemoveJob("job")
+
+	for _, want := range []struct {
+		typ     EventType
+		version int
+	}{{EventAdded, 1}, {EventUpdated, 2}, {EventRemoved, 2}} {
+		select {
+		case c := <-changes:
+			if c.Type != want.typ || c.Name != "job" || c.Version != want.version {
+				t.Errorf("expected %s of version %d, got %+v", want.typ, want.version, c)
+			}
+			if (c.Definition == nil) != (want.typ == EventRemoved) {
+				t.Errorf("%s: unexpected definition %+v", c.Type, c.Definition)
+			}
+			if c.Type == EventUpdated && c.Definition.Interval != 2*time.Hour {
+				t.Errorf("expected the new interval, got %v", c.Definition.Interval)
+			}
+		case <-time.After(ONE_SECOND):
+			t.Fatalf("no %s change", want.typ)
+		}
+	}
+}
+
+func TestChangeFeedRetries(t *testing.T) {
+	var calls atomic.Int32
+	published := make(chan struct{})
+	cron := New(WithChangeFeed(ChangeSinkFunc(func(c DefinitionChange) error {
+		if calls.Add(1) < 3 {
+			return errors.New("unavailable")
+		}
+		close(published)
+		return nil
+	})))
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "job")
+
+	select {
+	case <-published:
+	case <-time.After(2 * ONE_SECOND):
+		t.Fatalf("change not published after %d calls", calls.Load())
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	got := make(chan DefinitionChange, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var c DefinitionChange
+		json.NewDecoder(r.Body).Decode(&c)
+		got <- c
+	}))
+	defer srv.Close()
+
+	sink := WebhookSink{URL: srv.URL}
+	if err := sink.Publish(DefinitionChange{Type: EventAdded, Name: "job", Version: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if c := <-got; c.Type != EventAdded || c.Name != "job" {
+		t.Errorf("unexpected change %+v", c)
+	}
+	srv.Config.Handler = http.NotFoundHandler()
+	if err := sink.Publish(DefinitionChange{}); err == nil {
+		t.Error("expected a 404 to be an error")
+	}
+}
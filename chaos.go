@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrInjectedFault is the value injected failures panic with, see Faults.
+var ErrInjectedFault = errors.New("scheduler: injected fault")
+
+// Faults makes the scheduler misbehave on purpose, to check that alerting
+// and retry policies cope with it. Each probability is between 0 and 1 and
+// is drawn independently for every run. It is meant for tests only.
+type Faults struct {
+	// Probability that a wake-up of the run loop is skipped: the entries
+	// that were due move on to their next time without running.
+	Skip float64
+
+	// Probability that a run is held back for Delay before it starts.
+	DelayProbability float64
+	Delay            time.Duration
+
+	// Probability that a run fails: its job is not run and the run panics
+	// with ErrInjectedFault instead, handled as per the PanicPolicy.
+	Fail float64
+
+	// Source of randomness, seeded from the time if nil. Set it to get
+	// repeatable runs.
+	Rand *rand.Rand
+}
+
+// faultInjector draws the faults of a Faults. A nil *faultInjector injects
+// nothing.
+type faultInjector struct {
+	mu     sync.Mutex
+	faults Faults
+}
+
+func newFaultInjector(f Faults) *faultInjector {
+	if f.Rand == nil {
+		f.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return &faultInjector{faults: f}
+}
+
+// draw reports whether a fault of probability p happens.
+func (fi *faultInjector) draw(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	return fi.faults.Rand.Float64() < p
+}
+
+// skip reports whether to skip the current wake-up.
+func (fi *faultInjector) skip() bool {
+	if fi == nil {
+		return false
+	}
+	return fi.draw(fi.faults.Skip)
+}
+
+// delay holds the run back, if it draws a delay.
+func (fi *faultInjector) delay() {
+	if fi != nil && fi.draw(fi.faults.DelayProbability) {
+		time.Sleep(fi.faults.Delay)
+	}
+}
+
+// job returns the job to run in place of j: j itself, or one that fails.
+func (fi *faultInjector) job(j Job) Job {
+	if fi != nil && fi.draw(fi.faults.Fail) {
+		return FuncJob(func() { panic(ErrInjectedFault) })
+	}
+	return j
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFaultsFail(t *testing.T) {
+	cron := New(WithFaults(Faults{Fail: 1}))
+	ran := false
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() { ran = true }, "flaky")
+	cron.Start()
+	defer cron.Stop()
+
+	r := waitForHistory(t, cron, "flaky", 1)[0]
+	if r.Outcome != OutcomePanic || !strings.Contains(r.Output, ErrInjectedFault.Error()) {
+		t.Errorf("expected an injected failure, got %+v", r)
+	}
+	if ran {
+		t.Error("expected the job not to run")
+	}
+}
+
+func TestFaultsDelay(t *testing.T) {
+	cron := New(WithFaults(Faults{DelayProbability: 1, Delay: 200 * time.Millisecond}))
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() {}, "slow")
+	cron.Start()
+	defer cron.Stop()
+
+	r := waitForHistory(t, cron, "slow", 1)[0]
+	if late := r.Start.Sub(r.Scheduled); late < 200*time.Millisecond {
+		t.Errorf("expected the run to be delayed, started %v late", late)
+	}
+}
+
+func TestFaultsSkip(t *testing.T) {
+	cron := New(WithFaults(Faults{Skip: 1}))
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), ONE_SECOND, func() {}, "skipped")
+	cron.Start()
+	defer cron.Stop()
+
+	time.Sleep(ONE_SECOND + 200*time.Millisecond)
+	if n := len(cron.History("skipped")); n != 0 {
+		t.Errorf("expected every wake-up to be skipped, got %d runs", n)
+	}
+	if next := cron.Entries()[0].NextTime; !next.After(time.Now()) {
+		t.Errorf("expected the entry to move on to its next time, got %v", next)
+	}
+}
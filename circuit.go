@@ -0,0 +1,109 @@
+package scheduler
+
+import "time"
+
+// CircuitBreaker pauses an entry whose runs keep failing, see
+// WithCircuitBreaker.
+type CircuitBreaker struct {
+	// How many failed runs in a row open the circuit, at least one. Retries
+	// count as runs of their own.
+	Failures int
+
+	// How long the circuit stays open before the next run is let through as
+	// a probe. Zero keeps it open until Resume.
+	CoolDown time.Duration
+}
+
+// CircuitState is the state of the circuit breaker of an entry.
+type CircuitState string
+
+const (
+	// The entry runs as usual. It is the state of entries without a
+	// breaker too.
+	CircuitClosed CircuitState = ""
+
+	// The runs failed too many times in a row, and the triggers of the entry
+	// are ignored. It is apart from Entry.Disabled, which Pause sets.
+	CircuitOpen CircuitState = "open"
+
+	// The cool-down is over and the next run is a probe: it closes the
+	// circuit if it succeeds and opens it again if it fails.
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// WithCircuitBreaker pauses the entry once b.Failures of its runs in a row
+// failed, emitting EventCircuitOpened, and lets a probe run through after
+// b.CoolDown. Resume closes the circuit at once.
+func WithCircuitBreaker(b CircuitBreaker) EntryOption {
+	if b.Failures < 1 {
+		b.Failures = 1
+	}
+	return func(e *Entry) {
+		e.Breaker = &b
+	}
+}
+
+// breakCircuit accounts for a run of e that ended with outcome, opening or
+// closing its circuit.
+func (c *Cron) breakCircuit(e *Entry, t trigger, outcome Outcome) {
+	if e.Breaker == nil || outcome.skipped() {
+		return
+	}
+	var event EventType
+	c.mu.Lock()
+	if outcome.failed() {
+		e.failures++
+		if e.Circuit == CircuitHalfOpen || e.Circuit == CircuitClosed && e.failures >= e.Breaker.Failures {
+			event = EventCircuitOpened
+			e.Circuit = CircuitOpen
+			if e.Breaker.CoolDown > 0 {
+				e.coolDown = time.AfterFunc(e.Breaker.CoolDown, func() { c.halfOpen(e) })
+			}
+		}
+	} else {
+		e.failures = 0
+		if e.Circuit == CircuitHalfOpen {
+			event = EventCircuitClosed
+			e.Circuit = CircuitClosed
+		}
+	}
+	failures := e.failures
+	c.mu.Unlock()
+	switch event {
+	case EventCircuitOpened:
+		c.log(SubsystemLifecycle).Info("runs keep failing, circuit opened", "entry", e.Name, "failures", failures, "cool_down", e.Breaker.CoolDown)
+	case EventCircuitClosed:
+		c.log(SubsystemLifecycle).Info("probe run succeeded, circuit closed", "entry", e.Name)
+	default:
+		return
+	}
+	c.emit(Event{Type: event, Name: e.Name, Version: t.view.Version, Metadata: t.view.Metadata})
+}
+
+// halfOpen lets the next run of e through once its cool-down is over.
+func (c *Cron) halfOpen(e *Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e.Circuit != CircuitOpen {
+		return
+	}
+	c.log(SubsystemLifecycle).Info("cool-down over, circuit half open", "entry", e.Name)
+	e.Circuit = CircuitHalfOpen
+}
+
+// closeCircuit closes the circuit of e, without a probe. The caller must
+// hold c.mu.
+func (e *Entry) closeCircuit() {
+	e.stopCoolDown()
+	e.failures = 0
+	e.Circuit = CircuitClosed
+}
+
+// stopCoolDown stops the cool-down of the open circuit of e, if any. The
+// caller must hold c.mu.
+func (e *Entry) stopCoolDown() {
+	if e.coolDown != nil {
+		e.coolDown.Stop()
+		e.coolDown = nil
+	}
+}
@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	events := make(chan Event, 100)
+	cron := New(WithEventHandler(func(e Event) { events <- e }))
+	var healthy atomic.Bool
+	cron.AddFunc(time.Now(), 50*time.Millisecond, func() {
+		if !healthy.Load() {
+			panic("down")
+		}
+	}, "flaky", WithCircuitBreaker(CircuitBreaker{Failures: 2, CoolDown: 200 * time.Millisecond}))
+	cron.Start()
+	defer cron.Stop()
+
+	waitForEvent(t, events, EventCircuitOpened)
+	e, _ := cron.Entry("flaky")
+	if e.Disabled || e.Circuit != CircuitOpen {
+		t.Fatalf("expected the circuit open, apart from Disabled, got %v, %q", e.Disabled, e.Circuit)
+	}
+	if n := len(waitForHistory(t, cron, "flaky", 2)); n != 2 {
+		t.Errorf("expected 2 runs before the circuit opened, got %d", n)
+	}
+
+	// The probe after the cool-down fails, which opens the circuit again.
+	waitForEvent(t, events, EventCircuitOpened)
+	if n := len(waitForHistory(t, cron, "flaky", 3)); n != 3 {
+		t.Errorf("expected a single probe run, got %d runs", n)
+	}
+
+	healthy.Store(true)
+	waitForEvent(t, events, EventCircuitClosed)
+	if e, _ := cron.Entry("flaky"); e.Disabled || e.Circuit != CircuitClosed {
+		t.Errorf("expected the entry running again, got %v, %q", e.Disabled, e.Circuit)
+	}
+}
+
+func TestCircuitResume(t *testing.T) {
+	cron := New()
+	cron.AddFunc(time.Now(), 50*time.Millisecond, func() { panic("down") }, "broken",
+		WithCircuitBreaker(CircuitBreaker{Failures: 1}))
+	cron.Start()
+	defer cron.Stop()
+
+	waitForHistory(t, cron, "broken", 1)
+	time.Sleep(150 * time.Millisecond)
+	if n := len(cron.History("broken")); n != 1 {
+		t.Fatalf("expected the circuit to stay open without a cool-down, got %d runs", n)
+	}
+	if err := cron.Resume("broken"); err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := cron.Entry("broken"); e.Disabled || e.Circuit != CircuitClosed {
+		t.Errorf("expected Resume to close the circuit, got %v, %q", e.Disabled, e.Circuit)
+	}
+	waitForHistory(t, cron, "broken", 2)
+}
+
+func TestCircuitKeepsPanicDisable(t *testing.T) {
+	events := make(chan Event, 100)
+	cron := New(WithEventHandler(func(e Event) { events <- e }), WithPanicPolicy(PanicDisable))
+	cron.AddFunc(time.Now(), 50*time.Millisecond, func() { panic("down") }, "broken",
+		WithCircuitBreaker(CircuitBreaker{Failures: 1, CoolDown: 100 * time.Millisecond}))
+	cron.Start()
+	defer cron.Stop()
+
+	// The panic both disables the entry and opens its circuit. The end of
+	// the cool-down must not enable it again.
+	waitForEvent(t, events, EventCircuitOpened)
+	time.Sleep(250 * time.Millisecond)
+	e, _ := cron.Entry("broken")
+	if !e.Disabled || e.Circuit != CircuitHalfOpen {
+		t.Fatalf("expected the entry disabled with its circuit half open, got %v, %q", e.Disabled, e.Circuit)
+	}
+	if n := len(cron.History("broken")); n != 1 {
+		t.Errorf("expected no run once disabled, got %d runs", n)
+	}
+}
+
+func TestCircuitCoolDownStoppedOnRemove(t *testing.T) {
+	events := make(chan Event, 100)
+	cron := New(WithEventHandler(func(e Event) { events <- e }))
+	cron.AddFunc(time.Now(), 50*time.Millisecond, func() { panic("down") }, "broken",
+		WithCircuitBreaker(CircuitBreaker{Failures: 1, CoolDown: time.Hour}))
+	cron.Start()
+	defer cron.Stop()
+
+	waitForEvent(t, events, EventCircuitOpened)
+	var e *Entry
+	cron.inLoop(func() { e = cron.lookup("broken") })
+	cron.RemoveJob("broken")
+	cron.mu.Lock()
+	defer cron.mu.Unlock()
+	if e.coolDown != nil {
+		t.Error("expected the cool-down stopped once the entry is removed")
+	}
+}
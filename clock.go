@@ -0,0 +1,140 @@
+package scheduler
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNoFakeClock is returned by AdvanceTo on a scheduler that doesn't run on
+// a FakeClock.
+var ErrNoFakeClock = errors.New("scheduler: AdvanceTo needs a FakeClock, see WithClock")
+
+// Clock tells the run loop of the scheduler the time and wakes it up, see
+// WithClock. Runs are still timed by the real clock.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the Clock of the system, the default.
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// FakeClock is a Clock that only moves when told to, for tests and Replay.
+type FakeClock struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	waiters []fakeWaiter
+
+	// How many times After was called and when the channel it last returned
+	// goes off, see awaitAfter.
+	afters int
+	last   time.Time
+}
+
+type fakeWaiter struct {
+	at time.Time
+	c  chan time.Time
+}
+
+// NewFakeClock returns a FakeClock showing now.
+func NewFakeClock(now time.Time) *FakeClock {
+	f := &FakeClock{now: now}
+	f.changed = sync.NewCond(&f.mu)
+	return f
+}
+
+// Now returns the time the clock shows.
+func (f *FakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// After returns a channel that gets the time once the clock has moved d on.
+func (f *FakeClock) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := make(chan time.Time, 1)
+	f.last = f.now
+	if d <= 0 {
+		c <- f.now
+	} else {
+		f.last = f.now.Add(d)
+		f.waiters = append(f.waiters, fakeWaiter{at: f.last, c: c})
+	}
+	f.afters++
+	f.changed.Broadcast()
+	return c
+}
+
+// Advance moves the clock d on.
+func (f *FakeClock) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set moves the clock to t, waking up the waiters due by then. The clock
+// never moves back.
+func (f *FakeClock) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if t.After(f.now) {
+		f.now = t
+	}
+	waiters := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.at.After(f.now) {
+			waiters = append(waiters, w)
+			continue
+		}
+		w.c <- f.now
+	}
+	f.waiters = waiters
+}
+
+// awaitAfter waits until After has been called more than n times, and
+// returns how many times it has and when the channel it last returned goes
+// off.
+func (f *FakeClock) awaitAfter(n int) (int, time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for f.afters <= n {
+		f.changed.Wait()
+	}
+	return f.afters, f.last
+}
+
+// AdvanceTo moves the FakeClock of the scheduler on to t, firing the runs
+// due by then as it goes, in order. The runs due at the same time are
+// dispatched together, and waited for before the clock moves on to the next
+// ones, so a test of scheduled behaviour sees the runs of hours of schedule
+// in as long as they take to run. The scheduler is started if need be.
+func (c *Cron) AdvanceTo(t time.Time) error {
+	clock, ok := c.clock.(*FakeClock)
+	if !ok {
+		return ErrNoFakeClock
+	}
+	c.Start()
+	for fired := true; fired; {
+		fired = false
+		c.inLoop(func() {
+			if len(c.entries) == 0 {
+				return
+			}
+			next := c.entries[0].NextTime
+			if next.IsZero() || next.After(t) {
+				return
+			}
+			clock.Set(next)
+			c.fire(next)
+			fired = true
+		})
+		c.runs.Wait()
+	}
+	clock.Set(t)
+	return nil
+}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAdvanceTo(t *testing.T) {
+	t0 := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(t0)
+	cron := New(WithClock(clock), WithLocation(time.UTC))
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func() {
+		return func() {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}
+	}
+	cron.AddFunc(t0.Add(time.Minute), 10*time.Minute, record("often"), "often")
+	cron.AddFunc(t0.Add(5*time.Minute), time.Hour, record("rare"), "rare")
+	defer cron.Stop()
+
+	start := time.Now()
+	if err := cron.AdvanceTo(t0.Add(3 * time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("expected three hours of schedule to take no time, took %v", d)
+	}
+	if n := len(cron.History("often")); n != 18 {
+		t.Errorf("expected 18 runs of often, got %d", n)
+	}
+	runs := cron.History("rare")
+	if len(runs) != 3 || !runs[2].Scheduled.Equal(t0.Add(2*time.Hour+5*time.Minute)) {
+		t.Errorf("expected rare to run at 00:05, 01:05 and 02:05, got %v", runs)
+	}
+	if len(order) < 2 || order[0] != "often" || order[1] != "rare" {
+		t.Errorf("expected the runs in order, got %v", order)
+	}
+	if now := clock.Now(); !now.Equal(t0.Add(3 * time.Hour)) {
+		t.Errorf("expected the clock at 03:00, got %v", now)
+	}
+}
+
+func TestAdvanceToNeedsFakeClock(t *testing.T) {
+	if err := New().AdvanceTo(time.Now()); err != ErrNoFakeClock {
+		t.Errorf("expected ErrNoFakeClock, got %v", err)
+	}
+}
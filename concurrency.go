@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// maxConcurrencyBuckets caps the buckets of Concurrency.
+const maxConcurrencyBuckets = 10000
+
+// ConcurrencyBucket is how many runs were going at once over a span of
+// time, see Concurrency.
+type ConcurrencyBucket struct {
+	Start time.Time `json:"start"`
+
+	// The most runs going at once during the bucket, and how many were
+	// going on average over it.
+	Peak int     `json:"peak"`
+	Mean float64 `json:"mean"`
+}
+
+// Concurrency works out how many runs were going at once, in buckets of the
+// given width, or of a minute if none, from the recent runs in the history
+// and those in flight: a peak close to the size of the WorkerPool in most
+// buckets says it is saturated. The query picks the runs of some entries,
+// or outcomes, and From and To the span of time, by default from the
+// earliest run on to now. Runs that were skipped took no time, so they
+// don't count. As History, it only goes as far back as the runs kept for
+// each entry.
+func (c *Cron) Concurrency(q HistoryQuery, bucket time.Duration) ([]ConcurrencyBucket, error) {
+	if bucket <= 0 {
+		bucket = time.Minute
+	}
+	from, to := q.From, q.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	span := q
+	span.From, span.To, span.Offset, span.Limit = time.Time{}, time.Time{}, 0, 0
+
+	// A run is a step up at its start and a step down at its end.
+	type step struct {
+		at    time.Time
+		delta int
+	}
+	var steps []step
+	add := func(start, end time.Time) {
+		steps = append(steps, step{start, 1}, step{end, -1})
+	}
+	for _, r := range c.QueryHistory(span).Runs {
+		if !r.Outcome.skipped() {
+			add(r.Start, r.End)
+		}
+	}
+	if len(q.Outcomes) == 0 {
+		tagged := c.tagged(q.Tag)
+		c.mu.Lock()
+		for p := range c.going {
+			if q.picks(p.info.Name, tagged) && !p.info.Start.IsZero() {
+				add(p.info.Start, to)
+			}
+		}
+		c.mu.Unlock()
+	}
+	if len(steps) == 0 && from.IsZero() {
+		return []ConcurrencyBucket{}, nil
+	}
+	// Ends first, so a run starting as another ends doesn't count twice.
+	sort.Slice(steps, func(i, j int) bool {
+		if !steps[i].at.Equal(steps[j].at) {
+			return steps[i].at.Before(steps[j].at)
+		}
+		return steps[i].delta < steps[j].delta
+	})
+	if from.IsZero() {
+		from = steps[0].at
+	}
+	from = from.Truncate(bucket)
+	if n := to.Sub(from) / bucket; n >= maxConcurrencyBuckets {
+		return nil, fmt.Errorf("scheduler: %d buckets of %s is too many, at most %d", n+1, bucket, maxConcurrencyBuckets)
+	}
+
+	buckets := []ConcurrencyBucket{}
+	level, i := 0, 0
+	for ; i < len(steps) && !steps[i].at.After(from); i++ {
+		level += steps[i].delta
+	}
+	for start := from; start.Before(to); start = start.Add(bucket) {
+		end := start.Add(bucket)
+		b := ConcurrencyBucket{Start: start, Peak: level}
+		var area time.Duration
+		at := start
+		for ; i < len(steps) && steps[i].at.Before(end); i++ {
+			area += time.Duration(level) * steps[i].at.Sub(at)
+			at = steps[i].at
+			level += steps[i].delta
+			b.Peak = max(b.Peak, level)
+		}
+		area += time.Duration(level) * end.Sub(at)
+		b.Mean = float64(area) / float64(bucket)
+		buckets = append(buckets, b)
+	}
+	return buckets, nil
+}
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestConcurrency(t *testing.T) {
+	cron := New()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(min int) time.Time { return base.Add(time.Duration(min) * time.Minute) }
+	cron.history = map[string][]RunRecord{
+		"a": {
+			{Name: "a", Outcome: OutcomeSuccess, Start: at(0), End: at(2)},
+			{Name: "a", Outcome: OutcomeSuccess, Start: at(2), End: at(3)},
+		},
+		"b": {
+			{Name: "b", Outcome: OutcomePanic, Start: at(1), End: at(3)},
+			{Name: "b", Outcome: OutcomeVetoed, Start: at(1), End: at(1)},
+		},
+	}
+
+	buckets, err := cron.Concurrency(HistoryQuery{To: at(4)}, 2*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a runs the whole first bucket and b its second half; both run the
+	// first half of the second bucket.
+	want := []ConcurrencyBucket{{Start: at(0), Peak: 2, Mean: 1.5}, {Start: at(2), Peak: 2, Mean: 1}}
+	if len(buckets) != len(want) {
+		t.Fatalf("expected %v, got %v", want, buckets)
+	}
+	for i := range want {
+		if !buckets[i].Start.Equal(want[i].Start) || buckets[i].Peak != want[i].Peak || buckets[i].Mean != want[i].Mean {
+			t.Errorf("bucket %d: expected %+v, got %+v", i, want[i], buckets[i])
+		}
+	}
+
+	buckets, _ = cron.Concurrency(HistoryQuery{Names: []string{"a"}, From: at(1), To: at(3)}, time.Minute)
+	if len(buckets) != 2 || buckets[0].Peak != 1 || buckets[1].Mean != 1 {
+		t.Errorf("expected a alone from 12:01, got %+v", buckets)
+	}
+	if _, err := cron.Concurrency(HistoryQuery{From: at(0), To: at(0).AddDate(1, 0, 0)}, time.Second); err == nil {
+		t.Error("expected too many buckets to be refused")
+	}
+}
+
+func TestAdminConcurrency(t *testing.T) {
+	cron := New()
+	now := time.Now()
+	cron.history = map[string][]RunRecord{
+		"a": {{Name: "a", Outcome: OutcomeSuccess, Start: now.Add(-90 * time.Second), End: now.Add(-30 * time.Second)}},
+	}
+	admin := NewAdmin(cron, TokenAuth(map[string]Role{"r": RoleReader}))
+
+	w := adminRequest(t, admin, "GET", "/concurrency?bucket=1m", "r")
+	var buckets []ConcurrencyBucket
+	if err := json.NewDecoder(w.Body).Decode(&buckets); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("unexpected response %d: %v", w.Code, err)
+	}
+	if len(buckets) < 2 || buckets[0].Peak != 1 {
+		t.Errorf("unexpected buckets %+v", buckets)
+	}
+	if w := adminRequest(t, admin, "GET", "/concurrency?bucket=soon", "r"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected a bad bucket to be refused, got %d", w.Code)
+	}
+}
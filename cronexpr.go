@@ -0,0 +1,310 @@
+package scheduler
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronExpr is a Schedule firing on a crontab expression, on the wall clock of
+// the location it is asked in. Each field is a set of the values it
+// matches, as bits.
+type cronExpr struct {
+	spec string
+
+	second, minute, hour, dom, month, dow uint64
+
+	// Whether the day of the month and the day of the week are left at *.
+	// If neither is, a day matching either matches, as in crontab.
+	domAny, dowAny bool
+}
+
+// cronField is the range of a field of a crontab expression, and the names
+// its values may go by.
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = [6]cronField{
+	{name: "second", min: 0, max: 59},
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	// 7 is Sunday as well as 0.
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+// cronDescriptors are the shorthands of crontab for common expressions,
+// with seconds.
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// CronFormat is the format of the crontab expressions of a scheduler or an
+// entry, see WithCronFormat and WithEntryCronFormat.
+type CronFormat int
+
+const (
+	// The format of the scheduler, for an entry. For the scheduler, it means
+	// CronStandard.
+	CronDefault CronFormat = iota
+
+	// 5 fields, starting with the minute.
+	CronStandard
+
+	// 6 fields, starting with the second, as in "*/15 * * * * *".
+	CronSeconds
+
+	// 5 fields, or 6 starting with the second.
+	CronOptionalSeconds
+)
+
+// ParseCron parses a standard 5 field crontab expression, minute, hour, day
+// of the month, month and day of the week, into a Schedule for AddFuncOn:
+//
+//	s, err := ParseCron("0 3 * * 1-5") // 03:00 on weekdays
+//
+// Fields take *, values, ranges such as 1-5, steps such as */15 or 0-30/10,
+// and lists of those such as 1,15. Months and days of the week may go by
+// their first three letters, as in JAN or mon, and Sunday is 0 or 7. When
+// both the day of the month and the day of the week are restricted, a day
+// matching either matches. The shorthands @yearly, @monthly, @weekly,
+// @daily and @hourly are accepted too, as is "@every <duration>", such as
+// "@every 90s", see Every.
+//
+// Times are in the location of the scheduler, see WithLocation, unless the
+// expression starts with the time zone it goes by, as in
+// "CRON_TZ=Europe/Paris 0 3 * * *". Like Daily, on a day that skips a
+// time, the run comes as many minutes later as the clock skipped. For
+// expressions with seconds, see ParseCronFormat.
+func ParseCron(spec string) (Schedule, error) {
+	return ParseCronFormat(spec, CronStandard)
+}
+
+// ParseCronFormat is ParseCron for expressions in the given format. With a
+// seconds field, the first one, an expression may fire more than once a
+// minute, as "*/15 * * * * *" does every 15 seconds.
+func ParseCronFormat(spec string, format CronFormat) (Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) > 0 && strings.HasPrefix(strings.ToUpper(fields[0]), cronTZ) {
+		name := fields[0][len(cronTZ):]
+		loc, err := time.LoadLocation(name)
+		if err != nil || name == "" {
+			return nil, fmt.Errorf("scheduler: bad cron expression %q: unknown time zone %q", spec, name)
+		}
+		s, err := ParseCronFormat(strings.Join(fields[1:], " "), format)
+		if err != nil {
+			return nil, err
+		}
+		return zoned{s, loc}, nil
+	}
+	if len(fields) == 2 && strings.ToLower(fields[0]) == "@every" {
+		d, err := time.ParseDuration(fields[1])
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("scheduler: bad cron expression %q: bad duration %q", spec, fields[1])
+		}
+		return Every(d), nil
+	}
+	if format == CronDefault {
+		format = CronStandard
+	}
+	d, descriptor := cronDescriptors[strings.ToLower(strings.TrimSpace(spec))]
+	switch {
+	case descriptor:
+		// In any format.
+		fields = strings.Fields(d)
+	case len(fields) == 5 && format != CronSeconds:
+		fields = append([]string{"0"}, fields...)
+	case len(fields) == 6 && format != CronStandard:
+	default:
+		want := map[CronFormat]string{CronStandard: "5", CronSeconds: "6", CronOptionalSeconds: "5 or 6"}[format]
+		return nil, fmt.Errorf("scheduler: bad cron expression %q: want %s fields, got %d", spec, want, len(fields))
+	}
+	var sets [6]uint64
+	for i, f := range fields {
+		set, err := cronFields[i].parse(f)
+		if err != nil {
+			return nil, fmt.Errorf("scheduler: bad cron expression %q: %v", spec, err)
+		}
+		sets[i] = set
+	}
+	if sets[5]&(1<<7) != 0 {
+		sets[5] |= 1
+	}
+	return cronExpr{
+		spec:   strings.Join(strings.Fields(spec), " "),
+		second: sets[0], minute: sets[1], hour: sets[2], dom: sets[3], month: sets[4], dow: sets[5],
+		domAny: strings.HasPrefix(fields[3], "*"), dowAny: strings.HasPrefix(fields[5], "*"),
+	}, nil
+}
+
+// parse returns the set of values s matches.
+func (f cronField) parse(s string) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(s, ",") {
+		lo, hi, step := f.min, f.max, 1
+		rng := part
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("bad step %q in %s", part[i+1:], f.name)
+			}
+			step, rng = n, part[:i]
+		}
+		if rng != "*" {
+			from, to, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(from); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = f.value(to); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// a/n runs from a to the end of the range.
+				hi = f.max
+			}
+			if hi < lo {
+				return 0, fmt.Errorf("bad range %q in %s", rng, f.name)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// value parses a single value of the field, a number or a name.
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("bad %s %q", f.name, s)
+	}
+	return v, nil
+}
+
+// cronHorizon is how many years ahead Next looks for an occurrence, before
+// it gives up on an expression that never matches, such as "0 0 30 2 *".
+const cronHorizon = 5
+
+func (c cronExpr) Next(t time.Time) time.Time {
+	loc := t.Location()
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	end := day.AddDate(cronHorizon, 0, 0)
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if !c.matchesDay(day) {
+			continue
+		}
+		y, m, d := day.Date()
+		for hours := c.hour; hours != 0; hours &= hours - 1 {
+			h := bits.TrailingZeros64(hours)
+			for mins := c.minute; mins != 0; mins &= mins - 1 {
+				min := bits.TrailingZeros64(mins)
+				for secs := c.second; secs != 0; secs &= secs - 1 {
+					sec := bits.TrailingZeros64(secs)
+					if next := wallTime(y, m, d, h, min, sec, loc); next.After(t) {
+						return next
+					}
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// matchesDay reports whether the expression fires on day.
+func (c cronExpr) matchesDay(day time.Time) bool {
+	if c.month&(1<<uint(day.Month())) == 0 {
+		return false
+	}
+	dom := c.dom&(1<<uint(day.Day())) != 0
+	dow := c.dow&(1<<uint(day.Weekday())) != 0
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	}
+	return dom || dow
+}
+
+func (c cronExpr) String() string { return c.spec }
+
+// cronTZ prefixes the time zone of a crontab expression.
+const cronTZ = "CRON_TZ="
+
+// zoned is a Schedule going by the clock of loc, wherever it is asked.
+type zoned struct {
+	s   Schedule
+	loc *time.Location
+}
+
+func (z zoned) Next(t time.Time) time.Time {
+	return z.s.Next(t.In(z.loc)).In(t.Location())
+}
+
+func (z zoned) String() string { return fmt.Sprintf("%s%s %v", cronTZ, z.loc, z.s) }
+
+// WithEntryCronFormat parses the crontab expression of the entry, given to
+// AddCron or AddCronJob, in format rather than that of the scheduler.
+func WithEntryCronFormat(format CronFormat) EntryOption {
+	return func(e *Entry) {
+		e.cronFormat = format
+	}
+}
+
+// AddCron adds a func to the Cron to be run on a crontab expression, see
+// ParseCron and WithCronFormat.
+func (c *Cron) AddCron(spec string, cmd func(), name string, opts ...EntryOption) error {
+	return c.AddCronJob(spec, FuncJob(cmd), name, opts...)
+}
+
+// AddCronJob adds a Job to the Cron to be run on a crontab expression, see
+// ParseCron and WithCronFormat.
+func (c *Cron) AddCronJob(spec string, cmd Job, name string, opts ...EntryOption) error {
+	probe := &Entry{}
+	for _, opt := range opts {
+		opt(probe)
+	}
+	s, err := ParseCronFormat(spec, c.cronFormatOf(probe))
+	if err != nil {
+		return err
+	}
+	return c.AddJobOn(s, cmd, name, opts...)
+}
+
+// cronFormatOf returns the format of the crontab expression of e.
+func (c *Cron) cronFormatOf(e *Entry) CronFormat {
+	if e.cronFormat != CronDefault {
+		return e.cronFormat
+	}
+	if c.cronFormat != CronDefault {
+		return c.cronFormat
+	}
+	return CronStandard
+}
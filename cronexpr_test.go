@@ -0,0 +1,215 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	// A Wednesday.
+	from := time.Date(2030, 1, 2, 10, 7, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		spec string
+		want []string
+	}{
+		{"*/15 * * * *", []string{"2030-01-02 10:15", "2030-01-02 10:30", "2030-01-02 10:45"}},
+		{"0 3 * * 1-5", []string{"2030-01-03 03:00", "2030-01-04 03:00", "2030-01-07 03:00"}},
+		{"30 9 1,15 * *", []string{"2030-01-15 09:30", "2030-02-01 09:30", "2030-02-15 09:30"}},
+		{"0 0 13 * fri", []string{"2030-01-04 00:00", "2030-01-11 00:00", "2030-01-13 00:00"}},
+		{"0 12 * feb SUN", []string{"2030-02-03 12:00", "2030-02-10 12:00", "2030-02-17 12:00"}},
+		{"0 12 * * 7", []string{"2030-01-06 12:00", "2030-01-13 12:00", "2030-01-20 12:00"}},
+		{"5/20 8-9 * * *", []string{"2030-01-03 08:05", "2030-01-03 08:25", "2030-01-03 08:45"}},
+		{"@monthly", []string{"2030-02-01 00:00", "2030-03-01 00:00", "2030-04-01 00:00"}},
+	} {
+		s, err := ParseCron(tc.spec)
+		if err != nil {
+			t.Errorf("%s: %v", tc.spec, err)
+			continue
+		}
+		at := from
+		for _, want := range tc.want {
+			at = s.Next(at)
+			if got := at.Format("2006-01-02 15:04"); got != want {
+				t.Errorf("%s: expected %s, got %s", tc.spec, want, got)
+				break
+			}
+		}
+	}
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "@often"} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+	if s, _ := ParseCron("0 0 30 2 *"); !s.Next(from).IsZero() {
+		t.Error("expected February 30th never to come")
+	}
+}
+
+// Like Daily, a time the clock skips runs as many minutes later.
+func TestCronSpringForward(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	s, _ := ParseCron("30 2 * * *")
+	next := s.Next(time.Date(2030, 3, 10, 0, 0, 0, 0, loc))
+	if want := time.Date(2030, 3, 10, 3, 30, 0, 0, loc); !next.Equal(want) {
+		t.Errorf("expected %s, got %s", want, next)
+	}
+}
+
+func TestAddCron(t *testing.T) {
+	cron := New()
+	if err := cron.AddCron("0 3 * * 1-5", func() {}, "nightly"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cron.AddCron("0 3 * *", func() {}, "bad"); err == nil {
+		t.Error("expected a bad expression to be an error")
+	}
+	cron.Start()
+	defer cron.Stop()
+	next := cron.Entries()[0].NextTime
+	if next.Hour() != 3 || next.Minute() != 0 || next.Weekday() == time.Saturday || next.Weekday() == time.Sunday {
+		t.Errorf("unexpected next run %s", next)
+	}
+}
+
+func TestParseCronSeconds(t *testing.T) {
+	from := time.Date(2030, 1, 2, 10, 7, 50, 0, time.UTC)
+	s, err := ParseCronFormat("*/15 * * * * *", CronSeconds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := from
+	for _, want := range []string{"10:08:00", "10:08:15", "10:08:30"} {
+		if at = s.Next(at); at.Format("15:04:05") != want {
+			t.Errorf("expected %s, got %s", want, at.Format("15:04:05"))
+		}
+	}
+	for _, tc := range []struct {
+		spec   string
+		format CronFormat
+		ok     bool
+	}{
+		{"* * * * *", CronStandard, true},
+		{"* * * * * *", CronStandard, false},
+		{"* * * * *", CronSeconds, false},
+		{"* * * * * *", CronSeconds, true},
+		{"* * * * *", CronOptionalSeconds, true},
+		{"30 * * * * *", CronOptionalSeconds, true},
+		{"60 * * * * *", CronSeconds, false},
+		{"@hourly", CronSeconds, true},
+	} {
+		if _, err := ParseCronFormat(tc.spec, tc.format); (err == nil) != tc.ok {
+			t.Errorf("%q in format %d: expected ok %v, got %v", tc.spec, tc.format, tc.ok, err)
+		}
+	}
+	if s, _ := ParseCronFormat("0 3 * * *", CronOptionalSeconds); s.Next(from).Second() != 0 || s.Next(from).Hour() != 3 {
+		t.Errorf("expected a 5 field expression to fire on the minute, got %s", s.Next(from))
+	}
+}
+
+func TestCronFormatOption(t *testing.T) {
+	cron := New(WithCronFormat(CronSeconds))
+	if err := cron.AddCron("*/15 * * * * *", func() {}, "seconds"); err != nil {
+		t.Error(err)
+	}
+	if err := cron.AddCron("0 3 * * *", func() {}, "minutes"); err == nil {
+		t.Error("expected a 5 field expression to be an error")
+	}
+	if err := cron.AddCron("0 3 * * *", func() {}, "minutes", WithEntryCronFormat(CronStandard)); err != nil {
+		t.Error(err)
+	}
+	if err := New().AddCron("*/15 * * * * *", func() {}, "seconds"); err == nil {
+		t.Error("expected a 6 field expression to be an error by default")
+	}
+}
+
+func TestDescriptors(t *testing.T) {
+	from := time.Date(2030, 1, 2, 10, 7, 0, 0, time.UTC)
+	for spec, want := range map[string]string{
+		"@yearly":     "2031-01-01 00:00:00",
+		"@annually":   "2031-01-01 00:00:00",
+		"@monthly":    "2030-02-01 00:00:00",
+		"@weekly":     "2030-01-06 00:00:00",
+		"@daily":      "2030-01-03 00:00:00",
+		"@midnight":   "2030-01-03 00:00:00",
+		"@hourly":     "2030-01-02 11:00:00",
+		"@every 90s":  "2030-01-02 10:08:30",
+		"@EVERY 1h":   "2030-01-02 11:07:00",
+		"@every 1h2m": "2030-01-02 11:09:00",
+	} {
+		s, err := ParseSchedule(spec)
+		if err != nil {
+			t.Errorf("%s: %v", spec, err)
+			continue
+		}
+		if got := s.Next(from).Format("2006-01-02 15:04:05"); got != want {
+			t.Errorf("%s: expected %s, got %s", spec, want, got)
+		}
+	}
+}
+
+func TestCronTimeZone(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip(err)
+	}
+	s, err := ParseSchedule("CRON_TZ=Europe/Paris 0 3 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 03:00 in Paris is 01:00 UTC in the summer, and the time comes back in
+	// the location it was asked in.
+	next := s.Next(time.Date(2030, 7, 1, 12, 0, 0, 0, time.UTC))
+	if next.Location() != time.UTC || !next.Equal(time.Date(2030, 7, 2, 3, 0, 0, 0, paris)) {
+		t.Errorf("expected 03:00 in Paris, got %v", next)
+	}
+	if got := fmt.Sprint(s); got != "CRON_TZ=Europe/Paris 0 3 * * *" {
+		t.Errorf("unexpected spec %q", got)
+	}
+	if m, err := ParseSchedule("CRON_TZ=UTC 0 3 * * *, CRON_TZ=UTC 0 15 * * *"); err != nil || len(m.(merged)) != 2 {
+		t.Errorf("expected zoned expressions to merge, got %v %v", m, err)
+	}
+	if _, err := ParseCron("CRON_TZ=Nowhere/Atlantis 0 3 * * *"); err == nil {
+		t.Error("expected an unknown time zone to be refused")
+	}
+}
+
+func TestAddCronEvery(t *testing.T) {
+	cron := New()
+	ran := make(chan struct{}, 10)
+	cron.AddCron("@every 50ms", func() { ran <- struct{}{} }, "often")
+	cron.Start()
+	defer cron.Stop()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ran:
+		case <-time.After(ONE_SECOND):
+			t.Fatal("expected the entry to run every 50ms")
+		}
+	}
+}
+
+// Times the clock skips run once it is past the gap, east of UTC too.
+func TestCronAcrossDSTEastOfUTC(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+	at := func(hh, mm int) time.Time { return time.Date(2026, 3, 29, hh, mm, 0, 0, loc) }
+	expr, _ := ParseCron("30 2 * * *")
+	if got, want := expr.Next(at(0, 0)), at(3, 30); !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	expr, _ = ParseCron("0,30 1,2,3 * * *")
+	var got []string
+	for next := expr.Next(at(0, 0)); next.Day() == 29; next = expr.Next(next) {
+		got = append(got, next.Format("15:04"))
+	}
+	if want := "01:00 01:30 03:00 03:30"; strings.Join(got, " ") != want {
+		t.Errorf("expected the runs %s, got %v", want, got)
+	}
+}
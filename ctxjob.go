@@ -0,0 +1,36 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// JobCtx is a job that is handed the context of its run, which is cancelled
+// once the run is over, when the scheduler gives up on it (see
+// WithHeartbeat) and on Shutdown, so long running work can stop cleanly.
+type JobCtx interface {
+	Run(ctx context.Context)
+}
+
+// A wrapper that turns a func(context.Context) into a JobCtx.
+type CtxFuncJob func(context.Context)
+
+func (f CtxFuncJob) Run(ctx context.Context) { f(ctx) }
+
+// AddFuncCtx adds a func that takes the context of its run to the Cron to be
+// run on the given schedule.
+func (c *Cron) AddFuncCtx(startTime time.Time, Interval time.Duration, cmd func(context.Context), name string, opts ...EntryOption) error {
+	return c.AddJobCtx(startTime, Interval, CtxFuncJob(cmd), name, opts...)
+}
+
+// AddJobCtx adds a JobCtx to the Cron to be run on the given schedule.
+func (c *Cron) AddJobCtx(startTime time.Time, Interval time.Duration, cmd JobCtx, name string, opts ...EntryOption) error {
+	return c.AddProgressJob(startTime, Interval, ctxJob{cmd}, name, opts...)
+}
+
+// ctxJob adapts a JobCtx to a ProgressJob, whose Progress has the context.
+type ctxJob struct {
+	job JobCtx
+}
+
+func (j ctxJob) Run(p *Progress) { j.job.Run(p.Context()) }
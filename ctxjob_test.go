@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestShutdownCancelsJobCtx(t *testing.T) {
+	cron := New()
+	started, stopped := make(chan struct{}), make(chan error, 1)
+	cron.AddFuncCtx(time.Now().Add(50*time.Millisecond), time.Hour, func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		stopped <- ctx.Err()
+	}, "long")
+	cron.Start()
+
+	select {
+	case <-started:
+	case <-time.After(ONE_SECOND):
+		t.Fatal("the job did not start")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ONE_SECOND)
+	defer cancel()
+	if err := cron.Shutdown(ctx); err != nil {
+		t.Fatalf("expected the run to return once cancelled, got %v", err)
+	}
+	if err := <-stopped; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the context cancelled, got %v", err)
+	}
+}
+
+func TestShutdownTimesOut(t *testing.T) {
+	cron := New()
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() {
+		close(started)
+		<-release
+	}, "stubborn")
+	cron.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := cron.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected to give up on a job ignoring its context, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// GrafanaDashboard returns the JSON model of a Grafana dashboard charting
+// the metrics of a PrometheusMetrics with the given namespace: the runs by
+// outcome, the failures, the duration and lateness percentiles, with the
+// exemplars of the runs linking to their traces, and the dropped triggers,
+// for a Prometheus data source and the jobs picked on the dashboard. It is
+// meant to be imported into Grafana, or provisioned from a file;
+// grafana/scheduler.json is the one for the namespace "scheduler".
+func GrafanaDashboard(namespace string) ([]byte, error) {
+	m := &PrometheusMetrics{namespace: namespace}
+	pm := prometheusMetrics
+	runs, duration, lateness, dropped := m.name(pm.runs), m.name(pm.duration), m.name(pm.lateness), m.name(pm.dropped)
+	const sel = `{job=~"$job"}`
+	const rate = "[$__rate_interval]"
+	quantile := func(q float64, metric string) string {
+		return fmt.Sprintf("histogram_quantile(%g, sum by (job, le) (rate(%s_bucket%s%s)))", q, metric, sel, rate)
+	}
+
+	panels := []grafanaPanel{
+		{Title: "Runs by outcome", Unit: "ops", Targets: []grafanaTarget{
+			{Expr: fmt.Sprintf("sum by (outcome) (rate(%s%s%s))", runs, sel, rate), LegendFormat: "{{outcome}}"},
+		}},
+		{Title: "Failed runs", Unit: "ops", Targets: []grafanaTarget{
+			{Expr: fmt.Sprintf(`sum by (job) (rate(%s{job=~"$job",outcome=~"%s|%s|%s"}%s))`, runs, OutcomePanic, OutcomeError, OutcomeStalled, rate), LegendFormat: "{{job}}"},
+		}},
+		{Title: "Run duration", Unit: "s", Targets: []grafanaTarget{
+			{Expr: quantile(0.5, duration), LegendFormat: "p50 {{job}}", Exemplar: true},
+			{Expr: quantile(0.95, duration), LegendFormat: "p95 {{job}}", Exemplar: true},
+		}},
+		{Title: "Run lateness", Unit: "s", Targets: []grafanaTarget{
+			{Expr: quantile(0.95, lateness), LegendFormat: "p95 {{job}}"},
+		}},
+		{Title: "Dropped triggers", Unit: "ops", Targets: []grafanaTarget{
+			{Expr: fmt.Sprintf("sum by (job) (rate(%s%s%s))", dropped, sel, rate), LegendFormat: "{{job}}"},
+		}},
+	}
+	ds := map[string]string{"type": "prometheus", "uid": "${datasource}"}
+	var out []map[string]interface{}
+	for i, p := range panels {
+		targets := make([]map[string]interface{}, len(p.Targets))
+		for j, t := range p.Targets {
+			targets[j] = map[string]interface{}{
+				"datasource":   ds,
+				"expr":         t.Expr,
+				"legendFormat": t.LegendFormat,
+				"exemplar":     t.Exemplar,
+				"refId":        string(rune('A' + j)),
+			}
+		}
+		out = append(out, map[string]interface{}{
+			"id":         i + 1,
+			"type":       "timeseries",
+			"title":      p.Title,
+			"datasource": ds,
+			"gridPos":    map[string]int{"h": 8, "w": 12, "x": 12 * (i % 2), "y": 8 * (i / 2)},
+			"fieldConfig": map[string]interface{}{
+				"defaults":  map[string]string{"unit": p.Unit},
+				"overrides": []interface{}{},
+			},
+			"targets": targets,
+		})
+	}
+	title := "Scheduler"
+	if namespace != "" {
+		title += " (" + namespace + ")"
+	}
+	return json.MarshalIndent(map[string]interface{}{
+		"title":         title,
+		"tags":          []string{"scheduler"},
+		"schemaVersion": 39,
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"refresh":       "1m",
+		"templating": map[string]interface{}{"list": []interface{}{
+			map[string]interface{}{"name": "datasource", "type": "datasource", "query": "prometheus"},
+			map[string]interface{}{
+				"name":       "job",
+				"type":       "query",
+				"datasource": ds,
+				"definition": fmt.Sprintf("label_values(%s, job)", runs),
+				"query":      map[string]string{"query": fmt.Sprintf("label_values(%s, job)", runs), "refId": "jobs"},
+				"multi":      true,
+				"includeAll": true,
+				"allValue":   ".*",
+				"current":    map[string]interface{}{"text": "All", "value": "$__all"},
+				"refresh":    2,
+			},
+		}},
+		"panels": out,
+	}, "", "  ")
+}
+
+type grafanaPanel struct {
+	Title, Unit string
+	Targets     []grafanaTarget
+}
+
+type grafanaTarget struct {
+	Expr, LegendFormat string
+	Exemplar           bool
+}
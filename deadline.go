@@ -0,0 +1,40 @@
+package scheduler
+
+import "time"
+
+// WithNextRunDeadline gives the context of each run of the entry a deadline
+// margin before its next occurrence is due, so a run can never overlap its
+// successor even without a timeout of its own: a job that heeds its context
+// stops in time, whatever the schedule, where a fixed timeout has to be
+// kept in step with it. The next occurrence is the one of the schedule
+// after the time the run was due, or after now for RunNow, and retries keep
+// the deadline of the first attempt. One-shot entries and backfilled runs,
+// which are late already, have none.
+func WithNextRunDeadline(margin time.Duration) EntryOption {
+	return func(e *Entry) {
+		e.NextRunDeadline = true
+		e.NextRunMargin = margin
+	}
+}
+
+// nextRunDeadline returns the deadline of the runs of t, if they have one.
+// The schedule goes by the clock of the scheduler, the deadline by the wall
+// clock.
+func (c *Cron) nextRunDeadline(t trigger) (time.Time, bool) {
+	if !t.view.NextRunDeadline || t.backfill {
+		return time.Time{}, false
+	}
+	s := t.view.schedule()
+	if s == nil {
+		return time.Time{}, false
+	}
+	loc := t.view.location
+	if loc == nil {
+		loc = time.Local
+	}
+	next := s.Next(t.scheduled.In(loc))
+	if next.IsZero() {
+		return time.Time{}, false
+	}
+	return time.Now().Add(next.Sub(c.clock.Now()) - t.view.NextRunMargin), true
+}
@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNextRunDeadline(t *testing.T) {
+	cron := New()
+	bounded, unbounded := make(chan error, 1), make(chan bool, 1)
+	var left time.Duration
+	cron.AddFuncCtx(time.Now().Add(50*time.Millisecond), 300*time.Millisecond, func(ctx context.Context) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			bounded <- errors.New("no deadline")
+			return
+		}
+		left = time.Until(deadline)
+		<-ctx.Done()
+		bounded <- ctx.Err()
+	}, "bounded", WithNextRunDeadline(100*time.Millisecond), WithMaxRuns(1))
+	cron.AddFuncCtx(time.Now().Add(50*time.Millisecond), time.Hour, func(ctx context.Context) {
+		_, ok := ctx.Deadline()
+		unbounded <- ok
+	}, "unbounded")
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case err := <-bounded:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected the run to hit its deadline, got %v", err)
+		}
+		if left < 100*time.Millisecond || left > 250*time.Millisecond {
+			t.Errorf("expected the deadline 100ms before the next run, %v after the start", left)
+		}
+	case <-time.After(ONE_SECOND):
+		t.Fatal("expected the run to stop by the next one")
+	}
+	if <-unbounded {
+		t.Error("expected no deadline without the option")
+	}
+}
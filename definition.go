@@ -0,0 +1,149 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const definitionPrefix = "definitions/"
+
+// JobDefinition defines an entry whose job is of a registered kind, see
+// RegisterJobFactory, in a form that can be sent over the wire and stored,
+// such as to the admin API. Its parameters come out of JSON, so numbers are
+// float64s to the factory.
+type JobDefinition struct {
+	Name   string                 `json:"name"`
+	Kind   string                 `json:"kind"`
+	Params map[string]interface{} `json:"params,omitempty"`
+
+	// The entry either runs on Schedule, a spec of ParseSchedule, or from
+	// Start every Every, as in time.ParseDuration. Without Every it runs
+	// once, at Start.
+	Schedule string    `json:"schedule,omitempty"`
+	Start    time.Time `json:"start,omitempty"`
+	Every    string    `json:"every,omitempty"`
+
+	Tags      []string          `json:"tags,omitempty"`
+	Namespace string            `json:"namespace,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// defined returns the entry def defines, with the job made by c.
+func (c *Cron) defined(def JobDefinition) (*Entry, error) {
+	if def.Name == "" {
+		return nil, errors.New("scheduler: a definition needs a name")
+	}
+	e := &Entry{Name: def.Name, setStartTime: def.Start}
+	switch {
+	case def.Schedule != "" && (def.Every != "" || !def.Start.IsZero()):
+		return nil, errors.New("scheduler: a definition takes a schedule or a start and interval, not both")
+	case def.Schedule != "":
+		s, err := parseSchedule(def.Schedule, c.cronFormatOf(e))
+		if err != nil {
+			return nil, err
+		}
+		e.Schedule = s
+	case def.Every != "":
+		every, err := time.ParseDuration(def.Every)
+		if err != nil || every <= 0 {
+			return nil, fmt.Errorf("scheduler: bad interval %q", def.Every)
+		}
+		e.Interval = every
+	case def.Start.IsZero():
+		return nil, errors.New("scheduler: a definition needs a schedule or a start")
+	}
+	job, err := c.NewJob(def.Kind, def.Params)
+	if err != nil {
+		return nil, err
+	}
+	e.Job = job
+	registered(def.Kind, def.Params)(e)
+	WithTags(def.Tags...)(e)
+	WithNamespace(def.Namespace)(e)
+	if def.Metadata != nil {
+		WithMetadata(def.Metadata)(e)
+	}
+	return e, nil
+}
+
+// AddDefinition adds the entry def defines, and stores def in the JobStore,
+// if any, so that LoadDefinitions adds it again after a restart, until the
+// entry is removed. It returns the name of the entry, which the
+// DuplicatePolicy may have changed. An entry that was added but could not be
+// stored runs until the scheduler stops, and the error says so.
+func (c *Cron) AddDefinition(def JobDefinition) (string, error) {
+	e, err := c.defined(def)
+	if err != nil {
+		return "", err
+	}
+	e.persisted = c.store != nil
+	if err := c.admit(e); err != nil {
+		return "", err
+	}
+	def.Name = e.Name
+	if err := c.storeDefinition(def); err != nil {
+		return e.Name, fmt.Errorf("scheduler: %q added but not stored: %w", e.Name, err)
+	}
+	return e.Name, nil
+}
+
+// storeDefinition stores def in the JobStore, if any, for LoadDefinitions.
+func (c *Cron) storeDefinition(def JobDefinition) error {
+	if c.store == nil {
+		return nil
+	}
+	data, err := json.Marshal(def)
+	if err != nil {
+		return err
+	}
+	return c.store.Put(definitionPrefix+def.Name, data)
+}
+
+// LoadDefinitions adds the entries stored by AddDefinition, such as when the
+// scheduler starts again. It goes on past the definitions it cannot add,
+// say of a kind no longer registered, and returns their errors joined.
+func (c *Cron) LoadDefinitions() error {
+	if c.store == nil {
+		return ErrNoJobStore
+	}
+	keys, err := c.store.Keys(definitionPrefix)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, key := range keys {
+		data, err := c.store.Get(key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var def JobDefinition
+		if err := json.Unmarshal(data, &def); err != nil {
+			errs = append(errs, fmt.Errorf("scheduler: definition %q: %w", strings.TrimPrefix(key, definitionPrefix), err))
+			continue
+		}
+		e, err := c.defined(def)
+		if err == nil {
+			e.persisted = true
+			err = c.admit(e)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// forget deletes the stored definition of e, once it is removed. It is
+// called from the run loop.
+func (c *Cron) forget(e *Entry) {
+	if !e.persisted || c.store == nil {
+		return
+	}
+	if err := c.store.Delete(definitionPrefix + e.Name); err != nil {
+		c.log(SubsystemLifecycle).Error(err, "deleting the stored definition failed", "entry", e.Name)
+	}
+}
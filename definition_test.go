@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAdminAddDefinition(t *testing.T) {
+	store := NewMemoryStore()
+	cron := New(WithJobStore(store), WithDuplicatePolicy(DuplicateError))
+	ran := make(chan string, 10)
+	cron.RegisterJobFactory("report", func(params map[string]interface{}) Job {
+		return FuncJob(func() { ran <- params["to"].(string) })
+	})
+	cron.Start()
+	defer cron.Stop()
+	admin := NewAdmin(cron, TokenAuth(map[string]Role{"r": RoleReader, "op": RoleOperator}))
+	post := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/entries", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		admin.ServeHTTP(w, req)
+		return w
+	}
+
+	body := `{"name": "weekly-report", "kind": "report", "params": {"to": "ops"}, "schedule": "weekly monday 09:00", "tags": ["reports"]}`
+	if w := post("r", body); w.Code != http.StatusForbidden {
+		t.Errorf("expected a reader to be refused, got %d", w.Code)
+	}
+	w := post("op", body)
+	if w.Code != http.StatusCreated || !strings.Contains(w.Body.String(), `"name":"weekly-report"`) {
+		t.Fatalf("expected the entry created, got %d %s", w.Code, w.Body)
+	}
+	if w := post("op", body); w.Code != http.StatusConflict {
+		t.Errorf("expected a duplicate to conflict, got %d", w.Code)
+	}
+	for _, bad := range []string{
+		`{"name": "x", "kind": "missing", "every": "1h"}`,
+		`{"name": "x", "kind": "report", "schedule": "hourly"}`,
+		`{"name": "x", "kind": "report"}`,
+		`not json`,
+	} {
+		if w := post("op", bad); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected a 400, got %d %s", bad, w.Code, w.Body)
+		}
+	}
+
+	e, ok := cron.Entry("weekly-report")
+	if !ok || e.Kind != "report" || !e.HasTag("reports") || e.NextTime.Weekday() != time.Monday {
+		t.Fatalf("unexpected entry %+v", e)
+	}
+	cron.RunNow("weekly-report")
+	if to := <-ran; to != "ops" {
+		t.Errorf("expected the job made from the params, got %q", to)
+	}
+
+	// Another scheduler on the store loads the definition, until the entry
+	// is removed.
+	other := New(WithJobStore(store))
+	other.RegisterJobFactory("report", func(map[string]interface{}) Job { return FuncJob(func() {}) })
+	if err := other.LoadDefinitions(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := other.Entry("weekly-report"); !ok {
+		t.Error("expected the stored definition to be loaded")
+	}
+	cron.RemoveJob("weekly-report")
+	if keys, _ := store.Keys(definitionPrefix); len(keys) != 0 {
+		t.Errorf("expected the definition deleted with the entry, got %v", keys)
+	}
+}
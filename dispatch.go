@@ -0,0 +1,527 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// trigger is a run of an entry that has come due.
+type trigger struct {
+	// Copy of the entry taken when the run came due.
+	view      *Entry
+	scheduled time.Time
+
+	// Set for runs enqueued by Backfill.
+	backfill bool
+
+	// Which attempt at the run this is, starting at 1, and how long it
+	// waited after the previous one, see WithRetries.
+	attempt int
+	backoff time.Duration
+
+	// The sequence number of the trigger, see RunInfo.Sequence.
+	sequence uint64
+
+	// How long to hold the run back before it starts, see
+	// WithStormProtection.
+	delay time.Duration
+
+	// Set for runs of the new job of a canary, see WithCanary.
+	canary bool
+
+	// Set for runs started by RunNow, which waits on done for the record,
+	// and for those of them that took the place of a scheduled run.
+	manual    bool
+	done      chan RunRecord
+	coalesced bool
+
+	// Set for runs triggered by a source, see WithSource.
+	sourced bool
+
+	// The labels of the entry let through to the metrics, see
+	// WithMetricLabels.
+	labels map[string]string
+
+	// Why the run was deferred or coalesced, if it was.
+	reason Reason
+}
+
+// attemptNumber returns which attempt at the run t is, starting at 1.
+func (t trigger) attemptNumber() int {
+	if t.attempt == 0 {
+		return 1
+	}
+	return t.attempt
+}
+
+// OverlapPolicy decides what happens to a run of an entry that comes due
+// while a previous run of the same entry is still going.
+type OverlapPolicy int
+
+const (
+	// Start the run anyway, concurrently with the previous one.
+	OverlapAllow OverlapPolicy = iota
+
+	// Queue the run until the previous one is over, so the runs of the
+	// entry never overlap. See DelayIfStillRunning for runs of different
+	// versions or entries.
+	OverlapSerialize
+
+	// Drop the run while the previous one is still going, counting it in
+	// Entry.DroppedTriggers.
+	OverlapSkip
+)
+
+// WithOverlap sets the overlap policy of the entry. The default is
+// OverlapAllow.
+func WithOverlap(p OverlapPolicy) EntryOption {
+	return func(e *Entry) {
+		e.Overlap = p
+	}
+}
+
+// WithMaxConcurrent lets up to n runs of the entry go at once, in place of
+// the one of OverlapSerialize and OverlapSkip, such as for a job that
+// tolerates a little overlap but not a pile-up. A run past the limit is
+// queued until one of them is over, or dropped under OverlapSkip. With
+// OverlapAllow, the default, it is queued.
+func WithMaxConcurrent(n int) EntryOption {
+	return func(e *Entry) {
+		e.MaxConcurrent = n
+	}
+}
+
+// concurrencyLimit returns how many runs of e may go at once, 0 for any
+// number.
+func (e *Entry) concurrencyLimit() int {
+	switch {
+	case e.MaxConcurrent > 0:
+		return e.MaxConcurrent
+	case e.Overlap != OverlapAllow:
+		return 1
+	}
+	return 0
+}
+
+// WithMaxPending caps how many runs of the entry may be waiting to start,
+// either queued by OverlapSerialize or WithMaxConcurrent, or waiting for a
+// worker of the WorkerPool. Triggers past the cap are dropped, that is
+// coalesced into the runs already waiting, and counted in
+// Entry.DroppedTriggers.
+func WithMaxPending(n int) EntryOption {
+	return func(e *Entry) {
+		e.MaxPending = n
+	}
+}
+
+// dispatch starts a run of the entry that was due at scheduled with its
+// Executor, unless the entry is disabled or its overlap policy holds the
+// run back.
+func (c *Cron) dispatch(e *Entry, scheduled time.Time) {
+	c.dispatchTrigger(e, trigger{scheduled: scheduled})
+}
+
+// dispatchTrigger is dispatch for a trigger carrying more than its time. It
+// returns false if the trigger was not taken.
+func (c *Cron) dispatchTrigger(e *Entry, t trigger) bool {
+	// Emitted, and the run started, once c.mu is released.
+	var skipped Reason
+	var run *Run
+	var x Executor
+	defer func() {
+		if t.sequence != 0 {
+			c.storeSequence(e.Name, t.sequence)
+		}
+		if run != nil {
+			x.Execute(run)
+		}
+		if skipped != "" {
+			c.emit(Event{Type: EventTriggerSkipped, Name: e.Name, Version: e.Version, Metadata: e.Metadata, Reason: skipped})
+		}
+	}()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e.Disabled {
+		c.debug(SubsystemDispatch, "entry disabled, trigger ignored", "entry", e.Name, "scheduled", t.scheduled)
+		c.traceDispatch(e, t, "disabled")
+		skipped = ReasonPaused
+		return false
+	}
+	if e.Circuit == CircuitOpen {
+		c.debug(SubsystemDispatch, "circuit open, trigger ignored", "entry", e.Name, "scheduled", t.scheduled, "failures", e.failures)
+		c.traceDispatch(e, t, "circuit_open")
+		skipped = ReasonPaused
+		return false
+	}
+	if missing := c.missingLabels(e); missing != nil {
+		c.debug(SubsystemDispatch, "instance lacks the labels of the entry, trigger ignored", "entry", e.Name, "scheduled", t.scheduled, "missing", missing)
+		c.traceDispatch(e, t, "elsewhere")
+		skipped = ReasonAffinity
+		return false
+	}
+	if e.MaxPending > 0 && e.Pending >= e.MaxPending {
+		c.debug(SubsystemDispatch, "too many runs pending, trigger dropped", "entry", e.Name, "scheduled", t.scheduled, "pending", e.Pending)
+		c.traceDispatch(e, t, "dropped")
+		skipped = ReasonOverlap
+		c.countDropped(e)
+		return false
+	}
+	limit := e.concurrencyLimit()
+	if e.Overlap == OverlapSkip && e.active >= limit {
+		c.debug(SubsystemDispatch, "runs still going, trigger dropped", "entry", e.Name, "scheduled", t.scheduled, "active", e.active)
+		c.traceDispatch(e, t, "dropped")
+		skipped = ReasonOverlap
+		c.countDropped(e)
+		return false
+	}
+
+	if e.retired {
+		c.traceDispatch(e, t, "retired")
+		return false
+	}
+	if c.held && t.manual {
+		c.debug(SubsystemDispatch, "dispatch held, manual run refused", "entry", e.Name)
+		return false
+	}
+	e.Runs++
+	e.Sequence++
+	t.sequence = e.Sequence
+	if e.MaxRuns > 0 && e.Runs >= e.MaxRuns {
+		e.retired = true
+		c.retiring = append(c.retiring, e)
+	}
+
+	t.view = e.copy()
+	t.labels = c.labelsOf(e)
+	e.pickCanary(&t)
+	if limit > 0 && e.active >= limit {
+		c.debug(SubsystemDispatch, "run queued behind the previous one", "entry", e.Name, "scheduled", t.scheduled)
+		c.traceDispatch(e, t, "queued")
+		if t.reason == "" {
+			t.reason = ReasonOverlap
+		}
+		e.queue = append(e.queue, t)
+		e.Pending++
+		return true
+	}
+	c.debug(SubsystemDispatch, "run dispatched", "entry", e.Name, "version", t.view.Version, "scheduled", t.scheduled)
+	c.traceDispatch(e, t, "dispatched")
+	e.active++
+	c.inFlight++
+	c.runs.Add(1)
+	x = c.executorOf(t.view)
+	run = &Run{
+		Trigger: TriggerMessage{Name: e.Name, Version: t.view.Version, Attempt: t.attemptNumber(), Sequence: t.sequence, Scheduled: t.scheduled, Sent: time.Now()},
+		do:      func() { c.runTriggers(e, t) },
+	}
+	return true
+}
+
+// traceDispatch records what became of trigger t of e, if it came from the
+// run loop.
+func (c *Cron) traceDispatch(e *Entry, t trigger, decision string) {
+	if c.tracer == nil || t.manual || t.backfill || t.sourced {
+		return
+	}
+	c.trace(TraceEvent{Kind: TraceDispatch, Entry: e.Name, Scheduled: t.scheduled, Decision: decision})
+}
+
+// countDropped counts a trigger of e that was dropped.
+func (c *Cron) countDropped(e *Entry) {
+	e.DroppedTriggers++
+	e.stats.Dropped++
+	if c.metrics != nil {
+		go c.metrics.TriggerDropped(e.Name)
+	}
+}
+
+// runTriggers runs t, then whatever got queued behind it.
+func (c *Cron) runTriggers(e *Entry, t trigger) {
+	defer c.runs.Done()
+	for {
+		if c.runEntry(e, t) {
+			t, _ = t.retry()
+			continue
+		}
+
+		c.mu.Lock()
+		if len(e.queue) == 0 {
+			e.active--
+			c.inFlight--
+			if e.active == 0 && e.retired {
+				c.archiveEntry(e)
+			}
+			c.mu.Unlock()
+			return
+		}
+		t = e.queue[0]
+		e.queue = e.queue[1:]
+		e.Pending--
+		c.mu.Unlock()
+	}
+}
+
+// acquire waits for the warm-up ramp, the resources the entry needs and
+// then for a worker of the pool of its tenant, counting the run as pending
+// meanwhile. It returns a func that releases them.
+func (c *Cron) acquire(e, view *Entry) (release func()) {
+	resources := c.resourcesOf(view)
+	tenant := c.tenantOf(view)
+	pool := c.poolOf(tenant)
+	if len(resources) == 0 && pool == nil && c.warmup == nil {
+		return func() {}
+	}
+
+	c.mu.Lock()
+	e.Pending++
+	c.mu.Unlock()
+	if c.warmup != nil {
+		c.warmup.acquire()
+	}
+	for _, r := range resources {
+		r.acquire()
+	}
+	if pool != nil {
+		pool.acquireFor(tenant)
+	}
+	c.mu.Lock()
+	e.Pending--
+	c.mu.Unlock()
+
+	return func() {
+		if pool != nil {
+			pool.release()
+		}
+		for i := len(resources) - 1; i >= 0; i-- {
+			resources[i].release()
+		}
+		if c.warmup != nil {
+			c.warmup.release()
+		}
+	}
+}
+
+// runEntry runs the job of the entry, records how the run ended and returns
+// whether it is to be retried, once the backoff is waited out.
+func (c *Cron) runEntry(e *Entry, t trigger) (retry bool) {
+	if t.delay > 0 {
+		time.Sleep(t.delay)
+	}
+	if cached, ok := c.cachedResult(e); ok {
+		r := skipped(t, OutcomeCached)
+		r.Output = cached.Output
+		c.over(e, t, OutcomeCached)
+		c.recordRun(r, t.view.Metadata)
+		if t.done != nil {
+			cached.Cached = true
+			t.done <- cached
+		}
+		return false
+	}
+	if !t.manual && c.paused(t.view, time.Now()) {
+		c.over(e, t, OutcomePaused)
+		c.finish(t, skipped(t, OutcomePaused))
+		return false
+	}
+	if !t.manual {
+		run, deferred := c.awaitHealthy(t.view)
+		if !run {
+			c.over(e, t, OutcomeUnhealthy)
+			c.finish(t, skipped(t, OutcomeUnhealthy))
+			return false
+		}
+		if deferred {
+			t.reason = ReasonUnhealthy
+		}
+	}
+	c.fence(t)
+	ctx, ok := c.intercept(t.view, t.scheduled)
+	if !ok {
+		c.over(e, t, OutcomeVetoed)
+		c.finish(t, skipped(t, OutcomeVetoed))
+		return false
+	}
+	if !c.charge(t.view) {
+		c.over(e, t, OutcomeOverBudget)
+		c.finish(t, skipped(t, OutcomeOverBudget))
+		return false
+	}
+	defer c.acquire(e, t.view)()
+	c.faults.delay()
+
+	start := time.Now()
+	p := newProgress()
+	p.logger = c.entryLogger(t)
+	p.secrets = c.secrets
+	p.scratch = c.Scratchpad(e.Name)
+	p.info = t.runInfo(start)
+	p.trigger = TriggerMessage{Name: e.Name, Version: t.view.Version, Attempt: p.info.Attempt, Sequence: t.sequence, Scheduled: t.scheduled}
+	ctx = context.WithValue(ctx, runInfoKey{}, p.info)
+	if t.labels != nil {
+		ctx = context.WithValue(ctx, labelsKey{}, t.labels)
+	}
+	if deadline, ok := c.nextRunDeadline(t); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+	p.start(ctx)
+	defer p.cancel()
+	if e.HeartbeatTimeout > 0 {
+		go c.watchHeartbeat(e, p)
+	}
+
+	c.mu.Lock()
+	e.progress = p
+	if c.live == nil {
+		c.live = make(map[string]*Progress)
+	}
+	c.live[e.Name] = p
+	if c.going == nil {
+		c.going = make(map[*Progress]bool)
+	}
+	c.going[p] = true
+	c.mu.Unlock()
+
+	err := c.invokeRecovering(c.chain(t.view, c.faults.job(t.view.Job)), t, p)
+	panicked := err != nil
+	p.output.close()
+
+	outcome := OutcomeSuccess
+	var failure string
+	if p.isStalled() {
+		outcome = OutcomeStalled
+		failure = "no heartbeat for " + e.HeartbeatTimeout.String()
+	}
+	if jobErr := p.failure(); jobErr != nil && !panicked {
+		outcome = OutcomeError
+		failure = jobErr.Error()
+		if c.errorHandler != nil {
+			c.errorHandler(e.Name, jobErr)
+		}
+	}
+	if panicked {
+		outcome = OutcomePanic
+		failure = err.Error()
+	}
+	output, truncated := p.output.contents()
+	c.mu.Lock()
+	if c.live[e.Name] == p {
+		delete(c.live, e.Name)
+	}
+	delete(c.going, p)
+	e.LastOutcome = outcome
+	if panicked {
+		e.Panics++
+		if c.panicPolicy == PanicDisable {
+			e.Disabled = true
+		}
+	}
+	r := RunRecord{
+		Name:            e.Name,
+		Version:         t.view.Version,
+		Scheduled:       t.scheduled,
+		Start:           start,
+		End:             time.Now(),
+		Outcome:         outcome,
+		Attempt:         t.attemptNumber(),
+		Error:           failure,
+		Backoff:         t.backoff,
+		Backfill:        t.backfill,
+		Manual:          t.manual,
+		Coalesced:       t.coalesced,
+		Sourced:         t.sourced,
+		Reason:          t.reason,
+		Labels:          t.labels,
+		TraceID:         p.TraceID(),
+		Output:          output,
+		OutputTruncated: truncated,
+	}
+	if outcome == OutcomeSuccess {
+		e.lastSuccess = &r
+	}
+	c.mu.Unlock()
+	retry = c.retrying(e, t, outcome)
+	c.over(e, t, outcome)
+	if retry {
+		// The caller of RunNow waits for the last attempt, which this one is
+		// after all if the scheduler stops during the backoff.
+		c.recordRun(r, t.view.Metadata)
+		next, backoff := t.retry()
+		c.debug(SubsystemDispatch, "run failed, retrying", "entry", e.Name, "scheduled", t.scheduled, "attempt", next.attempt, "backoff", backoff)
+		if c.sleep(backoff) {
+			return true
+		}
+		c.debug(SubsystemDispatch, "scheduler stopped, retry given up", "entry", e.Name, "scheduled", t.scheduled, "attempt", next.attempt)
+		if t.done != nil {
+			t.done <- r
+		}
+		return false
+	}
+	c.finish(t, r)
+	return false
+}
+
+// sleep waits for d, such as the backoff before a retry, telling whether to
+// go on after it: not once the scheduler is stopped or shut down meanwhile.
+func (c *Cron) sleep(d time.Duration) bool {
+	c.mu.Lock()
+	halt := c.halt
+	c.mu.Unlock()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-halt:
+		return false
+	case <-c.runContext().Done():
+		return false
+	}
+}
+
+// over accounts for a run of e that ended with outcome, before it is
+// recorded.
+func (c *Cron) over(e *Entry, t trigger, outcome Outcome) {
+	if outcome.skipped() {
+		c.debug(SubsystemDispatch, "run skipped", "entry", e.Name, "scheduled", t.scheduled, "outcome", outcome)
+	} else {
+		c.debug(SubsystemJobs, "run over", "entry", e.Name, "version", t.view.Version, "scheduled", t.scheduled, "outcome", outcome)
+	}
+	c.mu.Lock()
+	e.stats.count(outcome)
+	c.mu.Unlock()
+	c.canaryOver(e, t, outcome)
+	c.switchSchedule(e, outcome)
+	c.breakCircuit(e, t, outcome)
+}
+
+// skipped returns the record of a run of t whose job did not run.
+func skipped(t trigger, outcome Outcome) RunRecord {
+	now := time.Now()
+	return RunRecord{
+		Name:      t.view.Name,
+		Version:   t.view.Version,
+		Scheduled: t.scheduled,
+		Start:     now,
+		End:       now,
+		Outcome:   outcome,
+		Attempt:   t.attemptNumber(),
+		Backoff:   t.backoff,
+		Backfill:  t.backfill,
+		Manual:    t.manual,
+		Coalesced: t.coalesced,
+		Sourced:   t.sourced,
+		Reason:    reasonOf(outcome),
+		Labels:    t.labels,
+	}
+}
+
+// finish records the last attempt at the run of t and hands the record to
+// RunNow, if waiting.
+func (c *Cron) finish(t trigger, r RunRecord) {
+	c.recordRun(r, t.view.Metadata)
+	if t.done != nil {
+		t.done <- r
+	}
+}
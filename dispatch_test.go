@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Serialized runs never overlap, and triggers past the cap are dropped.
+func TestOverlapSerializeWithMaxPending(t *testing.T) {
+	cron := New()
+	var running, overlapped int32
+	release := make(chan struct{})
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), 50*time.Millisecond, func() {
+		if atomic.AddInt32(&running, 1) > 1 {
+			atomic.StoreInt32(&overlapped, 1)
+		}
+		<-release
+		atomic.AddInt32(&running, -1)
+	}, "serial", WithOverlap(OverlapSerialize), WithMaxPending(2))
+	cron.Start()
+	defer cron.Stop()
+
+	// The first run blocks while about 8 more come due.
+	time.Sleep(500 * time.Millisecond)
+	e := cron.Entries()[0]
+	if e.Pending != 2 {
+		t.Errorf("expected 2 pending runs, got %d", e.Pending)
+	}
+	if e.DroppedTriggers < 3 {
+		t.Errorf("expected dropped triggers, got %d", e.DroppedTriggers)
+	}
+	close(release)
+
+	waitForHistory(t, cron, "serial", 3)
+	if atomic.LoadInt32(&overlapped) != 0 {
+		t.Error("serialized runs overlapped")
+	}
+}
+
+// Runs waiting for a worker count against the cap too.
+func TestMaxPendingWithWorkerPool(t *testing.T) {
+	pool := NewWorkerPool(1)
+	cron := New(WithWorkerPool(pool))
+	release := make(chan struct{})
+	defer close(release)
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), 50*time.Millisecond, func() {
+		<-release
+	}, "pooled", WithMaxPending(1))
+	cron.Start()
+	defer cron.Stop()
+
+	time.Sleep(400 * time.Millisecond)
+	e := cron.Entries()[0]
+	if e.Pending != 1 || e.DroppedTriggers == 0 {
+		t.Errorf("expected 1 pending run and dropped triggers, got %d and %d", e.Pending, e.DroppedTriggers)
+	}
+}
+
+// Up to MaxConcurrent runs go at once, and those past the limit are queued
+// or, under OverlapSkip, dropped.
+func TestMaxConcurrent(t *testing.T) {
+	for _, policy := range []OverlapPolicy{OverlapAllow, OverlapSkip} {
+		cron := New()
+		var mu sync.Mutex
+		running, most := 0, 0
+		release := make(chan struct{})
+		cron.AddFunc(time.Now().Add(50*time.Millisecond), 50*time.Millisecond, func() {
+			mu.Lock()
+			running++
+			most = max(most, running)
+			mu.Unlock()
+			<-release
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}, "limited", WithMaxConcurrent(2), WithOverlap(policy))
+		cron.Start()
+
+		time.Sleep(400 * time.Millisecond)
+		e, _ := cron.Entry("limited")
+		mu.Lock()
+		if most != 2 {
+			t.Errorf("policy %d: expected 2 runs at once, got %d", policy, most)
+		}
+		mu.Unlock()
+		if policy == OverlapSkip && (e.Pending != 0 || e.DroppedTriggers == 0) {
+			t.Errorf("expected the runs past the limit dropped, got %d pending and %d dropped", e.Pending, e.DroppedTriggers)
+		}
+		if policy == OverlapAllow && (e.Pending == 0 || e.DroppedTriggers != 0) {
+			t.Errorf("expected the runs past the limit queued, got %d pending and %d dropped", e.Pending, e.DroppedTriggers)
+		}
+		cron.Stop()
+		close(release)
+	}
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateName is returned for an entry added under a name that is
+// taken, under DuplicateError.
+var ErrDuplicateName = errors.New("scheduler: duplicate entry name")
+
+// DuplicatePolicy decides what adding an entry under a name that is taken
+// does. UpdateJob and Rollback always replace the entry.
+type DuplicatePolicy int
+
+const (
+	// Replace the entry, bumping its version, as UpdateJob does.
+	DuplicateReplace DuplicatePolicy = iota
+
+	// Refuse the new entry with ErrDuplicateName.
+	DuplicateError
+
+	// Keep both, adding the new entry as "name#2", "name#3" and so on.
+	DuplicateSuffix
+)
+
+func (p DuplicatePolicy) String() string {
+	switch p {
+	case DuplicateReplace:
+		return "replace"
+	case DuplicateError:
+		return "error"
+	case DuplicateSuffix:
+		return "suffix"
+	}
+	return fmt.Sprintf("DuplicatePolicy(%d)", int(p))
+}
+
+// DuplicatePolicy returns the policy in force, see WithDuplicatePolicy.
+func (c *Cron) DuplicatePolicy() DuplicatePolicy {
+	return c.duplicates
+}
+
+// checkDuplicate applies the DuplicatePolicy to the entry about to be put,
+// renaming it under DuplicateSuffix.
+func (c *Cron) checkDuplicate(entry *Entry) error {
+	if entry.update || entry.rollback || c.lookup(entry.Name) == nil {
+		return nil
+	}
+	switch c.duplicates {
+	case DuplicateError:
+		return fmt.Errorf("%w: %q", ErrDuplicateName, entry.Name)
+	case DuplicateSuffix:
+		name := entry.Name
+		for n := 2; c.lookup(entry.Name) != nil; n++ {
+			entry.Name = fmt.Sprintf("%s#%d", name, n)
+		}
+		c.log(SubsystemLifecycle).Info("name taken, adding entry under another", "entry", name, "as", entry.Name)
+	default:
+		c.log(SubsystemLifecycle).Info("replacing entry", "entry", entry.Name)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDuplicatePolicy(t *testing.T) {
+	start := time.Now().Add(time.Hour)
+	for _, tc := range []struct {
+		policy DuplicatePolicy
+		err    error
+		names  []string
+	}{
+		{DuplicateReplace, nil, []string{"job"}},
+		{DuplicateError, ErrDuplicateName, []string{"job"}},
+		{DuplicateSuffix, nil, []string{"job", "job#2", "job#3"}},
+	} {
+		cron := New(WithDuplicatePolicy(tc.policy))
+		if cron.DuplicatePolicy() != tc.policy {
+			t.Errorf("%v: expected the policy to be exposed", tc.policy)
+		}
+		cron.AddFunc(start, time.Hour, func() {}, "job")
+		cron.Start()
+		err := cron.AddFunc(start, time.Hour, func() {}, "job")
+		cron.AddFunc(start, time.Hour, func() {}, "job")
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%v: expected %v, got %v", tc.policy, tc.err, err)
+		}
+		var names []string
+		for _, e := range cron.Entries() {
+			names = append(names, e.Name)
+		}
+		if len(names) != len(tc.names) {
+			t.Errorf("%v: expected %v, got %v", tc.policy, tc.names, names)
+		}
+		cron.Stop()
+	}
+}
+
+// UpdateJob replaces the entry whatever the policy.
+func TestDuplicatePolicyUpdate(t *testing.T) {
+	cron := New(WithDuplicatePolicy(DuplicateError))
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "job")
+	if err := cron.UpdateJob(time.Now().Add(time.Hour), time.Hour, FuncJob(func() {}), "job"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cron.Rollback("job", 1); err != nil {
+		t.Fatal(err)
+	}
+	if e := cron.Entries(); len(e) != 1 || e[0].Version != 3 {
+		t.Errorf("expected one entry at version 3, got %v", e)
+	}
+}
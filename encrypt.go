@@ -0,0 +1,202 @@
+package scheduler
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// KeyWrapper wraps the data keys of an EncryptedStore under key encryption
+// keys, which usually live in a KMS.
+type KeyWrapper interface {
+	// WrapKey encrypts a data key under the current key encryption key and
+	// returns the id of that key along with the wrapped data key.
+	WrapKey(dataKey []byte) (keyID string, wrapped []byte, err error)
+
+	// UnwrapKey decrypts a data key wrapped under the key with the given id.
+	UnwrapKey(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// EncryptedStore is a JobStore that envelope encrypts the values it puts in
+// another one: each value is sealed with AES-GCM under a fresh data key,
+// which is stored next to it wrapped by a KeyWrapper. Keys are stored as is.
+type EncryptedStore struct {
+	store JobStore
+	keys  KeyWrapper
+}
+
+// NewEncryptedStore returns a JobStore encrypting the values it puts in store
+// with data keys wrapped by keys.
+func NewEncryptedStore(store JobStore, keys KeyWrapper) *EncryptedStore {
+	return &EncryptedStore{store: store, keys: keys}
+}
+
+// envelope is how EncryptedStore stores a value.
+type envelope struct {
+	KeyID string `json:"kid"`
+	Key   []byte `json:"key"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+func (s *EncryptedStore) Get(key string) ([]byte, error) {
+	raw, err := s.store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return nil, fmt.Errorf("scheduler: decoding envelope of %q: %w", key, err)
+	}
+	dataKey, err := s.keys.UnwrapKey(env.KeyID, env.Key)
+	if err != nil {
+		return nil, fmt.Errorf("scheduler: unwrapping data key of %q: %w", key, err)
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	value, err := aead.Open(nil, env.Nonce, env.Data, []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("scheduler: decrypting %q: %w", key, err)
+	}
+	return value, nil
+}
+
+func (s *EncryptedStore) Put(key string, value []byte) error {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return err
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return err
+	}
+	env := envelope{Nonce: make([]byte, aead.NonceSize())}
+	if _, err := rand.Read(env.Nonce); err != nil {
+		return err
+	}
+	// The key is authenticated along with the value, so a value can't be
+	// moved to another key.
+	env.Data = aead.Seal(nil, env.Nonce, value, []byte(key))
+	if env.KeyID, env.Key, err = s.keys.WrapKey(dataKey); err != nil {
+		return fmt.Errorf("scheduler: wrapping data key of %q: %w", key, err)
+	}
+	raw, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	return s.store.Put(key, raw)
+}
+
+func (s *EncryptedStore) Delete(key string) error {
+	return s.store.Delete(key)
+}
+
+func (s *EncryptedStore) Keys(prefix string) ([]string, error) {
+	return s.store.Keys(prefix)
+}
+
+// Rotate encrypts every value again, so that they all end up under the
+// current key encryption key and the older ones can be retired.
+func (s *EncryptedStore) Rotate() error {
+	keys, err := s.store.Keys("")
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		value, err := s.Get(key)
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.Put(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Keyring is a KeyWrapper with its key encryption keys in memory. It wraps
+// under the primary key and unwraps under any key it holds, so rotating is
+// adding a key, making it primary, calling EncryptedStore.Rotate, and then
+// removing the old key.
+type Keyring struct {
+	mu      sync.RWMutex
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring returns an empty Keyring.
+func NewKeyring() *Keyring {
+	return &Keyring{keys: make(map[string]cipher.AEAD)}
+}
+
+// Add adds a 16, 24 or 32 byte AES key under id, making it the primary key
+// if primary is set or if it is the first key.
+func (k *Keyring) Add(id string, key []byte, primary bool) error {
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[id] = aead
+	if primary || k.primary == "" {
+		k.primary = id
+	}
+	return nil
+}
+
+// Remove removes the key with the given id. The primary key can't be
+// removed.
+func (k *Keyring) Remove(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if id == k.primary {
+		return fmt.Errorf("scheduler: can't remove the primary key %q", id)
+	}
+	delete(k.keys, id)
+	return nil
+}
+
+func (k *Keyring) WrapKey(dataKey []byte) (string, []byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	aead, ok := k.keys[k.primary]
+	if !ok {
+		return "", nil, fmt.Errorf("scheduler: keyring has no primary key")
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+	return k.primary, aead.Seal(nonce, nonce, dataKey, nil), nil
+}
+
+func (k *Keyring) UnwrapKey(id string, wrapped []byte) ([]byte, error) {
+	k.mu.RLock()
+	aead, ok := k.keys[id]
+	k.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("scheduler: keyring has no key %q", id)
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, fmt.Errorf("scheduler: wrapped key too short")
+	}
+	nonce, sealed := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, nil)
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrJob is a job whose runs fail by returning an error, rather than by
+// panicking. A run that returns one ends with OutcomeError, so retries,
+// circuit breakers and alerts apply to it as to a panic, and the error is
+// in its RunRecord and goes to the error handler, see WithErrorHandler.
+type ErrJob interface {
+	Run() error
+}
+
+// A wrapper that turns a func() error into an ErrJob.
+type ErrFuncJob func() error
+
+func (f ErrFuncJob) Run() error { return f() }
+
+// AddErrFunc adds a func that may fail with an error to the Cron to be run
+// on the given schedule.
+func (c *Cron) AddErrFunc(startTime time.Time, Interval time.Duration, cmd func() error, name string, opts ...EntryOption) error {
+	return c.AddErrJob(startTime, Interval, ErrFuncJob(cmd), name, opts...)
+}
+
+// AddErrJob adds an ErrJob to the Cron to be run on the given schedule.
+func (c *Cron) AddErrJob(startTime time.Time, Interval time.Duration, cmd ErrJob, name string, opts ...EntryOption) error {
+	return c.AddProgressJob(startTime, Interval, errJob{cmd}, name, opts...)
+}
+
+// errJob adapts an ErrJob to a ProgressJob, whose Progress keeps the error.
+type errJob struct {
+	job ErrJob
+}
+
+func (j errJob) Run(p *Progress) {
+	if err := j.job.Run(); err != nil {
+		fmt.Fprintf(p.Output(), "error: %v\n", err)
+		p.Logger().Error(err, "job failed")
+		p.fail(err)
+	}
+}
+
+// fail records the error the run failed with.
+func (p *Progress) fail(err error) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.err = err
+	p.mu.Unlock()
+}
+
+// failure returns the error the run failed with, if any.
+func (p *Progress) failure() error {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
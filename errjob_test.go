@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrJob(t *testing.T) {
+	errFull := errors.New("disk full")
+	handled := make(chan error, 1)
+	cron := New(WithErrorHandler(func(name string, err interface{}) {
+		if name == "backup" {
+			handled <- err.(error)
+		}
+	}))
+	calls := 0
+	cron.AddErrFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() error {
+		calls++
+		if calls == 1 {
+			return errFull
+		}
+		return nil
+	}, "backup", WithRetries(1, 10*time.Millisecond))
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case err := <-handled:
+		if err != errFull {
+			t.Errorf("expected the error of the job, got %v", err)
+		}
+	case <-time.After(ONE_SECOND):
+		t.Fatal("the error handler was not called")
+	}
+	runs := waitForHistory(t, cron, "backup", 2)
+	if runs[0].Outcome != OutcomeError || runs[0].Error != "disk full" || !strings.Contains(runs[0].Output, "error: disk full") {
+		t.Errorf("unexpected failed run %+v", runs[0])
+	}
+	if runs[1].Outcome != OutcomeSuccess {
+		t.Errorf("expected the retry to succeed, got %+v", runs[1])
+	}
+	if e, _ := cron.Entry("backup"); e.Panics != 0 {
+		t.Errorf("expected an error not to count as a panic, got %d", e.Panics)
+	}
+}
@@ -0,0 +1,95 @@
+package scheduler
+
+import "time"
+
+// EventType tells what an Event is about.
+type EventType string
+
+const (
+	// The entry was added.
+	EventAdded EventType = "added"
+
+	// The entry was redefined, see UpdateJob.
+	EventUpdated EventType = "updated"
+
+	// The entry was put back to a previous definition, see Rollback.
+	EventRolledBack EventType = "rolled_back"
+
+	// The new definition of the entry passed its canary, see WithCanary.
+	EventPromoted EventType = "promoted"
+
+	// The entry was removed.
+	EventRemoved EventType = "removed"
+
+	// The entry ran its last run and was archived, see WithMaxRuns.
+	EventRetired EventType = "retired"
+
+	// A run of the entry is over, see Event.Run.
+	EventRun EventType = "run"
+
+	// The maintenance window named by the event opened or closed, see
+	// AddMaintenanceWindow.
+	EventMaintenanceStarted EventType = "maintenance_started"
+	EventMaintenanceEnded   EventType = "maintenance_ended"
+
+	// A trigger of the entry was ignored before it became a run, for
+	// Event.Reason: the entry is disabled, or too many runs are pending.
+	EventTriggerSkipped EventType = "trigger_skipped"
+
+	// The runs of the entry failed too many times in a row and it was
+	// paused, or a probe run succeeded and it runs again. See
+	// WithCircuitBreaker.
+	EventCircuitOpened EventType = "circuit_opened"
+	EventCircuitClosed EventType = "circuit_closed"
+)
+
+// Event is something that happened to an entry.
+type Event struct {
+	Type EventType
+	Time time.Time
+
+	// The entry, and the version of its definition the event is about. For
+	// the maintenance events, Name is that of the window.
+	Name    string
+	Version int
+
+	// The metadata of the entry, see WithMetadata. It is shared, so it must
+	// not be modified.
+	Metadata map[string]string
+
+	// Why the trigger was skipped, for EventTriggerSkipped, or why the run
+	// was skipped, deferred or coalesced, for EventRun.
+	Reason Reason
+
+	// The run, for EventRun.
+	Run *RunRecord
+}
+
+// How many events may be waiting for the handler before the scheduler
+// waits for it.
+const eventBuffer = 1024
+
+// emit passes e on to the event handler and the event log, if any.
+func (c *Cron) emit(e Event) {
+	if c.onEvent == nil && c.eventLog == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	c.eventsMu.Lock()
+	if c.events == nil {
+		c.events = make(chan Event, eventBuffer)
+		go func(events <-chan Event) {
+			for e := range events {
+				c.writeEvent(e)
+				if c.onEvent != nil {
+					c.onEvent(e)
+				}
+			}
+		}(c.events)
+	}
+	events := c.events
+	c.eventsMu.Unlock()
+	events <- e
+}
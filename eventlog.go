@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// eventLine is how WithEventLog writes an Event.
+type eventLine struct {
+	Time     time.Time         `json:"time"`
+	Type     EventType         `json:"type"`
+	Name     string            `json:"name,omitempty"`
+	Version  int               `json:"version,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Reason   Reason            `json:"reason,omitempty"`
+	Run      *eventRun         `json:"run,omitempty"`
+}
+
+// eventRun is the run of an EventRun line, without its output.
+type eventRun struct {
+	Scheduled  time.Time         `json:"scheduled"`
+	Start      time.Time         `json:"start"`
+	End        time.Time         `json:"end"`
+	DurationMs int64             `json:"duration_ms"`
+	Outcome    Outcome           `json:"outcome"`
+	Attempt    int               `json:"attempt"`
+	Error      string            `json:"error,omitempty"`
+	BackoffMs  int64             `json:"backoff_ms,omitempty"`
+	Backfill   bool              `json:"backfill,omitempty"`
+	Manual     bool              `json:"manual,omitempty"`
+	Coalesced  bool              `json:"coalesced,omitempty"`
+	Sourced    bool              `json:"sourced,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
+// eventLog writes the events to a writer, one JSON line each.
+type eventLog struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// newEventLog returns an eventLog writing to w.
+func newEventLog(w io.Writer) *eventLog {
+	return &eventLog{enc: json.NewEncoder(w)}
+}
+
+// writeEvent writes e to the event log, if any. It is called from the goroutine
+// of the event handler, so the lines come in order.
+func (c *Cron) writeEvent(e Event) {
+	l := c.eventLog
+	if l == nil {
+		return
+	}
+	line := eventLine{Time: e.Time, Type: e.Type, Name: e.Name, Version: e.Version, Metadata: e.Metadata, Reason: e.Reason}
+	if r := e.Run; r != nil {
+		line.Run = &eventRun{
+			Scheduled:  r.Scheduled,
+			Start:      r.Start,
+			End:        r.End,
+			DurationMs: r.End.Sub(r.Start).Milliseconds(),
+			Outcome:    r.Outcome,
+			Attempt:    r.Attempt,
+			Error:      r.Error,
+			BackoffMs:  r.Backoff.Milliseconds(),
+			Backfill:   r.Backfill,
+			Manual:     r.Manual,
+			Coalesced:  r.Coalesced,
+			Sourced:    r.Sourced,
+			Labels:     r.Labels,
+		}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.enc.Encode(line); err != nil {
+		c.log(SubsystemLifecycle).Error(err, "writing event log failed, event log stopped")
+		l.enc = json.NewEncoder(io.Discard)
+	}
+}
@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"bufio"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventLog(t *testing.T) {
+	var out syncBuffer
+	events := make(chan Event, 10)
+	cron := New(WithEventLog(&out), WithEventHandler(func(e Event) { events <- e }))
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() {}, "job", WithMetadata(map[string]string{"team": "data"}))
+	cron.Start()
+	defer cron.Stop()
+
+	waitForEvent(t, events, EventRun)
+	var lines []map[string]interface{}
+	s := bufio.NewScanner(strings.NewReader(out.String()))
+	for s.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(s.Bytes(), &line); err != nil {
+			t.Fatalf("%q: %v", s.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 || lines[0]["type"] != "added" || lines[1]["type"] != "run" {
+		t.Fatalf("expected an added and a run line, got %v", lines)
+	}
+	if lines[1]["name"] != "job" || lines[1]["metadata"].(map[string]interface{})["team"] != "data" {
+		t.Errorf("unexpected run line %v", lines[1])
+	}
+	run, _ := lines[1]["run"].(map[string]interface{})
+	if run["outcome"] != string(OutcomeSuccess) {
+		t.Errorf("unexpected run %v", run)
+	}
+}
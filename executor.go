@@ -0,0 +1,122 @@
+package scheduler
+
+import "sync"
+
+// Executor decides where the runs the scheduler dispatches are carried out.
+// The default, GoExecutor, gives each run a goroutine of its own. Executors
+// are set for the scheduler with WithExecutor and for an entry with
+// WithEntryExecutor. Implementations must be safe for concurrent use.
+type Executor interface {
+	// Execute carries out r, by calling r.Do once, now or later and in any
+	// goroutine. It is called by the run loop, so it should not block.
+	Execute(r *Run)
+}
+
+// Run is a dispatched run of an entry, handed to an Executor. Do runs the
+// job, along with everything around it: the policies of the scheduler and
+// the entry, the retries and the runs queued behind it, and its record in
+// the history.
+type Run struct {
+	// Describes the run, as Progress.Trigger would. Sent is when it was
+	// dispatched.
+	Trigger TriggerMessage
+
+	do func()
+}
+
+// Do carries out the run.
+func (r *Run) Do() { r.do() }
+
+// ExecutorFunc is an Executor calling itself.
+type ExecutorFunc func(r *Run)
+
+func (f ExecutorFunc) Execute(r *Run) { f(r) }
+
+// GoExecutor runs each run in a goroutine of its own. It is the default.
+var GoExecutor Executor = ExecutorFunc(func(r *Run) { go r.Do() })
+
+// InlineExecutor runs each run in the goroutine that dispatches it, so the
+// run loop waits for the run to be over before it goes on. It is meant for
+// tests, along with a FakeClock, and for jobs that only hand their work on:
+// the job must not call back into the Cron, which would deadlock.
+var InlineExecutor Executor = ExecutorFunc(func(r *Run) { r.Do() })
+
+// PoolExecutor runs the runs on a bounded number of goroutines. Runs that
+// find them all busy are queued, in order, and the goroutines exit once
+// the queue is empty. Unlike a WorkerPool it bounds goroutines rather than
+// jobs: a queued run has not started, so it is neither pending nor held up
+// by its resources.
+type PoolExecutor struct {
+	mu      sync.Mutex
+	size    int
+	workers int
+	queue   []*Run
+}
+
+// NewPoolExecutor returns an executor of size goroutines.
+func NewPoolExecutor(size int) *PoolExecutor {
+	if size < 1 {
+		size = 1
+	}
+	return &PoolExecutor{size: size}
+}
+
+func (p *PoolExecutor) Execute(r *Run) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queue = append(p.queue, r)
+	if p.workers < p.size {
+		p.workers++
+		go p.work()
+	}
+}
+
+// Queued returns the number of runs waiting for a goroutine.
+func (p *PoolExecutor) Queued() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.queue)
+}
+
+func (p *PoolExecutor) work() {
+	for {
+		p.mu.Lock()
+		if len(p.queue) == 0 {
+			p.workers--
+			p.mu.Unlock()
+			return
+		}
+		r := p.queue[0]
+		p.queue = p.queue[1:]
+		p.mu.Unlock()
+		r.Do()
+	}
+}
+
+// QueueExecutor hands the runs over to a queue, for consumers that carry
+// them out on their own terms, such as a bridge to remote workers that
+// sends r.Trigger on and calls r.Do with a job waiting for the worker's
+// result. Execute blocks while the channel is full, which holds up the run
+// loop, so it should be buffered.
+type QueueExecutor chan *Run
+
+func (q QueueExecutor) Execute(r *Run) { q <- r }
+
+// WithEntryExecutor carries out the runs of the entry with x, in place of
+// the executor of the scheduler.
+func WithEntryExecutor(x Executor) EntryOption {
+	return func(e *Entry) {
+		e.Executor = x
+	}
+}
+
+// executorOf returns the executor of the runs of e.
+func (c *Cron) executorOf(e *Entry) Executor {
+	switch {
+	case e.Executor != nil:
+		return e.Executor
+	case c.executor != nil:
+		return c.executor
+	}
+	return GoExecutor
+}
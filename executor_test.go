@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPoolExecutor(t *testing.T) {
+	x := NewPoolExecutor(1)
+	cron := New(WithExecutor(x))
+	var mu sync.Mutex
+	var running, most int
+	job := func() {
+		mu.Lock()
+		running++
+		most = max(most, running)
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+	}
+	start := time.Now().Add(50 * time.Millisecond)
+	for _, name := range []string{"a", "b", "c"} {
+		cron.AddFunc(start, time.Hour, job, name)
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	for _, name := range []string{"a", "b", "c"} {
+		waitForHistory(t, cron, name, 1)
+	}
+	if most != 1 {
+		t.Errorf("expected the runs one at a time, got %d at once", most)
+	}
+}
+
+func TestInlineExecutor(t *testing.T) {
+	t0 := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	cron := New(WithClock(NewFakeClock(t0)), WithExecutor(InlineExecutor))
+	var order []string
+	cron.AddFunc(t0.Add(time.Minute), 2*time.Minute, func() { order = append(order, "a") }, "a")
+	cron.AddFunc(t0.Add(2*time.Minute), 3*time.Minute, func() { order = append(order, "b") }, "b")
+	defer cron.Stop()
+
+	if err := cron.AdvanceTo(t0.Add(6 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	// a at 1, 3 and 5 minutes, b at 2 and 5.
+	if got := len(order); got != 5 || order[0] != "a" || order[1] != "b" || order[2] != "a" {
+		t.Errorf("unexpected order %v", order)
+	}
+}
+
+func TestQueueExecutor(t *testing.T) {
+	q := make(QueueExecutor, 1)
+	cron := New()
+	ran := make(chan struct{}, 1)
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() { ran <- struct{}{} }, "remote", WithEntryExecutor(q))
+	cron.Start()
+	defer cron.Stop()
+
+	var r *Run
+	select {
+	case r = <-q:
+	case <-time.After(time.Second):
+		t.Fatal("expected the run on the queue")
+	}
+	if r.Trigger.Name != "remote" || r.Trigger.Version != 1 || r.Trigger.Attempt != 1 {
+		t.Errorf("unexpected trigger %+v", r.Trigger)
+	}
+	select {
+	case <-ran:
+		t.Fatal("expected the run to wait for the consumer")
+	default:
+	}
+	go r.Do()
+	<-ran
+	waitForHistory(t, cron, "remote", 1)
+}
@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Explain describes, line by line, how the next run of the named entry was
+// worked out: what it is anchored on, the nominal time its schedule gives,
+// and how the phase of WithSpread, the preferred window and the state of
+// the entry and the scheduler move or hold it, to answer why an entry did
+// or didn't run at some time. It is meant for people; its wording may
+// change.
+func (c *Cron) Explain(name string) string {
+	var s string
+	c.inLoop(func() {
+		e := c.lookup(name)
+		if e == nil {
+			s = fmt.Sprintf("no entry %q\n", name)
+			return
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		s = c.explain(e)
+	})
+	return s
+}
+
+// explain returns Explain for e. The caller must be in the run loop and hold
+// c.mu.
+func (c *Cron) explain(e *Entry) string {
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		fmt.Fprintf(&b, format+"\n", args...)
+	}
+	at := func(t time.Time) string {
+		return e.DisplayTime(t).Format(time.RFC3339)
+	}
+	line("entry %q, version %d", e.Name, e.Version)
+
+	switch {
+	case e.failing && e.FailureInterval > 0:
+		line("anchor: the last run failed, so it runs every %s until one succeeds, see WithFailureInterval", e.FailureInterval)
+	case e.Schedule != nil:
+		loc := e.location
+		if loc == nil {
+			loc = time.Local
+		}
+		line("anchor: schedule %v, in %s", e.Schedule, loc)
+	case e.Interval <= 0:
+		line("anchor: one-shot at %s, however late", at(e.setStartTime))
+	default:
+		line("anchor: every %s from %s", e.Interval, at(e.setStartTime))
+	}
+
+	if e.nominal.IsZero() {
+		line("next run: not worked out yet, the scheduler has not started")
+		return b.String()
+	}
+	if e.NextTime.IsZero() {
+		line("next run: none, the schedule has no further occurrence")
+		return b.String()
+	}
+	if e.Schedule == nil && e.Interval > 0 && !(e.failing && e.FailureInterval > 0) {
+		n := e.nominal.Sub(e.setStartTime) / e.Interval
+		line("nominal: %s, the start time + %d × %s", at(e.nominal), n, e.Interval)
+		if n > 0 && e.Runs == 0 {
+			line("catch-up: intervals that passed before the entry was scheduled are skipped, not run")
+		}
+	} else {
+		line("nominal: %s", at(e.nominal))
+	}
+
+	placed := e.nominal
+	if e.Phase != 0 {
+		placed = placed.Add(e.Phase)
+		line("phase: %+v, to spread it with the entries of its interval, see WithSpread", e.Phase)
+	}
+	if e.Flex > 0 {
+		w := e.Preferred
+		line("flex: may start up to %s late to land in its preferred window %s-%s", e.Flex, clockOffset(w.From), clockOffset(w.To))
+		if shift := e.NextTime.Sub(placed); shift > 0 {
+			line("window: moved %s later, to its spot in the window", shift)
+		} else {
+			line("window: left as is, the window is out of reach or already there")
+		}
+	}
+	line("next run: %s", at(e.NextTime))
+
+	switch {
+	case e.Circuit == CircuitOpen:
+		line("held: the circuit is open after %d failures in a row, it won't run until the cool-down, see WithCircuitBreaker", e.failures)
+	case e.Disabled:
+		line("held: the entry is disabled, it won't run until resumed")
+	case c.held:
+		line("held: the scheduler is quiescing, runs are held back")
+	}
+	if missing := c.missingLabels(e); missing != nil {
+		line("affinity: this instance lacks the labels %s, so the entry runs elsewhere, see WithAffinity", strings.Join(missing, ", "))
+	}
+	if e.MaxRuns > 0 {
+		line("runs: %d of at most %d", e.Runs, e.MaxRuns)
+	}
+	names := make([]string, 0, len(c.maintenance))
+	for name := range c.maintenance {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if w := c.maintenance[name].window; w.pauses(e) && w.open(e.NextTime) {
+			line("maintenance: window %q is open then, so the run will be skipped as paused", name)
+		}
+	}
+	if c.stormThreshold > 0 {
+		line("storm: when more than %d entries come due at once, their runs are smeared over %s", c.stormThreshold, c.stormSmear)
+	}
+	return b.String()
+}
+
+// clockOffset formats an offset from midnight as hh:mm.
+func clockOffset(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
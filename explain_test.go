@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExplain(t *testing.T) {
+	cron := New(WithLocation(time.UTC))
+	start := time.Now().UTC().Truncate(time.Hour).Add(-5 * time.Hour)
+	cron.AddFunc(start, time.Hour, func() {}, "hourly", WithTags("db"))
+	cron.AddFuncOn(Daily(2, 30, 0), func() {}, "nightly", WithPreferredWindow(3*time.Hour, 4*time.Hour, 2*time.Hour))
+	cron.AddMaintenanceWindow(MaintenanceWindow{Name: "always", Tags: []string{"db"}, Start: Merge(everyMinute{}), Duration: time.Hour})
+
+	if got := cron.Explain("hourly"); !strings.Contains(got, "not worked out yet") {
+		t.Errorf("expected the entry not to be scheduled before Start, got\n%s", got)
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	got := cron.Explain("hourly")
+	for _, want := range []string{
+		`entry "hourly"`,
+		"anchor: every 1h0m0s from " + start.Format(time.RFC3339),
+		"the start time + 6 × 1h0m0s",
+		"catch-up: intervals that passed",
+		"next run: " + start.Add(6*time.Hour).Format(time.RFC3339),
+		`maintenance: window "always" is open then`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in\n%s", want, got)
+		}
+	}
+	got = cron.Explain("nightly")
+	for _, want := range []string{"anchor: schedule daily 02:30, in UTC", "preferred window 03:00-04:00", "window: moved"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in\n%s", want, got)
+		}
+	}
+	if got := cron.Explain("missing"); got != "no entry \"missing\"\n" {
+		t.Errorf("unexpected %q", got)
+	}
+}
+
+// everyMinute is a Schedule firing on every minute.
+type everyMinute struct{}
+
+func (everyMinute) Next(t time.Time) time.Time { return t.Truncate(time.Minute).Add(time.Minute) }
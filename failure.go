@@ -0,0 +1,43 @@
+package scheduler
+
+import "time"
+
+// WithFailureInterval gives the entry another interval to run at once a run
+// fails, such as every 5 minutes for an hourly entry, until a run succeeds
+// and the entry goes back to its schedule.
+func WithFailureInterval(d time.Duration) EntryOption {
+	return func(e *Entry) {
+		e.FailureInterval = d
+	}
+}
+
+// failed reports whether the job ran and did not succeed.
+func (o Outcome) failed() bool {
+	return o != OutcomeSuccess && !o.skipped()
+}
+
+// switchSchedule puts e on its failure interval after a failed run, and
+// back on its schedule after a successful one, see WithFailureInterval.
+func (c *Cron) switchSchedule(e *Entry, outcome Outcome) {
+	if e.FailureInterval <= 0 || outcome.skipped() {
+		return
+	}
+	// The schedule belongs to the run loop, which may be waiting on this run
+	// to stop.
+	go c.inLoop(func() {
+		if c.lookup(e.Name) != e || e.index < 0 || e.failing == outcome.failed() {
+			return
+		}
+		e.failing = outcome.failed()
+		if e.failing {
+			c.log(SubsystemLifecycle).Info("run failed, switching to the failure interval", "entry", e.Name, "interval", e.FailureInterval)
+		} else {
+			c.log(SubsystemLifecycle).Info("run succeeded, switching back to the schedule", "entry", e.Name)
+		}
+		// Restart the schedule from now, so that Next puts the entry on the
+		// failure interval, or on the next time of its schedule.
+		e.nominal = time.Time{}
+		e.Next()
+		c.reschedule(e)
+	})
+}
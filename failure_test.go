@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFailureInterval(t *testing.T) {
+	cron := New()
+	var runs int32
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() {
+		// Fail twice, then succeed.
+		if atomic.AddInt32(&runs, 1) <= 2 {
+			panic("flaky")
+		}
+	}, "flaky", WithFailureInterval(100*time.Millisecond))
+	cron.Start()
+	defer cron.Stop()
+
+	history := waitForHistory(t, cron, "flaky", 3)
+	for i, want := range []Outcome{OutcomePanic, OutcomePanic, OutcomeSuccess} {
+		if history[i].Outcome != want {
+			t.Errorf("expected run %d to end with %s, got %s", i, want, history[i].Outcome)
+		}
+	}
+	if gap := history[1].Start.Sub(history[0].Start); gap < 50*time.Millisecond || gap > 500*time.Millisecond {
+		t.Errorf("expected the retry on the failure interval, got %v later", gap)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if n := len(cron.History("flaky")); n != 3 {
+		t.Errorf("expected no more runs once back on the schedule, got %d", n)
+	}
+	if next := cron.Entries()[0].NextTime; time.Until(next) < 30*time.Minute {
+		t.Errorf("expected the entry back on its hourly schedule, next at %v", next)
+	}
+}
@@ -0,0 +1,53 @@
+package scheduler
+
+import "time"
+
+const (
+	// How long WithTriggerFence holds a run at most, and how often it reads
+	// the cluster clock meanwhile.
+	fenceHold = time.Minute
+	fencePoll = 100 * time.Millisecond
+)
+
+// fence holds the run of t until it is due by the clock of the coordination
+// backend, if WithTriggerFence set one: until that clock is at least the
+// time t was due less the tolerance. A clock that can't be read is read
+// again, and a run held for fenceHold goes ahead all the same, so a backend
+// that is down or far behind delays the runs rather than losing them. Once
+// the scheduler stops, the run isn't held any longer. It is called from the
+// goroutine that will run the job.
+func (c *Cron) fence(t trigger) {
+	if c.clusterNow == nil || t.manual {
+		return
+	}
+	due := t.scheduled.Add(-c.fenceTolerance)
+	checked := false
+	for start := time.Now(); ; {
+		wait := fencePoll
+		now, err := c.clusterNow(c.runContext())
+		if err != nil {
+			c.log(SubsystemDispatch).Error(err, "reading the cluster clock failed", "entry", t.view.Name, "scheduled", t.scheduled)
+		} else {
+			if !checked {
+				checked = true
+				if skew := c.clock.Now().Sub(now); skew > c.fenceTolerance || skew < -c.fenceTolerance {
+					c.warn(SubsystemDispatch, "clock is off the cluster clock", "skew", skew, "tolerance", c.fenceTolerance)
+				}
+			}
+			if !now.Before(due) {
+				return
+			}
+			if d := due.Sub(now); d < wait {
+				wait = d
+			}
+		}
+		if held := time.Since(start); held >= fenceHold {
+			c.warn(SubsystemDispatch, "run held by the cluster clock too long, going ahead", "entry", t.view.Name, "scheduled", t.scheduled, "held", held)
+			return
+		}
+		c.debug(SubsystemDispatch, "run not due yet by the cluster clock, held", "entry", t.view.Name, "scheduled", t.scheduled, "cluster_now", now)
+		if !c.sleep(wait) {
+			return
+		}
+	}
+}
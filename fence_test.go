@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTriggerFence(t *testing.T) {
+	var reads atomic.Int32
+	cron := New(WithTriggerFence(50*time.Millisecond, func(ctx context.Context) (time.Time, error) {
+		// The first read fails, and the cluster clock is 300ms behind.
+		if reads.Add(1) == 1 {
+			return time.Time{}, errors.New("backend down")
+		}
+		return time.Now().Add(-300 * time.Millisecond), nil
+	}))
+	start := time.Now().Add(100 * time.Millisecond)
+	cron.AddFunc(start, time.Hour, func() {}, "job")
+	cron.Start()
+	defer cron.Stop()
+
+	run := waitForHistory(t, cron, "job", 1)[0]
+	if run.Outcome != OutcomeSuccess {
+		t.Fatalf("expected the run held, then run, got %+v", run)
+	}
+	if held := run.Start.Sub(start); held < 250*time.Millisecond {
+		t.Errorf("expected the run held until the cluster clock reached it, started after %v", held)
+	}
+
+	// Runs started by RunNow aren't held.
+	before := time.Now()
+	if r, _ := cron.RunNow("job"); r.Outcome != OutcomeSuccess || r.Start.Sub(before) > 200*time.Millisecond {
+		t.Errorf("expected the manual run to start at once, got %+v", r)
+	}
+}
@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FiscalCalendar is the fiscal year of the schedules of finance and
+// reporting jobs, see LastBusinessDayOfQuarter and FirstDayOfFiscalYear.
+type FiscalCalendar struct {
+	// The month the fiscal year starts on the first of, such as time.April,
+	// January if none. Its quarters are the three months from there on.
+	StartMonth time.Month
+
+	// The days that aren't business days besides weekends, such as bank
+	// holidays. Only their dates count.
+	Holidays []time.Time
+}
+
+// fiscalSchedule is a Schedule firing once a fiscal quarter or year, at a
+// time of day on the wall clock of the location it is asked in.
+type fiscalSchedule struct {
+	yearly    bool
+	start     time.Month
+	holidays  map[string]bool
+	hour, min int
+}
+
+// LastBusinessDayOfQuarter returns a Schedule firing at hh:mm on the last
+// business day of each quarter of the fiscal year of cal, the last weekday
+// of the quarter that isn't one of its holidays, such as for closing the
+// books:
+//
+//	c.AddFuncOn(LastBusinessDayOfQuarter(FiscalCalendar{StartMonth: time.April}, 17, 0), close, "close-quarter")
+//
+// The time of day is in the location of the scheduler, as for Daily.
+func LastBusinessDayOfQuarter(cal FiscalCalendar, hh, mm int) Schedule {
+	return newFiscalSchedule(false, cal, hh, mm)
+}
+
+// FirstDayOfFiscalYear returns a Schedule firing at hh:mm on the first day
+// of each fiscal year of cal, whether a business day or not, like Daily.
+func FirstDayOfFiscalYear(cal FiscalCalendar, hh, mm int) Schedule {
+	return newFiscalSchedule(true, cal, hh, mm)
+}
+
+func newFiscalSchedule(yearly bool, cal FiscalCalendar, hh, mm int) fiscalSchedule {
+	s := fiscalSchedule{yearly: yearly, start: cal.StartMonth, hour: hh, min: mm}
+	if s.start < time.January || s.start > time.December {
+		s.start = time.January
+	}
+	if !yearly && len(cal.Holidays) > 0 {
+		s.holidays = make(map[string]bool, len(cal.Holidays))
+		for _, h := range cal.Holidays {
+			s.holidays[h.Format(time.DateOnly)] = true
+		}
+	}
+	return s
+}
+
+func (s fiscalSchedule) Next(t time.Time) time.Time {
+	span := 3
+	if s.yearly {
+		span = 12
+	}
+	// From the first month of the period t is in. A quarter of holidays
+	// throughout is passed over.
+	y, m, _ := t.Date()
+	m -= time.Month((int(m) - int(s.start) + 12) % span)
+	for i := 0; i < 100; i++ {
+		if next := s.in(y, m+time.Month(i*span), t.Location()); !next.IsZero() && next.After(t) {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// in returns the occurrence in the period starting with month m of year y,
+// zero if there is none.
+func (s fiscalSchedule) in(y int, m time.Month, loc *time.Location) time.Time {
+	if s.yearly {
+		return wallTime(y, m, 1, s.hour, s.min, 0, loc)
+	}
+	for d := 0; d > -90; d-- {
+		// Day 0 of the month after the quarter is its last day.
+		day := time.Date(y, m+3, d, 0, 0, 0, 0, loc)
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday || s.holidays[day.Format(time.DateOnly)] {
+			continue
+		}
+		return wallTime(day.Year(), day.Month(), day.Day(), s.hour, s.min, 0, loc)
+	}
+	return time.Time{}
+}
+
+func (s fiscalSchedule) String() string {
+	kind := "quarter-end"
+	if s.yearly {
+		kind = "fiscal-year-start"
+	}
+	spec := fmt.Sprintf("%s %02d:%02d", kind, s.hour, s.min)
+	if s.start != time.January {
+		spec += " fiscal " + strings.ToLower(s.start.String())
+	}
+	if len(s.holidays) > 0 {
+		days := make([]string, 0, len(s.holidays))
+		for day := range s.holidays {
+			days = append(days, day)
+		}
+		sort.Strings(days)
+		spec += " except " + strings.Join(days, " ")
+	}
+	return spec
+}
+
+// parseFiscal parses the fields of a "quarter-end" or "fiscal-year-start"
+// spec: the time of day, then optionally "fiscal" and the month the fiscal
+// year starts, and for quarter-end "except" and the holidays.
+func parseFiscal(fields []string) (Schedule, error) {
+	yearly := fields[0] == "fiscal-year-start"
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("%s takes hh:mm", fields[0])
+	}
+	hh, mm, ss, err := parseTimeOfDay(fields[1])
+	if err != nil || ss != 0 {
+		return nil, fmt.Errorf("%s takes hh:mm", fields[0])
+	}
+	var cal FiscalCalendar
+	rest := fields[2:]
+	if len(rest) >= 2 && rest[0] == "fiscal" {
+		month, ok := months[rest[1]]
+		if !ok {
+			return nil, fmt.Errorf("no month %q", rest[1])
+		}
+		cal.StartMonth = month
+		rest = rest[2:]
+	}
+	if len(rest) >= 2 && rest[0] == "except" && !yearly {
+		for _, day := range rest[1:] {
+			h, err := time.Parse(time.DateOnly, day)
+			if err != nil {
+				return nil, fmt.Errorf("bad holiday %q", day)
+			}
+			cal.Holidays = append(cal.Holidays, h)
+		}
+		rest = nil
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("unexpected %q", strings.Join(rest, " "))
+	}
+	return newFiscalSchedule(yearly, cal, hh, mm), nil
+}
+
+var months = func() map[string]time.Month {
+	m := make(map[string]time.Month)
+	for month := time.January; month <= time.December; month++ {
+		m[strings.ToLower(month.String())] = month
+	}
+	return m
+}()
@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLastBusinessDayOfQuarter(t *testing.T) {
+	at := func(y int, m time.Month, d, hh int) time.Time { return time.Date(y, m, d, hh, 0, 0, 0, time.UTC) }
+	april := FiscalCalendar{StartMonth: time.April, Holidays: []time.Time{at(2026, 9, 30, 0)}}
+	s := LastBusinessDayOfQuarter(april, 17, 0)
+	for _, tc := range []struct{ from, want time.Time }{
+		// June 30th 2026 is a Tuesday.
+		{at(2026, 4, 1, 0), at(2026, 6, 30, 17)},
+		{at(2026, 6, 30, 17), at(2026, 9, 29, 17)},
+		// December 31st 2026 is a Thursday, March 31st 2027 a Wednesday.
+		{at(2026, 10, 1, 0), at(2026, 12, 31, 17)},
+		{at(2027, 1, 15, 0), at(2027, 3, 31, 17)},
+	} {
+		if got := s.Next(tc.from); !got.Equal(tc.want) {
+			t.Errorf("after %v: expected %v, got %v", tc.from, tc.want, got)
+		}
+	}
+	// The quarter ending on a weekend ends on the Friday before.
+	if got, want := LastBusinessDayOfQuarter(FiscalCalendar{}, 9, 0).Next(at(2028, 7, 1, 0)), at(2028, 9, 29, 9); !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestFirstDayOfFiscalYear(t *testing.T) {
+	s := FirstDayOfFiscalYear(FiscalCalendar{StartMonth: time.July}, 6, 0)
+	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	if got, want := s.Next(from), time.Date(2026, 7, 1, 6, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got, want := s.Next(time.Date(2026, 7, 1, 6, 0, 0, 0, time.UTC)), time.Date(2027, 7, 1, 6, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestParseFiscalSchedules(t *testing.T) {
+	for _, spec := range []string{"quarter-end 17:00", "quarter-end 17:00 fiscal april except 2026-06-30 2026-09-30", "fiscal-year-start 06:00 fiscal july", "daily 01:00, quarter-end 18:00"} {
+		s, err := ParseSchedule(spec)
+		if err != nil {
+			t.Errorf("%s: %v", spec, err)
+			continue
+		}
+		if got := s.(interface{ String() string }).String(); got != spec {
+			t.Errorf("expected %q to print as itself, got %q", spec, got)
+		}
+	}
+	for _, spec := range []string{"quarter-end", "quarter-end 17:00:30", "quarter-end 17:00 fiscal smarch", "fiscal-year-start 06:00 except 2026-07-01", "quarter-end 17:00 except someday"} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+}
+
+// The specs of fiscal schedules parse back to the same schedule, whatever
+// their count of fields.
+func TestFiscalScheduleRoundTrip(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+	for _, s := range []Schedule{
+		LastBusinessDayOfQuarter(FiscalCalendar{StartMonth: time.April, Holidays: []time.Time{day("2026-06-30")}}, 17, 0),
+		LastBusinessDayOfQuarter(FiscalCalendar{Holidays: []time.Time{day("2026-03-31"), day("2026-06-30")}}, 17, 0),
+		LastBusinessDayOfQuarter(FiscalCalendar{Holidays: []time.Time{day("2026-03-31"), day("2026-06-30"), day("2026-09-30")}}, 17, 0),
+		FirstDayOfFiscalYear(FiscalCalendar{StartMonth: time.July}, 6, 0),
+	} {
+		spec := s.(interface{ String() string }).String()
+		parsed, err := ParseSchedule(spec)
+		if err != nil {
+			t.Errorf("%s: %v", spec, err)
+			continue
+		}
+		if got := parsed.(interface{ String() string }).String(); got != spec {
+			t.Errorf("expected %q to parse back as itself, got %q", spec, got)
+		}
+		from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		if want, got := s.Next(from), parsed.Next(from); !got.Equal(want) {
+			t.Errorf("%s: expected the next run at %v, got %v", spec, want, got)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ErrFunctionFailed is what a FunctionJob fails with, wrapped, when its
+// function could not be invoked or returned an error.
+var ErrFunctionFailed = errors.New("scheduler: function invocation failed")
+
+// FunctionInvoker invokes cloud functions by name, such as AWS Lambda, see
+// LambdaInvoker, or the HTTP functions of GCP and Azure, see
+// HTTPFunctionInvoker. Implementations must be safe for concurrent use.
+type FunctionInvoker interface {
+	// Invoke calls the function with payload and returns its response. It
+	// should give up once ctx is done.
+	Invoke(ctx context.Context, function string, payload []byte) ([]byte, error)
+}
+
+// FunctionInvokerFunc is a FunctionInvoker calling itself.
+type FunctionInvokerFunc func(ctx context.Context, function string, payload []byte) ([]byte, error)
+
+func (f FunctionInvokerFunc) Invoke(ctx context.Context, function string, payload []byte) ([]byte, error) {
+	return f(ctx, function, payload)
+}
+
+// FunctionJob invokes a cloud function at each run, so entries can take over
+// from cloud-native cron triggers such as EventBridge rules. The response of
+// the function ends up in the run history. A failed invocation fails the
+// run with ErrFunctionFailed, as OutcomeError, so that retries and circuit
+// breakers apply. Add it with AddProgressJob.
+type FunctionJob struct {
+	Invoker  FunctionInvoker
+	Function string
+
+	// Payload is a text/template of the payload, executed with the RunInfo
+	// of the run, such as
+	//
+	//	{"date": "{{.Scheduled.Format "2006-01-02"}}", "run": {{json .Name}}}
+	//
+	// The json function of the template marshals its argument as JSON. The
+	// payload is empty if Payload is.
+	Payload string
+
+	// If non-zero, how long an invocation may take.
+	Timeout time.Duration
+}
+
+func (j FunctionJob) Run(p *Progress) {
+	payload, err := j.payload(p.Info())
+	if err == nil {
+		ctx := p.Context()
+		if j.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, j.Timeout)
+			defer cancel()
+		}
+		var resp []byte
+		resp, err = j.Invoker.Invoke(ctx, j.Function, payload)
+		p.Output().Write(resp)
+	}
+	if err != nil {
+		fmt.Fprintln(p.Output(), err)
+		p.fail(fmt.Errorf("%w: %s: %v", ErrFunctionFailed, j.Function, err))
+	}
+}
+
+var functionTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// payload returns the payload of the run described by info.
+func (j FunctionJob) payload(info RunInfo) ([]byte, error) {
+	if j.Payload == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New(j.Function).Funcs(functionTemplateFuncs).Parse(j.Payload)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, info); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// FunctionJobFactory returns a JobFactory for FunctionJobs, for entries
+// defined by their parameters, see RegisterJobFactory: "function" is the
+// name of the function, "provider" the key of its invoker in invokers, and
+// "payload" and "timeout", a duration such as "30s", are optional. The job
+// of a definition with a bad provider or timeout fails every run.
+func FunctionJobFactory(invokers map[string]FunctionInvoker) JobFactory {
+	return func(params map[string]interface{}) Job {
+		str := func(key string) string {
+			s, _ := params[key].(string)
+			return s
+		}
+		j := FunctionJob{Function: str("function"), Payload: str("payload")}
+		var err error
+		if s := str("timeout"); s != "" {
+			j.Timeout, err = time.ParseDuration(s)
+		}
+		j.Invoker = invokers[str("provider")]
+		if j.Invoker == nil {
+			err = fmt.Errorf("no invoker for provider %q", str("provider"))
+		}
+		if err != nil {
+			err = fmt.Errorf("%w: %s: %v", ErrFunctionFailed, j.Function, err)
+			return AsJob(errJob{ErrFuncJob(func() error { return err })})
+		}
+		return AsJob(j)
+	}
+}
+
+// LambdaInvoker is a FunctionInvoker calling the Invoke API of AWS Lambda,
+// signing its requests with AWS Signature Version 4. Functions are named by
+// their name, ARN or alias, as in "report:prod".
+type LambdaInvoker struct {
+	Region string
+
+	// The credentials to sign with. If AccessKeyID is empty they are read
+	// from $AWS_ACCESS_KEY_ID, $AWS_SECRET_ACCESS_KEY and
+	// $AWS_SESSION_TOKEN.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// Async invokes the functions without waiting for them to complete, so
+	// their response is empty.
+	Async bool
+
+	// The endpoint of the API, https://lambda.{Region}.amazonaws.com if
+	// empty, and the client to call it with, http.DefaultClient if nil.
+	Endpoint string
+	Client   *http.Client
+}
+
+func (l LambdaInvoker) Invoke(ctx context.Context, function string, payload []byte) ([]byte, error) {
+	endpoint := l.Endpoint
+	if endpoint == "" {
+		endpoint = "https://lambda." + l.Region + ".amazonaws.com"
+	}
+	path := "/2015-03-31/functions/" + awsEscape(function) + "/invocations"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+path, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	if l.Async {
+		req.Header.Set("X-Amz-Invocation-Type", "Event")
+	}
+	l.sign(req, payload, time.Now())
+	client := l.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, outputLimit))
+	if err != nil {
+		return body, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return body, fmt.Errorf("scheduler: lambda %s: %s", function, resp.Status)
+	}
+	// The function itself failed, its response is the error.
+	if fe := resp.Header.Get("X-Amz-Function-Error"); fe != "" {
+		return body, fmt.Errorf("scheduler: lambda %s: %s error", function, fe)
+	}
+	return body, nil
+}
+
+// sign signs req, whose body is payload, at now.
+func (l LambdaInvoker) sign(req *http.Request, payload []byte, now time.Time) {
+	id, secret, token := l.AccessKeyID, l.SecretAccessKey, l.SessionToken
+	if id == "" {
+		id = os.Getenv("AWS_ACCESS_KEY_ID")
+		secret = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		token = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+	signV4(req, payload, now, l.Region, "lambda", id, secret)
+}
+
+// signV4 sets the Authorization header of req, whose body is payload, as of
+// now, with AWS Signature Version 4. It signs the host, the Content-Type
+// and the X-Amz- headers.
+func signV4(req *http.Request, payload []byte, now time.Time, region, service, id, secret string) {
+	now = now.UTC()
+	stamp := now.Format("20060102T150405Z")
+	day := stamp[:8]
+	req.Header.Set("X-Amz-Date", stamp)
+
+	headers := map[string]string{"host": req.URL.Host}
+	names := []string{"host"}
+	for name, vs := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(vs, ","))
+			names = append(names, lower)
+		}
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signed := strings.Join(names, ";")
+
+	segments := strings.Split(req.URL.EscapedPath(), "/")
+	for i, s := range segments {
+		segments[i] = awsEscape(s)
+	}
+	canonical := strings.Join([]string{
+		req.Method,
+		strings.Join(segments, "/"),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signed,
+		hexSHA256(payload),
+	}, "\n")
+	scope := day + "/" + region + "/" + service + "/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + stamp + "\n" + scope + "\n" + hexSHA256([]byte(canonical))
+
+	key := hmacSHA256([]byte("AWS4"+secret), day)
+	for _, s := range []string{region, service, "aws4_request"} {
+		key = hmacSHA256(key, s)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		id, scope, signed, hex.EncodeToString(hmacSHA256(key, toSign))))
+}
+
+// canonicalQuery returns the query of a request as signed: sorted by key
+// and value, escaped the AWS way.
+func canonicalQuery(q url.Values) string {
+	var pairs []string
+	for k, vs := range q {
+		for _, v := range vs {
+			pairs = append(pairs, awsEscape(k)+"="+awsEscape(v))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape escapes s as AWS signatures do, everything but the unreserved
+// characters of RFC 3986.
+func awsEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case 'A' <= ch && ch <= 'Z', 'a' <= ch && ch <= 'z', '0' <= ch && ch <= '9',
+			ch == '-', ch == '_', ch == '.', ch == '~':
+			b.WriteByte(ch)
+		default:
+			fmt.Fprintf(&b, "%%%02X", ch)
+		}
+	}
+	return b.String()
+}
+
+func hexSHA256(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, s string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(s))
+	return h.Sum(nil)
+}
+
+// HTTPFunctionInvoker is a FunctionInvoker POSTing the payload to HTTP
+// triggered functions, such as those of Google Cloud Functions or Azure
+// Functions, see GCPFunctions and AzureFunctions. Any status but a 2xx is
+// an error.
+type HTTPFunctionInvoker struct {
+	// URL of the functions, "{function}" standing for the name of the one
+	// to invoke, as in "https://example.com/fn/{function}".
+	URL string
+
+	// Headers set on every request, and if set, a func returning the bearer
+	// token to authenticate with, such as a GCP identity token.
+	Header http.Header
+	Token  func(ctx context.Context) (string, error)
+
+	// The client to POST with, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// GCPFunctions returns the invoker of the HTTP functions of a Google Cloud
+// project, in a region such as "europe-west1". Functions that require
+// authentication also need the Token of the invoker.
+func GCPFunctions(region, project string) *HTTPFunctionInvoker {
+	return &HTTPFunctionInvoker{URL: "https://" + region + "-" + project + ".cloudfunctions.net/{function}"}
+}
+
+// AzureFunctions returns the invoker of the HTTP functions of an Azure
+// function app, authenticating with key, one of its function or host keys.
+func AzureFunctions(app, key string) *HTTPFunctionInvoker {
+	h := http.Header{}
+	if key != "" {
+		h.Set("X-Functions-Key", key)
+	}
+	return &HTTPFunctionInvoker{URL: "https://" + app + ".azurewebsites.net/api/{function}", Header: h}
+}
+
+func (i *HTTPFunctionInvoker) Invoke(ctx context.Context, function string, payload []byte) ([]byte, error) {
+	u := strings.ReplaceAll(i.URL, "{function}", url.PathEscape(function))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range i.Header {
+		req.Header[k] = vs
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if i.Token != nil {
+		token, err := i.Token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	client := i.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, outputLimit))
+	if err != nil {
+		return body, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return body, fmt.Errorf("scheduler: function %s: %s", function, resp.Status)
+	}
+	return body, nil
+}
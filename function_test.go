@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The get-vanilla case of the AWS Signature Version 4 test suite.
+func TestSignV4(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	signV4(req, nil, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC), "us-east-1", "service",
+		"AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestLambdaInvoker(t *testing.T) {
+	var path, auth, payload string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.EscapedPath(), r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		payload = string(b)
+		if payload == "fail" {
+			w.Header().Set("X-Amz-Function-Error", "Unhandled")
+		}
+		io.WriteString(w, `{"ok":true}`)
+	}))
+	defer srv.Close()
+	l := LambdaInvoker{Region: "eu-west-1", AccessKeyID: "id", SecretAccessKey: "secret", Endpoint: srv.URL}
+
+	resp, err := l.Invoke(context.Background(), "report:prod", []byte("go"))
+	if err != nil || string(resp) != `{"ok":true}` {
+		t.Fatalf("expected the response, got %q, %v", resp, err)
+	}
+	if path != "/2015-03-31/functions/report%3Aprod/invocations" {
+		t.Errorf("unexpected path %s", path)
+	}
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=id/") || !strings.Contains(auth, "/eu-west-1/lambda/aws4_request") {
+		t.Errorf("unexpected authorization %s", auth)
+	}
+	if _, err := l.Invoke(context.Background(), "report", []byte("fail")); err == nil {
+		t.Error("expected the function error to be an error")
+	}
+}
+
+func TestFunctionJob(t *testing.T) {
+	var got []string
+	inv := FunctionInvokerFunc(func(ctx context.Context, function string, payload []byte) ([]byte, error) {
+		got = append(got, function+" "+string(payload))
+		return []byte("done"), nil
+	})
+	cron := New()
+	cron.RegisterJobFactory("function", FunctionJobFactory(map[string]FunctionInvoker{"test": inv}))
+	err := cron.AddRegisteredJob(time.Now().Add(50*time.Millisecond), time.Hour, "function", map[string]interface{}{
+		"provider": "test",
+		"function": "report",
+		"payload":  `{"run": {{json .Name}}}`,
+	}, "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cron.AddRegisteredJob(time.Now().Add(50*time.Millisecond), time.Hour, "function", map[string]interface{}{
+		"provider": "missing",
+		"function": "other",
+	}, "other")
+	cron.Start()
+	defer cron.Stop()
+
+	runs := waitForHistory(t, cron, "report", 1)
+	if runs[0].Outcome != OutcomeSuccess || runs[0].Output != "done" {
+		t.Errorf("expected a successful run with the response, got %+v", runs[0])
+	}
+	if len(got) != 1 || got[0] != `report {"run": "report"}` {
+		t.Errorf("unexpected invocations %q", got)
+	}
+	if runs := waitForHistory(t, cron, "other", 1); runs[0].Outcome != OutcomeError || !strings.Contains(runs[0].Error, ErrFunctionFailed.Error()) {
+		t.Errorf("expected a run without invoker to fail, got %s %q", runs[0].Outcome, runs[0].Error)
+	}
+}
+
+// A failed invocation fails the run with an error, not a panic, so the
+// panic policy doesn't apply to it.
+func TestFunctionJobFails(t *testing.T) {
+	inv := FunctionInvokerFunc(func(ctx context.Context, function string, payload []byte) ([]byte, error) {
+		return nil, io.ErrUnexpectedEOF
+	})
+	cron := New(WithPanicPolicy(PanicDisable))
+	cron.AddProgressJob(time.Now().Add(time.Hour), time.Hour, FunctionJob{Invoker: inv, Function: "report"}, "report")
+	cron.Start()
+	defer cron.Stop()
+
+	r, _ := cron.RunNow("report")
+	if r.Outcome != OutcomeError || !strings.Contains(r.Error, ErrFunctionFailed.Error()) {
+		t.Errorf("expected the run to fail with ErrFunctionFailed, got %s %q", r.Outcome, r.Error)
+	}
+	if e, _ := cron.Entry("report"); e.Disabled || e.Panics != 0 {
+		t.Errorf("expected the entry enabled without panics, got %v, %d", e.Disabled, e.Panics)
+	}
+}
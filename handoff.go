@@ -0,0 +1,162 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	handoffPrefix = "handoff/entries/"
+	handoffReady  = "handoff/ready"
+
+	// How often Takeover looks for the ready signal.
+	handoffPoll = 100 * time.Millisecond
+)
+
+// ErrNoJobStore is returned by Drain and Takeover on a Cron without a
+// JobStore.
+var ErrNoJobStore = errors.New("scheduler: no JobStore configured")
+
+// handoffState is what a drained instance leaves for its successor about
+// an entry.
+type handoffState struct {
+	// When the next run is due.
+	Next time.Time `json:"next"`
+
+	// Runs that were due but had not started yet.
+	Pending []time.Time `json:"pending,omitempty"`
+
+	// The metadata of the entry, for a successor adding it without any.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Drain hands the scheduler over to another instance, typically during a
+// deploy. It stops firing new triggers, takes the runs that are queued but
+// not started out of the queue, and waits for the runs in flight to finish
+// or ctx to be done. It then writes when each entry is due next, along with
+// the runs it took out of the queue, to the JobStore and signals that the
+// state is ready, allowing the new instance's Takeover to proceed.
+//
+// The state is handed off even if ctx is done first, in which case the
+// runs still going keep going and ctx's error is returned.
+func (c *Cron) Drain(ctx context.Context) error {
+	if c.store == nil {
+		return ErrNoJobStore
+	}
+	c.Stop()
+
+	state := make(map[string]handoffState)
+	// The queues are guarded by mu, the entries by entriesMu, as for
+	// Entries.
+	c.entriesMu.RLock()
+	c.mu.Lock()
+	for _, e := range c.entries {
+		s := handoffState{Next: e.nominal, Metadata: e.Metadata}
+		for _, t := range e.queue {
+			s.Pending = append(s.Pending, t.scheduled)
+		}
+		e.Pending -= len(e.queue)
+		e.queue = nil
+		state[e.Name] = s
+	}
+	c.mu.Unlock()
+	c.entriesMu.RUnlock()
+
+	waitErr := c.waitRuns(ctx)
+
+	for name, s := range state {
+		data, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		if err := c.store.Put(handoffPrefix+name, data); err != nil {
+			return err
+		}
+	}
+	ready, _ := time.Now().MarshalText()
+	if err := c.store.Put(handoffReady, ready); err != nil {
+		return err
+	}
+	return waitErr
+}
+
+// Takeover waits for a draining instance sharing the JobStore to signal it
+// is ready, picks up where it left off, and starts the scheduler. Entries
+// must be added before calling it. Runs that came due in between are run
+// right away, once, so there are neither gaps nor double runs. The run loop
+// may have fired such a run by the time Takeover returns, and Entries then
+// shows the one after it; the run history shows where it picked up.
+func (c *Cron) Takeover(ctx context.Context) error {
+	if c.store == nil {
+		return ErrNoJobStore
+	}
+	for {
+		_, err := c.store.Get(handoffReady)
+		if err == nil {
+			break
+		}
+		if err != ErrNotFound {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(handoffPoll):
+		}
+	}
+
+	keys, err := c.store.Keys(handoffPrefix)
+	if err != nil {
+		return err
+	}
+	state := make(map[string]handoffState)
+	for _, key := range keys {
+		data, err := c.store.Get(key)
+		if err != nil {
+			return err
+		}
+		var s handoffState
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		state[strings.TrimPrefix(key, handoffPrefix)] = s
+		if err := c.store.Delete(key); err != nil {
+			return err
+		}
+	}
+	if err := c.store.Delete(handoffReady); err != nil {
+		return err
+	}
+
+	c.handoff = state
+	c.Start()
+	return nil
+}
+
+// resumeHandoff applies the state taken over from a drained instance. It is
+// called by the run loop once the entries have their next times.
+func (c *Cron) resumeHandoff() {
+	if c.handoff == nil {
+		return
+	}
+	for _, e := range c.entries {
+		s, ok := c.handoff[e.Name]
+		if !ok {
+			continue
+		}
+		if !s.Next.IsZero() {
+			e.nominal = s.Next
+			e.NextTime = e.place(s.Next)
+		}
+		if e.Metadata == nil {
+			e.Metadata = s.Metadata
+		}
+		for _, scheduled := range s.Pending {
+			c.dispatch(e, scheduled)
+		}
+	}
+	c.handoff = nil
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDrainAndTakeover(t *testing.T) {
+	store := NewMemoryStore()
+	var oldRuns, newRuns int32
+
+	old := New(WithJobStore(store))
+	old.AddFunc(time.Now().Add(100*time.Millisecond), 200*time.Millisecond, func() {
+		atomic.AddInt32(&oldRuns, 1)
+	}, "tick")
+	old.Start()
+	time.Sleep(150 * time.Millisecond)
+
+	replacement := New(WithJobStore(store))
+	replacement.AddFunc(time.Now(), 200*time.Millisecond, func() {
+		atomic.AddInt32(&newRuns, 1)
+	}, "tick")
+	took := make(chan error, 1)
+	go func() { took <- replacement.Takeover(context.Background()) }()
+
+	// The replacement waits until the old instance is drained.
+	time.Sleep(150 * time.Millisecond)
+	if replacement.running {
+		t.Fatal("replacement started before the handoff")
+	}
+	if err := old.Drain(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// What was handed off: the old instance may have run until Drain.
+	next := old.Entries()[0].NextTime
+	if err := <-took; err != nil {
+		t.Fatal(err)
+	}
+	defer replacement.Stop()
+
+	// The run due next may be past due by now, and already run, so its
+	// record tells where the replacement picked up.
+	if runs := waitForHistory(t, replacement, "tick", 1); !runs[0].Scheduled.Equal(next) {
+		t.Errorf("expected the replacement to pick up at %v, got %v", next, runs[0].Scheduled)
+	}
+	ran := atomic.LoadInt32(&oldRuns)
+	time.Sleep(300 * time.Millisecond)
+	if atomic.LoadInt32(&oldRuns) != ran {
+		t.Error("drained instance kept running jobs")
+	}
+	if atomic.LoadInt32(&newRuns) == 0 {
+		t.Error("replacement did not run the job")
+	}
+	if keys, _ := store.Keys("handoff/"); len(keys) != 0 {
+		t.Errorf("expected the handoff state to be consumed, got %v", keys)
+	}
+}
+
+// Runs that are queued behind a running one are handed off, not lost.
+func TestDrainHandsOffQueuedRuns(t *testing.T) {
+	store := NewMemoryStore()
+	release := make(chan struct{})
+	old := New(WithJobStore(store))
+	old.AddFunc(time.Now().Add(50*time.Millisecond), 50*time.Millisecond, func() {
+		<-release
+	}, "slow", WithOverlap(OverlapSerialize), WithMaxPending(1))
+	old.Start()
+	time.Sleep(200 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := old.Drain(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected the drain to time out on the running job, got %v", err)
+	}
+	close(release)
+
+	ran := make(chan struct{}, 10)
+	replacement := New(WithJobStore(store))
+	replacement.AddFunc(time.Now().Add(time.Hour), time.Hour, func() { ran <- struct{}{} }, "slow")
+	if err := replacement.Takeover(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer replacement.Stop()
+
+	select {
+	case <-ran:
+	case <-time.After(ONE_SECOND):
+		t.Fatal("queued run was not handed off")
+	}
+}
@@ -0,0 +1,155 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HealthCheck tells whether a dependency of some entries, such as a
+// database or a downstream service, is up. It is registered on the
+// scheduler, see AddHealthCheck, and entries refer to it by name, see
+// WithDependsOn.
+type HealthCheck struct {
+	Name string
+
+	// Check returns nil if the dependency is healthy. It should give up once
+	// ctx is done. See HTTPProbe.
+	Check func(ctx context.Context) error
+
+	// How often a deferred run checks again, and how long each check may
+	// take. The default is 10s.
+	Every time.Duration
+}
+
+// The default HealthCheck.Every.
+const defaultHealthEvery = 10 * time.Second
+
+// HTTPProbe returns a HealthCheck.Check that GETs url and takes a 2xx status
+// for healthy.
+func HTTPProbe(url string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("scheduler: probe of %s: %s", url, resp.Status)
+		}
+		return nil
+	}
+}
+
+// AddHealthCheck registers h, replacing the check of the same name if any.
+func (c *Cron) AddHealthCheck(h HealthCheck) {
+	if h.Every <= 0 {
+		h.Every = defaultHealthEvery
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.health == nil {
+		c.health = make(map[string]HealthCheck)
+	}
+	c.health[h.Name] = h
+}
+
+// RemoveHealthCheck unregisters the named check.
+func (c *Cron) RemoveHealthCheck(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.health, name)
+}
+
+// DeferralPolicy decides what becomes of a run that has been deferred for
+// the longest the entry allows, see WithMaxDeferral.
+type DeferralPolicy int
+
+const (
+	// Run anyway, dependencies or not.
+	DeferralRun DeferralPolicy = iota
+
+	// Skip the run, recorded as OutcomeUnhealthy.
+	DeferralSkip
+)
+
+// WithDependsOn makes the runs of the entry wait for the named health
+// checks: a run that comes due while any of them fails is deferred, checking
+// again every HealthCheck.Every, and starts once they all pass. A check that
+// isn't registered counts as failing. Runs started with RunNow don't wait.
+func WithDependsOn(checks ...string) EntryOption {
+	return func(e *Entry) {
+		e.DependsOn = append(e.DependsOn, checks...)
+	}
+}
+
+// WithMaxDeferral bounds how long a run of the entry waits for its
+// dependencies, see WithDependsOn, after which p decides whether it runs
+// anyway or is skipped. By default a run waits for as long as it takes.
+func WithMaxDeferral(d time.Duration, p DeferralPolicy) EntryOption {
+	return func(e *Entry) {
+		e.MaxDeferral = d
+		e.Deferral = p
+	}
+}
+
+// awaitHealthy waits for the dependencies of e to be healthy. It returns
+// whether the run is to go, or to be skipped instead, and whether it had to
+// wait.
+func (c *Cron) awaitHealthy(e *Entry) (run, deferred bool) {
+	if len(e.DependsOn) == 0 {
+		return true, false
+	}
+	start := time.Now()
+	for {
+		name, every, err := c.unhealthy(e)
+		if err == nil {
+			if deferred {
+				c.log(SubsystemDispatch).Info("dependencies recovered, deferred run starting", "entry", e.Name, "deferred", time.Since(start))
+			}
+			return true, deferred
+		}
+		if !deferred {
+			c.log(SubsystemDispatch).Info("dependency unhealthy, run deferred", "entry", e.Name, "check", name, "error", err.Error())
+			deferred = true
+		}
+		if e.MaxDeferral > 0 {
+			left := e.MaxDeferral - time.Since(start)
+			if left <= 0 {
+				if e.Deferral == DeferralRun {
+					c.log(SubsystemDispatch).Info("dependency still unhealthy, running anyway", "entry", e.Name, "check", name)
+					return true, true
+				}
+				c.log(SubsystemDispatch).Info("dependency still unhealthy, run skipped", "entry", e.Name, "check", name)
+				return false, true
+			}
+			every = min(every, left)
+		}
+		time.Sleep(every)
+	}
+}
+
+// unhealthy runs the health checks e depends on, and returns the first one
+// failing, how often to check it and why it fails.
+func (c *Cron) unhealthy(e *Entry) (string, time.Duration, error) {
+	for _, name := range e.DependsOn {
+		c.mu.Lock()
+		h, ok := c.health[name]
+		c.mu.Unlock()
+		if !ok {
+			return name, defaultHealthEvery, fmt.Errorf("scheduler: no health check %q", name)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), h.Every)
+		err := h.Check(ctx)
+		cancel()
+		if err != nil {
+			return name, h.Every, err
+		}
+	}
+	return "", 0, nil
+}
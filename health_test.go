@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDependencyDefersRun(t *testing.T) {
+	var healthy atomic.Bool
+	cron := New()
+	cron.AddHealthCheck(HealthCheck{Name: "db", Every: 20 * time.Millisecond, Check: func(context.Context) error {
+		if !healthy.Load() {
+			return errors.New("down")
+		}
+		return nil
+	}})
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() {}, "etl", WithDependsOn("db"))
+	cron.Start()
+	defer cron.Stop()
+
+	time.Sleep(250 * time.Millisecond)
+	if n := len(cron.History("etl")); n != 0 {
+		t.Fatalf("expected the run deferred, got %d runs", n)
+	}
+	healthy.Store(true)
+	r := waitForHistory(t, cron, "etl", 1)[0]
+	if r.Outcome != OutcomeSuccess || r.Start.Sub(r.Scheduled) < 200*time.Millisecond {
+		t.Errorf("expected a deferred successful run, got %+v", r)
+	}
+}
+
+func TestMaxDeferral(t *testing.T) {
+	cron := New()
+	cron.AddHealthCheck(HealthCheck{Name: "api", Every: 20 * time.Millisecond, Check: func(context.Context) error {
+		return errors.New("down")
+	}})
+	var ran atomic.Bool
+	start := time.Now().Add(50 * time.Millisecond)
+	cron.AddFunc(start, time.Hour, func() {}, "skipped", WithDependsOn("api"), WithMaxDeferral(100*time.Millisecond, DeferralSkip))
+	cron.AddFunc(start, time.Hour, func() { ran.Store(true) }, "forced", WithDependsOn("api"), WithMaxDeferral(100*time.Millisecond, DeferralRun))
+	cron.AddFunc(start, time.Hour, func() {}, "unknown", WithDependsOn("missing"), WithMaxDeferral(50*time.Millisecond, DeferralSkip))
+	cron.Start()
+	defer cron.Stop()
+
+	if r := waitForHistory(t, cron, "skipped", 1)[0]; r.Outcome != OutcomeUnhealthy {
+		t.Errorf("expected the run skipped as unhealthy, got %v", r.Outcome)
+	}
+	if r := waitForHistory(t, cron, "forced", 1)[0]; r.Outcome != OutcomeSuccess || !ran.Load() {
+		t.Errorf("expected the run to go anyway, got %v", r.Outcome)
+	}
+	if r := waitForHistory(t, cron, "unknown", 1)[0]; r.Outcome != OutcomeUnhealthy {
+		t.Errorf("expected a missing check to count as unhealthy, got %v", r.Outcome)
+	}
+}
+
+func TestHTTPProbe(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	probe := HTTPProbe(srv.URL)
+	if err := probe(context.Background()); err == nil {
+		t.Error("expected a 503 to be unhealthy")
+	}
+	status = http.StatusOK
+	if err := probe(context.Background()); err != nil {
+		t.Errorf("expected a 200 to be healthy, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package scheduler
+
+import (
+	"container/heap"
+	"sort"
+	"time"
+)
+
+// The entries of a Cron are kept in a min-heap by NextTime, zero times last,
+// each entry knowing its index in it, and indexed by name. Adding, removing
+// or rescheduling an entry is O(log n), so the run loop doesn't stall when
+// thousands of entries come and go.
+
+func (h entries) Len() int           { return len(h) }
+func (h entries) Less(i, j int) bool { return byTime(h).Less(i, j) }
+
+func (h entries) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *entries) Push(x interface{}) {
+	e := x.(*Entry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *entries) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	e.index = -1
+	return e
+}
+
+// lookup returns the named entry, or nil.
+func (c *Cron) lookup(name string) *Entry {
+	return c.byName[name]
+}
+
+// insert adds the entry to the schedule.
+func (c *Cron) insert(e *Entry) {
+	if c.byName == nil {
+		c.byName = make(map[string]*Entry)
+	}
+	c.byName[e.Name] = e
+	heap.Push(&c.entries, e)
+}
+
+// unlink removes the entry from the schedule.
+func (c *Cron) unlink(e *Entry) {
+	e.unwatchSources()
+	c.mu.Lock()
+	e.stopCoolDown()
+	c.mu.Unlock()
+	if c.byName[e.Name] == e {
+		delete(c.byName, e.Name)
+	}
+	if e.index >= 0 && e.index < len(c.entries) && c.entries[e.index] == e {
+		heap.Remove(&c.entries, e.index)
+	}
+}
+
+// reschedule moves the entry to its place after its NextTime changed.
+func (c *Cron) reschedule(e *Entry) {
+	heap.Fix(&c.entries, e.index)
+}
+
+// requeue puts an entry taken off by due back on the schedule.
+func (c *Cron) requeue(e *Entry) {
+	heap.Push(&c.entries, e)
+}
+
+// due takes the entries due at effective off the schedule, soonest first.
+// They go back with requeue once they have their next time.
+func (c *Cron) due(effective time.Time) []*Entry {
+	var due []*Entry
+	for len(c.entries) > 0 {
+		e := c.entries[0]
+		if e.NextTime.IsZero() || !e.NextTime.Round(time.Second).Equal(effective.Round(time.Second)) {
+			break
+		}
+		heap.Pop(&c.entries)
+		due = append(due, e)
+	}
+	return due
+}
+
+// sorted returns the entries soonest to run first.
+func (h entries) sorted() []*Entry {
+	s := append([]*Entry(nil), h...)
+	sort.Stable(byTime(s))
+	return s
+}
+
+// Upcoming is an entry along with the next time it is due, see Cron.Next.
+type Upcoming struct {
+	Entry *Entry
+	Time  time.Time
+}
+
+// Next returns the k entries soonest to run, soonest first, leaving out those
+// that won't run again. It walks the schedule heap instead of sorting a
+// snapshot of all the entries, in O(k log k).
+func (c *Cron) Next(k int) []Upcoming {
+	c.entriesMu.RLock()
+	defer c.entriesMu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Candidates are the children of the entries taken so far, in a heap of
+	// their own.
+	frontier := &indexHeap{h: c.entries}
+	if len(c.entries) > 0 {
+		heap.Push(frontier, 0)
+	}
+	var next []Upcoming
+	for len(next) < k && frontier.Len() > 0 {
+		e := c.entries[heap.Pop(frontier).(int)]
+		if e.NextTime.IsZero() {
+			break
+		}
+		next = append(next, Upcoming{Entry: e.copy(), Time: e.NextTime})
+		for _, child := range []int{2*e.index + 1, 2*e.index + 2} {
+			if child < len(c.entries) {
+				heap.Push(frontier, child)
+			}
+		}
+	}
+	return next
+}
+
+// indexHeap is a heap of indices into the schedule heap h.
+type indexHeap struct {
+	h       entries
+	indices []int
+}
+
+func (x *indexHeap) Len() int           { return len(x.indices) }
+func (x *indexHeap) Less(i, j int) bool { return x.h.Less(x.indices[i], x.indices[j]) }
+func (x *indexHeap) Swap(i, j int)      { x.indices[i], x.indices[j] = x.indices[j], x.indices[i] }
+func (x *indexHeap) Push(i interface{}) { x.indices = append(x.indices, i.(int)) }
+
+func (x *indexHeap) Pop() interface{} {
+	i := x.indices[len(x.indices)-1]
+	x.indices = x.indices[:len(x.indices)-1]
+	return i
+}
@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Churn through many entries while running, expect the schedule to stay
+// consistent: the survivors are listed soonest first and still run.
+func TestHeapChurn(t *testing.T) {
+	cron := New()
+	cron.Start()
+	defer cron.Stop()
+
+	base := time.Now().Add(time.Hour)
+	for i := 0; i < 2000; i++ {
+		cron.AddFunc(base.Add(time.Duration(i%97)*time.Minute), time.Hour, func() {}, fmt.Sprint(i))
+	}
+	for i := 0; i < 2000; i += 2 {
+		cron.RemoveJob(fmt.Sprint(i))
+	}
+	for i := 1; i < 2000; i += 4 {
+		cron.UpdateJob(base.Add(-time.Duration(i%89)*time.Minute), time.Hour, FuncJob(func() {}), fmt.Sprint(i))
+	}
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() {}, "soon")
+
+	entries := cron.Entries()
+	if len(entries) != 1001 {
+		t.Fatalf("expected 1001 entries, got %d", len(entries))
+	}
+	if entries[0].Name != "soon" {
+		t.Errorf("expected the soonest entry first, got %q", entries[0].Name)
+	}
+	for i := 1; i < len(entries); i++ {
+		if entries[i].NextTime.Before(entries[i-1].NextTime) {
+			t.Fatalf("entries out of order at %d", i)
+		}
+	}
+	waitForHistory(t, cron, "soon", 1)
+}
+
+func TestHeapRemoveAndUpdate(t *testing.T) {
+	c := New()
+	for i, name := range []string{"a", "b", "c", "d"} {
+		c.AddFunc(time.Now().Add(time.Duration(4-i)*time.Hour), time.Hour, func() {}, name)
+	}
+	for _, e := range c.entries {
+		e.Next()
+	}
+	c.drop("b")
+	c.put(&Entry{setStartTime: time.Now().Add(time.Minute), Interval: time.Hour, Job: FuncJob(func() {}), Name: "a"})
+	c.lookup("a").Next()
+	c.reschedule(c.lookup("a"))
+
+	for i, e := range c.entries {
+		if e.index != i {
+			t.Errorf("%q: expected index %d, got %d", e.Name, i, e.index)
+		}
+	}
+	if c.entries[0].Name != "a" || c.lookup("b") != nil || len(c.entries) != 3 {
+		t.Errorf("unexpected schedule %v", c.Entries())
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	cron := New()
+	base := time.Now().Add(time.Hour)
+	for _, i := range []int{5, 3, 9, 1, 7, 2, 8} {
+		cron.AddFunc(base.Add(time.Duration(i)*time.Minute), time.Hour, func() {}, fmt.Sprint(i))
+	}
+	cron.AddOnceFunc(time.Now().Add(-time.Hour), func() {}, "done")
+	cron.Start()
+	defer cron.Stop()
+	waitForHistory(t, cron, "done", 1)
+
+	next := cron.Next(4)
+	var names []string
+	for _, u := range next {
+		names = append(names, u.Entry.Name)
+	}
+	if fmt.Sprint(names) != "[1 2 3 5]" {
+		t.Errorf("expected the 4 soonest entries, got %v", names)
+	}
+	if !next[0].Time.Equal(base.Add(time.Minute)) {
+		t.Errorf("unexpected time %v", next[0].Time)
+	}
+	if n := len(cron.Next(100)); n != 7 {
+		t.Errorf("expected the entries that will run again, got %d", n)
+	}
+}
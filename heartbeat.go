@@ -0,0 +1,74 @@
+package scheduler
+
+import "time"
+
+// WithHeartbeat requires the runs of the entry to call Progress.Heartbeat (or
+// Progress.Report) at least once every timeout. A run that falls silent for
+// longer is cancelled through its context and recorded as OutcomeStalled.
+//
+// Only a ProgressJob can send heartbeats, so this is meant for those.
+func WithHeartbeat(timeout time.Duration) EntryOption {
+	return func(e *Entry) {
+		e.HeartbeatTimeout = timeout
+	}
+}
+
+// Heartbeat tells the scheduler the run is still alive.
+func (p *Progress) Heartbeat() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.lastBeat = time.Now()
+	p.mu.Unlock()
+}
+
+// silentFor returns how long ago the last heartbeat was.
+func (p *Progress) silentFor(now time.Time) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return now.Sub(p.lastBeat)
+}
+
+// stall marks the run as stalled and cancels it.
+func (p *Progress) stall() {
+	p.mu.Lock()
+	p.stalled = true
+	p.mu.Unlock()
+	p.cancel()
+}
+
+func (p *Progress) isStalled() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stalled
+}
+
+// The shortest time between two checks of the heartbeats of a run.
+const heartbeatTick = time.Millisecond
+
+// watchHeartbeat cancels the run once it stops sending heartbeats. It returns
+// when the run is over.
+func (c *Cron) watchHeartbeat(e *Entry, p *Progress) {
+	// A quarter of the timeout, but no tick shorter than heartbeatTick.
+	check := time.NewTicker(max(e.HeartbeatTimeout/4, heartbeatTick))
+	defer check.Stop()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case now := <-check.C:
+			if p.silentFor(now) <= e.HeartbeatTimeout {
+				continue
+			}
+			p.stall()
+			p.Logger().Info("job stopped sending heartbeats, cancelled", "timeout", e.HeartbeatTimeout)
+			// The job may never notice the cancellation, so don't wait for
+			// it to return before recording the outcome.
+			c.mu.Lock()
+			e.LastOutcome = OutcomeStalled
+			c.mu.Unlock()
+			return
+		}
+	}
+}
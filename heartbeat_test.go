@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+// A job that stops heartbeating is cancelled and recorded as stalled.
+func TestHeartbeatStalledJobIsCancelled(t *testing.T) {
+	cron := New()
+	cancelled := make(chan struct{})
+	cron.AddProgressFunc(time.Now().Add(100*time.Millisecond), time.Hour, func(p *Progress) {
+		p.Heartbeat()
+		<-p.Context().Done()
+		close(cancelled)
+	}, "stuck", WithHeartbeat(200*time.Millisecond))
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case <-cancelled:
+	case <-time.After(2 * ONE_SECOND):
+		t.Fatal("stalled job was not cancelled")
+	}
+
+	if got := cron.Entries()[0].LastOutcome; got != OutcomeStalled {
+		t.Errorf("expected outcome %q, got %q", OutcomeStalled, got)
+	}
+}
+
+// A job that keeps heartbeating runs to completion.
+func TestHeartbeatKeepsJobAlive(t *testing.T) {
+	cron := New()
+	done := make(chan struct{})
+	cron.AddProgressFunc(time.Now().Add(100*time.Millisecond), time.Hour, func(p *Progress) {
+		defer close(done)
+		for i := 0; i < 6; i++ {
+			select {
+			case <-p.Context().Done():
+				return
+			case <-time.After(50 * time.Millisecond):
+				p.Heartbeat()
+			}
+		}
+	}, "busy", WithHeartbeat(200*time.Millisecond))
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(2 * ONE_SECOND):
+		t.Fatal("job did not finish")
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	if got := cron.Entries()[0].LastOutcome; got != OutcomeSuccess {
+		t.Errorf("expected outcome %q, got %q", OutcomeSuccess, got)
+	}
+}
+
+// A timeout too short for a quarter of it to make a ticker still works.
+func TestHeartbeatTinyTimeout(t *testing.T) {
+	cron := New()
+	cron.AddProgressFunc(time.Now().Add(time.Hour), time.Hour, func(p *Progress) {
+		<-p.Context().Done()
+	}, "tiny", WithHeartbeat(time.Nanosecond))
+	cron.Start()
+	defer cron.Stop()
+
+	if r, _ := cron.RunNow("tiny"); r.Outcome != OutcomeStalled {
+		t.Errorf("expected outcome %q, got %q", OutcomeStalled, r.Outcome)
+	}
+}
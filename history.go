@@ -0,0 +1,148 @@
+package scheduler
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// How many runs are kept in the history of each entry.
+	historyLimit = 20
+
+	// How much output is kept for each run. Past this, only the most recent
+	// output is kept.
+	outputLimit = 64 << 10
+)
+
+// RunRecord describes a finished run of an entry.
+type RunRecord struct {
+	// Name of the entry that ran, and the version of its definition.
+	Name    string
+	Version int
+
+	// When the run was due.
+	Scheduled time.Time
+
+	// When the run started and ended.
+	Start time.Time
+	End   time.Time
+
+	// How the run ended.
+	Outcome Outcome
+
+	// Which attempt at the run this was, starting at 1, why it failed, and
+	// how long it waited after the previous attempt, see WithRetries.
+	Attempt int
+	Error   string
+	Backoff time.Duration
+
+	// Set if the run was enqueued by Backfill, or started by RunNow, rather
+	// than the schedule.
+	Backfill bool
+	Manual   bool
+
+	// Set on the record RunNow returns when it comes from the result cache,
+	// see WithResultCache.
+	Cached bool
+
+	// Set if the run, started by RunNow, took the place of a scheduled run
+	// due at Scheduled, see WithRunNowDedup.
+	Coalesced bool
+
+	// Set if the run was triggered by a source, see WithSource.
+	Sourced bool
+
+	// Why the run was skipped, deferred or coalesced, if it was.
+	Reason Reason
+
+	// The labels of the entry let through by WithMetricLabels.
+	Labels map[string]string
+
+	// The ID of the trace of the run, if the job gave one, see
+	// Progress.SetTraceID.
+	TraceID string
+
+	// Whatever the job wrote to Progress.Output, or the stdout and stderr of
+	// a ShellJob.
+	Output string
+
+	// Set if the output went over the limit and its beginning was dropped.
+	OutputTruncated bool
+}
+
+// History returns the most recent runs of the named entry, oldest first.
+func (c *Cron) History(name string) []RunRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]RunRecord(nil), c.history[name]...)
+}
+
+// record adds r to the history and reports it to the Metrics and the event
+// handler.
+func (c *Cron) record(r RunRecord) {
+	c.recordRun(r, nil)
+}
+
+// recordRun is record, with the metadata of the entry for the event.
+func (c *Cron) recordRun(r RunRecord, metadata map[string]string) {
+	c.mu.Lock()
+	defer func() {
+		c.mu.Unlock()
+		if c.metrics != nil {
+			c.metrics.RunFinished(r)
+		}
+		c.emit(Event{Type: EventRun, Name: r.Name, Version: r.Version, Metadata: metadata, Reason: r.Reason, Run: &r})
+	}()
+	if c.history == nil {
+		c.history = make(map[string][]RunRecord)
+	}
+	runs := append(c.history[r.Name], r)
+	if len(runs) > historyLimit {
+		runs = runs[len(runs)-historyLimit:]
+	}
+	c.history[r.Name] = runs
+}
+
+// Output returns a writer whose contents are kept in the run history once
+// the run is over.
+func (p *Progress) Output() io.Writer {
+	if p == nil || p.output == nil {
+		return io.Discard
+	}
+	return p.output
+}
+
+// outputBuffer keeps the last limit bytes written to it, and passes
+// complete lines on to whoever is tailing the run (see stream.go).
+type outputBuffer struct {
+	mu        sync.Mutex
+	buf       []byte
+	limit     int
+	truncated bool
+
+	partial   []byte
+	followers map[chan string]struct{}
+	closed    bool
+}
+
+func (b *outputBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.limit; over > 0 {
+		b.buf = b.buf[:copy(b.buf, b.buf[over:])]
+		b.truncated = true
+	}
+	b.broadcast(p)
+	return len(p), nil
+}
+
+func (b *outputBuffer) contents() (string, bool) {
+	if b == nil {
+		return "", false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf), b.truncated
+}
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitForHistory polls until the named entry has n runs in its history.
+func waitForHistory(t *testing.T, cron *Cron, name string, n int) []RunRecord {
+	deadline := time.Now().Add(2 * ONE_SECOND)
+	for time.Now().Before(deadline) {
+		if runs := cron.History(name); len(runs) >= n {
+			return runs
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("%s did not run %d time(s)", name, n)
+	return nil
+}
+
+func TestShellJobOutputInHistory(t *testing.T) {
+	cron := New()
+	cron.AddProgressJob(time.Now().Add(100*time.Millisecond), time.Hour,
+		ShellJob{Command: "echo hello; echo oops 1>&2"}, "shell")
+	cron.Start()
+	defer cron.Stop()
+
+	run := waitForHistory(t, cron, "shell", 1)[0]
+	if run.Output != "hello\noops\n" {
+		t.Errorf("unexpected output %q", run.Output)
+	}
+	if run.Outcome != OutcomeSuccess || run.End.Before(run.Start) {
+		t.Errorf("unexpected record %+v", run)
+	}
+}
+
+func TestProgressOutputIsBounded(t *testing.T) {
+	cron := New()
+	cron.AddProgressFunc(time.Now().Add(100*time.Millisecond), time.Hour, func(p *Progress) {
+		fmt.Fprint(p.Output(), strings.Repeat("a", outputLimit))
+		fmt.Fprint(p.Output(), "tail")
+	}, "chatty")
+	cron.Start()
+	defer cron.Stop()
+
+	run := waitForHistory(t, cron, "chatty", 1)[0]
+	if len(run.Output) != outputLimit || !strings.HasSuffix(run.Output, "tail") {
+		t.Errorf("expected the last %d bytes to be kept, got %d", outputLimit, len(run.Output))
+	}
+	if !run.OutputTruncated {
+		t.Error("expected the output to be marked truncated")
+	}
+}
+
+func TestHistoryIsBounded(t *testing.T) {
+	cron := New()
+	for i := 0; i < historyLimit+5; i++ {
+		cron.record(RunRecord{Name: "job", Output: fmt.Sprint(i)})
+	}
+	runs := cron.History("job")
+	if len(runs) != historyLimit || runs[0].Output != "5" {
+		t.Errorf("expected the last %d runs, got %d starting at %s", historyLimit, len(runs), runs[0].Output)
+	}
+}
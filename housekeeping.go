@@ -0,0 +1,52 @@
+package scheduler
+
+import "time"
+
+// defaultHorizon is the longest the run loop sleeps by default, see
+// WithHorizon.
+const defaultHorizon = time.Minute
+
+// WithHorizon caps how long the run loop sleeps at d, a minute by default,
+// instead of sleeping until the next trigger however far away it is. Every
+// d the loop wakes up for a housekeeping tick, even while triggers keep it
+// busy: it expires the archive (see WithArchiveTTL) and runs the tasks of
+// WithHousekeeping, so the loop has a heartbeat to go by.
+func WithHorizon(d time.Duration) Option {
+	return func(c *Cron) {
+		if d <= 0 {
+			d = defaultHorizon
+		}
+		c.horizon = d
+	}
+}
+
+// WithHousekeeping runs task on each housekeeping tick of the run loop, see
+// WithHorizon, with the time of the tick by the clock of the scheduler. It
+// suits periodic chores such as flushing stats or checkpointing state. The
+// tasks run one after the other, in a goroutine of their own, so they may
+// call back into the Cron; a tick that comes while the previous one is
+// still going is skipped.
+func WithHousekeeping(task func(now time.Time)) Option {
+	return func(c *Cron) {
+		c.housekeeping = append(c.housekeeping, task)
+	}
+}
+
+// housekeep does the chores of the tick at now. It is called from the run
+// loop.
+func (c *Cron) housekeep(now time.Time) {
+	c.lastTick = now
+	c.debug(SubsystemLifecycle, "housekeeping tick", "time", now)
+	c.mu.Lock()
+	c.expireArchive(time.Now())
+	c.mu.Unlock()
+	if len(c.housekeeping) == 0 || !c.housekeepBusy.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer c.housekeepBusy.Store(false)
+		for _, task := range c.housekeeping {
+			task(now)
+		}
+	}()
+}
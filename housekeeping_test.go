@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHousekeepingTicks(t *testing.T) {
+	ticks := make(chan time.Time, 100)
+	cron := New(WithHorizon(50*time.Millisecond), WithHousekeeping(func(now time.Time) { ticks <- now }))
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "far")
+	// Triggers more often than the horizon don't hold the ticks back.
+	cron.AddFunc(time.Now(), 20*time.Millisecond, func() {}, "busy")
+	cron.Start()
+	defer cron.Stop()
+
+	var prev time.Time
+	for i := 0; i < 3; i++ {
+		select {
+		case now := <-ticks:
+			if !prev.IsZero() && now.Sub(prev) < 50*time.Millisecond {
+				t.Errorf("expected ticks 50ms apart, got %v", now.Sub(prev))
+			}
+			prev = now
+		case <-time.After(ONE_SECOND):
+			t.Fatal("expected housekeeping ticks")
+		}
+	}
+	if e, _ := cron.Entry("far"); e.NextTime.Before(time.Now().Add(50 * time.Minute)) {
+		t.Errorf("expected far to keep its next time, got %v", e.NextTime)
+	}
+}
+
+func TestHousekeepingExpiresArchive(t *testing.T) {
+	cron := New(WithHorizon(20*time.Millisecond), WithArchiveTTL(30*time.Millisecond))
+	cron.AddOnceFunc(time.Now(), func() {}, "once")
+	cron.Start()
+	defer cron.Stop()
+
+	waitForHistory(t, cron, "once", 1)
+	time.Sleep(100 * time.Millisecond)
+	cron.mu.Lock()
+	n := len(cron.archive)
+	cron.mu.Unlock()
+	if n != 0 {
+		t.Errorf("expected the archive expired by the ticks, got %d entries", n)
+	}
+}
@@ -0,0 +1,382 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ImportedSchedule is a schedule found in the config of another cron
+// service, to consolidate it into the scheduler, see ImportGitHubActions
+// and ImportCronJobs.
+type ImportedSchedule struct {
+	// The name of the workflow or CronJob, and for a CronJob its namespace.
+	Name      string
+	Namespace string
+
+	// When it runs, as a spec of ParseSchedule: its crontab expressions,
+	// with the time zone they go by.
+	Schedule string
+
+	// Set for a CronJob that was suspended, which an importer may want to
+	// add paused, or not at all.
+	Suspended bool
+}
+
+// Definition returns the definition of an entry running a job of the given
+// kind, see RegisterJobFactory, on the schedule, for AddDefinition or Plan.
+func (s ImportedSchedule) Definition(kind string, params map[string]interface{}) JobDefinition {
+	return JobDefinition{Name: s.Name, Kind: kind, Params: params, Schedule: s.Schedule, Namespace: s.Namespace}
+}
+
+// ImportGitHubActions returns the schedule of a GitHub Actions workflow,
+// from the crontab expressions of its on.schedule block, which go by UTC.
+// The expressions of a workflow are merged into one schedule, named after
+// the workflow, or after its file if it has no name. A workflow without a
+// schedule has none.
+func ImportGitHubActions(file string, workflow []byte) ([]ImportedSchedule, error) {
+	doc, err := parseManifest(workflow)
+	if err != nil {
+		return nil, fmt.Errorf("scheduler: workflow %s: %w", file, err)
+	}
+	name := doc.get("name").text()
+	if name == "" {
+		name = strings.TrimSuffix(path.Base(file), path.Ext(file))
+	}
+	schedule := doc.get("on", "schedule")
+	if schedule.text() != "" {
+		return nil, fmt.Errorf("scheduler: workflow %s: want the schedule as a list of crons", file)
+	}
+	var specs []string
+	for _, item := range schedule.list() {
+		expr := item.get("cron").text()
+		if expr == "" {
+			return nil, fmt.Errorf("scheduler: workflow %s: a schedule without a cron", file)
+		}
+		specs = append(specs, cronTZ+"UTC "+expr)
+	}
+	if specs == nil {
+		return nil, nil
+	}
+	s := ImportedSchedule{Name: name, Schedule: strings.Join(specs, ", ")}
+	if _, err := ParseSchedule(s.Schedule); err != nil {
+		return nil, fmt.Errorf("scheduler: workflow %s: %w", file, err)
+	}
+	return []ImportedSchedule{s}, nil
+}
+
+// ImportCronJobs returns the schedules of the Kubernetes CronJobs among
+// manifests, as kubectl takes them: YAML documents separated by ---, or JSON,
+// each a CronJob, another object, which is passed over, or a List of them.
+// The expression of a CronJob goes by its spec.timeZone, or without one by
+// the location of the scheduler, as the controller goes by its own.
+func ImportCronJobs(manifests []byte) ([]ImportedSchedule, error) {
+	var objects []*manifestNode
+	for _, doc := range manifestDocuments(string(manifests)) {
+		n, err := parseManifest([]byte(doc))
+		if err != nil {
+			return nil, fmt.Errorf("scheduler: manifest: %w", err)
+		}
+		if n.get("kind").text() == "List" {
+			objects = append(objects, n.get("items").list()...)
+			continue
+		}
+		objects = append(objects, n)
+	}
+	var imported []ImportedSchedule
+	for _, n := range objects {
+		if n.get("kind").text() != "CronJob" {
+			continue
+		}
+		s := ImportedSchedule{
+			Name:      n.get("metadata", "name").text(),
+			Namespace: n.get("metadata", "namespace").text(),
+			Schedule:  n.get("spec", "schedule").text(),
+			Suspended: n.get("spec", "suspend").text() == "true",
+		}
+		if tz := n.get("spec", "timeZone").text(); tz != "" {
+			s.Schedule = cronTZ + tz + " " + s.Schedule
+		}
+		if _, err := ParseSchedule(s.Schedule); err != nil {
+			return nil, fmt.Errorf("scheduler: CronJob %q: %w", s.Name, err)
+		}
+		imported = append(imported, s)
+	}
+	return imported, nil
+}
+
+// manifestNode is a node of a workflow or manifest: a scalar, a mapping or
+// a sequence.
+type manifestNode struct {
+	scalar string
+	fields map[string]*manifestNode
+	items  []*manifestNode
+}
+
+// get returns the node at the path of keys under n, nil if there is none.
+func (n *manifestNode) get(path ...string) *manifestNode {
+	for _, key := range path {
+		if n == nil {
+			return nil
+		}
+		n = n.fields[key]
+	}
+	return n
+}
+
+func (n *manifestNode) text() string {
+	if n == nil {
+		return ""
+	}
+	return n.scalar
+}
+
+func (n *manifestNode) list() []*manifestNode {
+	if n == nil {
+		return nil
+	}
+	return n.items
+}
+
+// manifestDocuments splits a stream of YAML documents at their ---
+// separators.
+func manifestDocuments(src string) []string {
+	var docs []string
+	var b strings.Builder
+	for _, line := range strings.Split(src, "\n") {
+		if t := strings.TrimRight(line, " \t\r"); t == "---" || strings.HasPrefix(t, "--- ") || t == "..." {
+			docs = append(docs, b.String())
+			b.Reset()
+			continue
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return append(docs, b.String())
+}
+
+// parseManifest parses a JSON document, or a YAML one written in the subset
+// workflows and manifests are: block mappings and sequences of plain or
+// quoted scalars, and block scalars such as the scripts of steps. Anchors,
+// tags and flow collections other than JSON are not supported.
+func parseManifest(doc []byte) (*manifestNode, error) {
+	if t := strings.TrimSpace(string(doc)); strings.HasPrefix(t, "{") {
+		var v interface{}
+		if err := json.Unmarshal(doc, &v); err != nil {
+			return nil, err
+		}
+		return jsonNode(v), nil
+	}
+	p := &yamlParser{}
+	for i, line := range strings.Split(string(doc), "\n") {
+		text := strings.TrimRight(line, " \t\r")
+		if body := strings.TrimLeft(text, " "); body != "" {
+			p.lines = append(p.lines, yamlLine{indent: len(text) - len(body), text: body, n: i + 1})
+		}
+	}
+	if len(p.lines) == 0 {
+		return &manifestNode{}, nil
+	}
+	n, err := p.block(p.lines[0].indent)
+	if l, more := p.line(); err == nil && more {
+		err = fmt.Errorf("line %d: bad indentation", l.n)
+	}
+	return n, err
+}
+
+// jsonNode converts a value decoded from JSON.
+func jsonNode(v interface{}) *manifestNode {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		n := &manifestNode{fields: make(map[string]*manifestNode, len(v))}
+		for key, val := range v {
+			n.fields[key] = jsonNode(val)
+		}
+		return n
+	case []interface{}:
+		n := &manifestNode{}
+		for _, val := range v {
+			n.items = append(n.items, jsonNode(val))
+		}
+		return n
+	case nil:
+		return &manifestNode{}
+	}
+	return &manifestNode{scalar: fmt.Sprint(v)}
+}
+
+type yamlLine struct {
+	indent int
+	text   string
+	n      int
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	i     int
+}
+
+// line returns the current line, with its comment cut off, and whether there
+// is one left.
+func (p *yamlParser) line() (yamlLine, bool) {
+	for p.i < len(p.lines) {
+		l := p.lines[p.i]
+		if l.text = stripYAMLComment(l.text); l.text != "" {
+			return l, true
+		}
+		p.i++
+	}
+	return yamlLine{}, false
+}
+
+// block parses the mapping or sequence at indent.
+func (p *yamlParser) block(indent int) (*manifestNode, error) {
+	if l, ok := p.line(); ok && isYAMLItem(l.text) {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+func (p *yamlParser) mapping(indent int) (*manifestNode, error) {
+	n := &manifestNode{fields: make(map[string]*manifestNode)}
+	for {
+		l, ok := p.line()
+		if !ok || l.indent < indent || l.indent == indent && isYAMLItem(l.text) {
+			return n, nil
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: bad indentation", l.n)
+		}
+		key, value, ok := splitYAMLKey(l.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: want key: value", l.n)
+		}
+		p.i++
+		var child *manifestNode
+		var err error
+		switch {
+		case value == "":
+			// A sequence may sit at the indent of its key.
+			child, err = p.child(indent, true)
+		case value[0] == '|' || value[0] == '>':
+			child = &manifestNode{scalar: p.blockScalar(indent)}
+		default:
+			child = &manifestNode{scalar: unquoteYAML(value)}
+		}
+		if err != nil {
+			return nil, err
+		}
+		n.fields[key] = child
+	}
+}
+
+func (p *yamlParser) sequence(indent int) (*manifestNode, error) {
+	n := &manifestNode{}
+	for {
+		l, ok := p.line()
+		if !ok || l.indent != indent || !isYAMLItem(l.text) {
+			return n, nil
+		}
+		rest := strings.TrimLeft(l.text[1:], " ")
+		var item *manifestNode
+		var err error
+		switch _, _, isKey := splitYAMLKey(rest); {
+		case rest == "":
+			p.i++
+			item, err = p.child(indent, false)
+		case isKey:
+			// "- key: value" starts a mapping at the column of the key.
+			p.lines[p.i].indent = indent + len(l.text) - len(rest)
+			p.lines[p.i].text = rest
+			item, err = p.mapping(p.lines[p.i].indent)
+		default:
+			p.i++
+			item = &manifestNode{scalar: unquoteYAML(rest)}
+		}
+		if err != nil {
+			return nil, err
+		}
+		n.items = append(n.items, item)
+	}
+}
+
+// child parses the block under a line at indent, an empty node if there is
+// none.
+func (p *yamlParser) child(indent int, sequenceAtIndent bool) (*manifestNode, error) {
+	l, ok := p.line()
+	if ok && (l.indent > indent || sequenceAtIndent && l.indent == indent && isYAMLItem(l.text)) {
+		return p.block(l.indent)
+	}
+	return &manifestNode{}, nil
+}
+
+// blockScalar returns the lines more indented than indent, as text.
+func (p *yamlParser) blockScalar(indent int) string {
+	var lines []string
+	for ; p.i < len(p.lines) && p.lines[p.i].indent > indent; p.i++ {
+		lines = append(lines, p.lines[p.i].text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isYAMLItem(s string) bool {
+	return s == "-" || strings.HasPrefix(s, "- ")
+}
+
+// splitYAMLKey splits "key: value", whose key may be quoted.
+func splitYAMLKey(s string) (key, value string, ok bool) {
+	i := 0
+	if s != "" && (s[0] == '"' || s[0] == '\'') {
+		end := strings.IndexByte(s[1:], s[0])
+		if end < 0 {
+			return "", "", false
+		}
+		i = end + 2
+	}
+	j := strings.Index(s[i:], ": ")
+	switch {
+	case j >= 0:
+		j += i
+	case strings.HasSuffix(s, ":"):
+		j = len(s) - 1
+	default:
+		return "", "", false
+	}
+	return unquoteYAML(s[:j]), strings.TrimSpace(s[j+1:]), true
+}
+
+// stripYAMLComment cuts off the comment of a line, a # at its start or
+// after a space, outside of the quoted scalars, which start a line or
+// follow a space.
+func stripYAMLComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case (ch == '"' || ch == '\'') && (i == 0 || s[i-1] == ' '):
+			quote = ch
+		case ch == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return strings.TrimRight(s[:i], " \t")
+		}
+	}
+	return s
+}
+
+func unquoteYAML(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+		return s[1 : len(s)-1]
+	}
+	return s
+}
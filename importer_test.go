@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+const testWorkflow = `
+name: "Nightly build" # shown in the Actions tab
+on:
+  push:
+    branches: [main]
+  schedule:
+    - cron: '30 5 * * 1,3'
+    # and at midnight
+    - cron: "0 0 * * *"
+  workflow_dispatch:
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - name: Build
+        run: |
+          make all # not a comment
+          echo "done: yes"
+`
+
+func TestImportGitHubActions(t *testing.T) {
+	imported, err := ImportGitHubActions(".github/workflows/nightly.yml", []byte(testWorkflow))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(imported) != 1 || imported[0].Name != "Nightly build" || imported[0].Schedule != "CRON_TZ=UTC 30 5 * * 1,3, CRON_TZ=UTC 0 0 * * *" {
+		t.Fatalf("unexpected schedules %+v", imported)
+	}
+	s, err := ParseSchedule(imported[0].Schedule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Monday 2030-01-07 03:00 UTC: the next run is at 05:30 UTC.
+	if next := s.Next(time.Date(2030, 1, 7, 3, 0, 0, 0, time.UTC)); !next.Equal(time.Date(2030, 1, 7, 5, 30, 0, 0, time.UTC)) {
+		t.Errorf("expected the crons to go by UTC, got %v", next)
+	}
+
+	imported, err = ImportGitHubActions("ci.yaml", []byte("on:\n  push:\n"))
+	if err != nil || imported != nil {
+		t.Errorf("expected no schedule, got %+v %v", imported, err)
+	}
+	imported, _ = ImportGitHubActions("weekly.yml", []byte("on:\n  schedule:\n  - cron: '0 9 * * mon'\n"))
+	if len(imported) != 1 || imported[0].Name != "weekly" {
+		t.Errorf("expected the workflow named after its file, got %+v", imported)
+	}
+	if _, err := ImportGitHubActions("bad.yml", []byte("on:\n  schedule:\n    - cron: 'every day'\n")); err == nil {
+		t.Error("expected a bad cron to be refused")
+	}
+}
+
+const testManifests = `apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: cleanup
+  namespace: ops
+spec:
+  schedule: "*/15 * * * *"
+  timeZone: Europe/Paris
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: cleanup
+            image: busybox
+            args:
+            - /bin/sh
+            - -c
+            - date; echo cleaning
+          restartPolicy: OnFailure
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: report
+spec:
+  schedule: '@daily'
+  suspend: true
+`
+
+func TestImportCronJobs(t *testing.T) {
+	imported, err := ImportCronJobs([]byte(testManifests))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ImportedSchedule{
+		{Name: "cleanup", Namespace: "ops", Schedule: "CRON_TZ=Europe/Paris */15 * * * *"},
+		{Name: "report", Schedule: "@daily", Suspended: true},
+	}
+	if len(imported) != len(want) || imported[0] != want[0] || imported[1] != want[1] {
+		t.Fatalf("expected %+v, got %+v", want, imported)
+	}
+
+	list := `{"kind": "List", "items": [{"kind": "CronJob", "metadata": {"name": "json"}, "spec": {"schedule": "0 3 * * *", "suspend": false}}]}`
+	imported, err = ImportCronJobs([]byte(list))
+	if err != nil || len(imported) != 1 || imported[0].Name != "json" || imported[0].Suspended {
+		t.Errorf("unexpected schedules %+v: %v", imported, err)
+	}
+	if _, err := ImportCronJobs([]byte("kind: CronJob\nspec:\n  schedule: nope\n")); err == nil {
+		t.Error("expected a bad schedule to be refused")
+	}
+	if _, err := ImportCronJobs([]byte("kind: CronJob\n  spec: x\n")); err == nil {
+		t.Error("expected bad indentation to be refused")
+	}
+}
+
+func TestImportedDefinition(t *testing.T) {
+	cron := New()
+	cron.RegisterJobFactory("noop", func(map[string]interface{}) Job { return FuncJob(func() {}) })
+	imported, _ := ImportCronJobs([]byte(testManifests))
+	for _, s := range imported {
+		if _, err := cron.AddDefinition(s.Definition("noop", nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if e, ok := cron.Entry("cleanup"); !ok || e.Namespace != "ops" || e.Kind != "noop" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+}
@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// Interceptor is called before each run of each entry is dispatched, with a
+// copy of the entry and the time the run was due. It is the single place to
+// enforce policy across all jobs: it can veto or delay the run, and the
+// context it returns becomes the parent of the run's context (see
+// Progress.Context), which is how it attaches values for the job.
+//
+// It is called from the goroutine that will run the job, so it may block.
+type Interceptor func(ctx context.Context, e *Entry, scheduled time.Time) (context.Context, Verdict)
+
+// Verdict is what an Interceptor decides about a run.
+type Verdict struct {
+	// Veto cancels the run. It is recorded as OutcomeVetoed.
+	Veto bool
+
+	// Delay postpones the start of the run.
+	Delay time.Duration
+}
+
+// intercept consults the Interceptor, if any, about the upcoming run of the
+// entry, given as a copy. It returns the parent context for the run, or
+// false if it was vetoed.
+func (c *Cron) intercept(view *Entry, scheduled time.Time) (context.Context, bool) {
+	ctx := c.runContext()
+	if c.interceptor == nil {
+		return ctx, true
+	}
+
+	next, verdict := c.interceptor(ctx, view, scheduled)
+	if next != nil {
+		ctx = next
+	}
+	if verdict.Veto {
+		return nil, false
+	}
+	if verdict.Delay > 0 {
+		time.Sleep(verdict.Delay)
+	}
+	return ctx, true
+}
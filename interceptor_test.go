@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type tenantKey struct{}
+
+func TestInterceptorVeto(t *testing.T) {
+	ran := make(chan struct{}, 1)
+	cron := New(WithInterceptor(func(ctx context.Context, e *Entry, scheduled time.Time) (context.Context, Verdict) {
+		return ctx, Verdict{Veto: e.Name == "forbidden"}
+	}))
+	cron.AddFunc(time.Now().Add(100*time.Millisecond), time.Hour, func() { ran <- struct{}{} }, "forbidden")
+	cron.Start()
+	defer cron.Stop()
+
+	runs := waitForHistory(t, cron, "forbidden", 1)
+	if runs[0].Outcome != OutcomeVetoed {
+		t.Errorf("expected outcome %q, got %q", OutcomeVetoed, runs[0].Outcome)
+	}
+	select {
+	case <-ran:
+		t.Error("vetoed job ran")
+	default:
+	}
+}
+
+func TestInterceptorDelayAndContext(t *testing.T) {
+	var due time.Time
+	cron := New(WithInterceptor(func(ctx context.Context, e *Entry, scheduled time.Time) (context.Context, Verdict) {
+		due = scheduled
+		return context.WithValue(ctx, tenantKey{}, "acme"), Verdict{Delay: 200 * time.Millisecond}
+	}))
+	got := make(chan interface{}, 1)
+	start := time.Now().Add(100 * time.Millisecond)
+	cron.AddProgressFunc(start, time.Hour, func(p *Progress) {
+		got <- p.Context().Value(tenantKey{})
+	}, "tenant")
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case v := <-got:
+		if v != "acme" {
+			t.Errorf("expected the context value to reach the job, got %v", v)
+		}
+	case <-time.After(ONE_SECOND):
+		t.Fatal("job did not run")
+	}
+	if time.Since(start) < 200*time.Millisecond {
+		t.Error("expected the run to be delayed")
+	}
+	if !due.Equal(start) {
+		t.Errorf("expected scheduled time %v, got %v", start, due)
+	}
+}
@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+)
+
+// Labels returns the tags of the entry of the form "key:value" as a map,
+// such as {"team": "billing"} for the tag "team:billing". The other tags
+// are left out.
+func (e *Entry) Labels() map[string]string {
+	var labels map[string]string
+	for _, tag := range e.Tags {
+		key, value, ok := strings.Cut(tag, ":")
+		if !ok || key == "" {
+			continue
+		}
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[key] = value
+	}
+	return labels
+}
+
+// labelsKey is the context key of the labels of a run.
+type labelsKey struct{}
+
+// LabelsFromContext returns the labels of the entry let through by
+// WithMetricLabels, for the run whose context ctx is or derives from, see
+// Progress.Context. They are meant as the attributes of the spans of the
+// run.
+func LabelsFromContext(ctx context.Context) map[string]string {
+	labels, _ := ctx.Value(labelsKey{}).(map[string]string)
+	return labels
+}
+
+// labelsOf returns the labels of the entry that may go to the Metrics and
+// the context of the run, see WithMetricLabels.
+func (c *Cron) labelsOf(e *Entry) map[string]string {
+	if len(c.metricLabels) == 0 {
+		return nil
+	}
+	var labels map[string]string
+	for key, value := range e.Labels() {
+		if !c.metricLabels[key] {
+			continue
+		}
+		if labels == nil {
+			labels = make(map[string]string, len(c.metricLabels))
+		}
+		labels[key] = value
+	}
+	return labels
+}
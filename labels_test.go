@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEntryLabels(t *testing.T) {
+	e := &Entry{Tags: []string{"team:billing", "nightly", ":odd", "tier:gold"}}
+	want := map[string]string{"team": "billing", "tier": "gold"}
+	if got := e.Labels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestMetricLabelsAllowlist(t *testing.T) {
+	m := recordingMetrics{make(chan RunRecord, 1)}
+	cron := New(WithMetrics(m), WithMetricLabels("team"))
+	labels := make(chan map[string]string, 1)
+	cron.AddProgressFunc(time.Now().Add(50*time.Millisecond), time.Hour, func(p *Progress) {
+		labels <- LabelsFromContext(p.Context())
+	}, "job", WithTags("team:billing", "customer:1234", "nightly"))
+	cron.Start()
+	defer cron.Stop()
+
+	want := map[string]string{"team": "billing"}
+	select {
+	case r := <-m.runs:
+		if !reflect.DeepEqual(r.Labels, want) {
+			t.Errorf("expected the run to be labelled %v, got %v", want, r.Labels)
+		}
+	case <-time.After(ONE_SECOND):
+		t.Fatal("run was not reported")
+	}
+	if got := <-labels; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the context of the run to be labelled %v, got %v", want, got)
+	}
+}
+
+func TestDogStatsDLabels(t *testing.T) {
+	agent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer agent.Close()
+
+	d, err := NewDogStatsD(agent.LocalAddr().String(), "sched")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	now := time.Now()
+	d.RunFinished(RunRecord{Name: "job", Scheduled: now, Start: now, End: now, Outcome: OutcomeSuccess,
+		Labels: map[string]string{"tier": "gold", "team": "billing"}})
+
+	buf := make([]byte, 512)
+	agent.SetReadDeadline(time.Now().Add(ONE_SECOND))
+	n, _, err := agent.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(buf[:n]), "sched.run.count:1|c|#job:job,outcome:success,team:billing,tier:gold"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,194 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// LintCode identifies the kind of problem a Diagnostic is about.
+type LintCode string
+
+const (
+	// The entry runs once a minute or more often.
+	LintHighFrequency LintCode = "high_frequency"
+
+	// A time of Daily or Weekly is out of range, such as hour 24, and
+	// time.Date carries it over into another time.
+	LintInvalidTime LintCode = "invalid_time"
+
+	// The schedule has no occurrence after now.
+	LintNeverFires LintCode = "never_fires"
+
+	// A one-shot entry starts more than a minute in the past, so it runs as
+	// soon as it is added.
+	LintStartPassed LintCode = "start_passed"
+
+	// Merge was given the same schedule more than once.
+	LintDuplicateSchedule LintCode = "duplicate_schedule"
+
+	// The flex of the preferred window is at least the interval, so runs may
+	// be reordered or pile up.
+	LintFlexOverInterval LintCode = "flex_over_interval"
+
+	// The failure interval is not shorter than the interval of the entry.
+	LintFailureInterval LintCode = "failure_interval"
+
+	// The retries of a failed run take longer than the interval, so they
+	// run on into the next runs.
+	LintRetriesOverInterval LintCode = "retries_over_interval"
+
+	// The result cache outlasts the interval, so every scheduled run after
+	// the first is cached.
+	LintCacheOverInterval LintCode = "cache_over_interval"
+
+	// A crontab expression sets both the day of the month and the day of
+	// the week, so it fires on the days matching either, not both.
+	LintDomDow LintCode = "dom_dow"
+)
+
+// highFrequency is the longest interval an entry gets a warning for, as
+// running that often.
+const highFrequency = time.Minute
+
+// Diagnostic is a warning about a suspicious entry definition, which is
+// valid, but most likely not what was meant. See Validate.
+type Diagnostic struct {
+	Code    LintCode
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return string(d.Code) + ": " + d.Message
+}
+
+// Validate lints the definition of an entry starting at startTime and
+// running every interval, with the given options, as Schedule would add it,
+// without adding it. It returns no diagnostics if nothing looks amiss. The
+// same diagnostics are logged when such an entry is added.
+func Validate(startTime time.Time, interval time.Duration, opts ...EntryOption) []Diagnostic {
+	return validate(&Entry{setStartTime: startTime, Interval: interval}, opts)
+}
+
+// ValidateOn is Validate for an entry on the Schedule s, as AddJobOn would
+// add it.
+func ValidateOn(s Schedule, opts ...EntryOption) []Diagnostic {
+	return validate(&Entry{}, append([]EntryOption{onSchedule(s)}, opts...))
+}
+
+func validate(e *Entry, opts []EntryOption) []Diagnostic {
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e.lint(time.Now())
+}
+
+// lint returns the diagnostics about the definition of e, as of now.
+func (e *Entry) lint(now time.Time) []Diagnostic {
+	var ds []Diagnostic
+	add := func(code LintCode, format string, args ...interface{}) {
+		ds = append(ds, Diagnostic{Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+	if e.Schedule != nil {
+		lintSchedule(e.Schedule, add)
+	} else if e.Interval <= 0 && !e.setStartTime.IsZero() && e.setStartTime.Before(now.Add(-time.Minute)) {
+		add(LintStartPassed, "one-shot start time %s has passed, it runs at once", e.setStartTime)
+	}
+	period := e.period(now)
+	if period == 0 {
+		return ds
+	}
+	if period <= highFrequency {
+		add(LintHighFrequency, "runs every %s, once a minute or more often", period)
+	}
+	if e.Flex >= period {
+		add(LintFlexOverInterval, "flex %s of the preferred window is not shorter than the interval %s", e.Flex, period)
+	}
+	if e.FailureInterval >= period {
+		add(LintFailureInterval, "failure interval %s is not shorter than the interval %s", e.FailureInterval, period)
+	}
+	if e.Retries > 0 && e.RetryBackoff > 0 {
+		var total time.Duration
+		for i := 0; i < e.Retries; i++ {
+			total += e.RetryBackoff << i
+		}
+		if total >= period {
+			add(LintRetriesOverInterval, "%d retries back off for %s in all, not less than the interval %s", e.Retries, total, period)
+		}
+	}
+	if e.CacheFor >= period {
+		add(LintCacheOverInterval, "result cache of %s is not shorter than the interval %s, every scheduled run would be cached", e.CacheFor, period)
+	}
+	return ds
+}
+
+// period returns the shortest time between two runs of e after now, or zero
+// for a one-shot entry. Schedules are sampled over their next few
+// occurrences.
+func (e *Entry) period(now time.Time) time.Duration {
+	if e.Schedule == nil {
+		return max(e.Interval, 0)
+	}
+	loc := e.location
+	if loc == nil {
+		loc = time.Local
+	}
+	var period time.Duration
+	prev := e.Schedule.Next(now.In(loc))
+	for i := 0; i < 8 && !prev.IsZero(); i++ {
+		next := e.Schedule.Next(prev)
+		if next.IsZero() {
+			break
+		}
+		if gap := next.Sub(prev); period == 0 || gap < period {
+			period = gap
+		}
+		prev = next
+	}
+	return period
+}
+
+// lintSchedule checks s and the schedules it merges.
+func lintSchedule(s Schedule, add func(LintCode, string, ...interface{})) {
+	switch s := s.(type) {
+	case wallClock:
+		if s.hour < 0 || s.hour > 23 || s.min < 0 || s.min > 59 || s.sec < 0 || s.sec > 59 {
+			add(LintInvalidTime, "time of day %02d:%02d:%02d is out of range", s.hour, s.min, s.sec)
+		}
+		if s.weekly && (s.weekday < time.Sunday || s.weekday > time.Saturday) {
+			add(LintInvalidTime, "weekday %d is out of range", int(s.weekday))
+		}
+		return
+	case cronExpr:
+		if !s.domAny && !s.dowAny {
+			add(LintDomDow, "%s sets both the day of the month and the day of the week, it fires on the days matching either", s)
+		}
+	case zoned:
+		lintSchedule(s.s, add)
+		return
+	case merged:
+		seen := make(map[wallClock]bool)
+		for _, s := range s {
+			if w, ok := s.(wallClock); ok {
+				if seen[w] {
+					add(LintDuplicateSchedule, "merged schedule %s is given more than once", w)
+				}
+				seen[w] = true
+			}
+			lintSchedule(s, add)
+		}
+		if len(s) == 0 {
+			add(LintNeverFires, "merges no schedules")
+		}
+		return
+	}
+	if s != Never && s.Next(time.Now()).IsZero() {
+		add(LintNeverFires, "schedule has no occurrence after now")
+	}
+}
+
+// warnLint logs the diagnostics about an entry being added.
+func (c *Cron) warnLint(e *Entry) {
+	for _, d := range e.lint(e.now()) {
+		c.log(SubsystemLifecycle).Info("suspicious entry definition", "entry", e.Name, "code", string(d.Code), "diagnostic", d.Message)
+	}
+}
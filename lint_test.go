@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func lintCodes(ds []Diagnostic) []string {
+	var codes []string
+	for _, d := range ds {
+		codes = append(codes, string(d.Code))
+	}
+	return codes
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Now()
+	for _, test := range []struct {
+		name string
+		ds   []Diagnostic
+		want string
+	}{
+		{"hourly", Validate(now, time.Hour), ""},
+		{"every second", Validate(now, time.Second), "high_frequency"},
+		{"every minute", ValidateOn(mustParseCron(t, "*/1 * * * *")), "high_frequency"},
+		{"every other minute", ValidateOn(mustParseCron(t, "*/2 * * * *")), ""},
+		{"dom and dow", ValidateOn(mustParseCron(t, "0 0 1 * 1")), "dom_dow"},
+		{"dom and dow zoned", ValidateOn(mustParseCron(t, "CRON_TZ=UTC 0 0 1 * 1")), "dom_dow"},
+		{"dom only", ValidateOn(mustParseCron(t, "0 0 1 * *")), ""},
+		{"one-shot", Validate(now.Add(time.Hour), 0), ""},
+		{"one-shot passed", Validate(now.Add(-24*time.Hour), 0), "start_passed"},
+		{"daily", ValidateOn(Daily(2, 30, 0)), ""},
+		{"hour 24", ValidateOn(Daily(24, 0, 0)), "invalid_time"},
+		{"weekday 7", ValidateOn(Weekly(7, 9, 0)), "invalid_time"},
+		{"duplicate", ValidateOn(Merge(Daily(1, 0, 0), Weekly(time.Monday, 9, 0), Daily(1, 0, 0))), "duplicate_schedule"},
+		{"never", ValidateOn(Merge()), "never_fires"},
+		{"flex", Validate(now, time.Hour, WithPreferredWindow(time.Hour, 2*time.Hour, 2*time.Hour)), "flex_over_interval"},
+		{"failure interval", ValidateOn(Daily(1, 0, 0), WithFailureInterval(48*time.Hour)), "failure_interval"},
+		{"retries", Validate(now, time.Hour, WithRetries(3, 20*time.Minute)), "retries_over_interval"},
+		{"cache", Validate(now, time.Hour, WithResultCache(2*time.Hour)), "cache_over_interval"},
+	} {
+		if got := strings.Join(lintCodes(test.ds), ","); got != test.want {
+			t.Errorf("%s: expected %q, got %q (%v)", test.name, test.want, got, test.ds)
+		}
+	}
+}
+
+func TestLintOnAdd(t *testing.T) {
+	var out syncBuffer
+	cron := New(WithLogger(PrintfLogger(log.New(&out, "", 0))))
+	cron.AddFunc(time.Now(), time.Hour, func() {}, "hourly")
+	cron.AddFuncOn(Daily(25, 0, 0), func() {}, "late")
+
+	got := strings.TrimSpace(out.String())
+	if want := "suspicious entry definition, entry=late, code=invalid_time, diagnostic=time of day 25:00:00 is out of range"; got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func mustParseCron(t *testing.T, spec string) Schedule {
+	s, err := ParseCron(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
@@ -0,0 +1,96 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// Logger is the logging interface the scheduler uses, and hands to jobs.
+// It takes structured key/value pairs, in the style of logr.
+type Logger interface {
+	// Info logs routine messages about the scheduler and the jobs.
+	Info(msg string, keysAndValues ...interface{})
+
+	// Error logs an error condition.
+	Error(err error, msg string, keysAndValues ...interface{})
+}
+
+// DiscardLogger is a Logger that logs nothing. It is the default.
+var DiscardLogger Logger = PrintfLogger(log.New(io.Discard, "", 0))
+
+// PrintfLogger wraps a Printf-based logger (such as the standard library
+// "log") into an implementation of the Logger interface.
+func PrintfLogger(l interface{ Printf(string, ...interface{}) }) Logger {
+	return printfLogger{l}
+}
+
+type printfLogger struct {
+	logger interface{ Printf(string, ...interface{}) }
+}
+
+func (pl printfLogger) Info(msg string, keysAndValues ...interface{}) {
+	pl.logger.Printf("%s", formatLog(msg, keysAndValues))
+}
+
+func (pl printfLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	pl.logger.Printf("%s", formatLog(msg, append([]interface{}{"error", err}, keysAndValues...)))
+}
+
+// formatLog renders a message and key/value pairs as "msg, k=v, k=v".
+func formatLog(msg string, keysAndValues []interface{}) string {
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		b.WriteString(", ")
+		fmt.Fprint(&b, keysAndValues[i])
+		b.WriteString("=")
+		if i+1 < len(keysAndValues) {
+			fmt.Fprint(&b, keysAndValues[i+1])
+		}
+	}
+	return b.String()
+}
+
+// withFields returns a Logger that adds the given key/value pairs in front
+// of those of every message.
+func withFields(l Logger, keysAndValues ...interface{}) Logger {
+	if fl, ok := l.(fieldLogger); ok {
+		return fieldLogger{fl.parent, append(append([]interface{}(nil), fl.fields...), keysAndValues...)}
+	}
+	return fieldLogger{l, keysAndValues}
+}
+
+type fieldLogger struct {
+	parent Logger
+	fields []interface{}
+}
+
+func (fl fieldLogger) Info(msg string, keysAndValues ...interface{}) {
+	fl.parent.Info(msg, append(append([]interface{}(nil), fl.fields...), keysAndValues...)...)
+}
+
+func (fl fieldLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	fl.parent.Error(err, msg, append(append([]interface{}(nil), fl.fields...), keysAndValues...)...)
+}
+
+// Logger returns a logger for the job to use, whose messages name the entry,
+// its tags, the time the run was due and the attempt. It logs to the Logger
+// of the Cron, see WithLogger.
+func (p *Progress) Logger() Logger {
+	if p == nil || p.logger == nil {
+		return DiscardLogger
+	}
+	return p.logger
+}
+
+// entryLogger returns the logger of a run of t.
+func (c *Cron) entryLogger(t trigger) Logger {
+	fields := []interface{}{"entry", t.view.Name}
+	if len(t.view.Tags) > 0 {
+		fields = append(fields, "tags", strings.Join(t.view.Tags, ","))
+	}
+	fields = append(fields, "scheduled", t.scheduled, "attempt", t.attemptNumber())
+	return withFields(c.log(SubsystemJobs), fields...)
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestJobLoggerFields(t *testing.T) {
+	var out syncBuffer
+	cron := New(WithLogger(PrintfLogger(log.New(&out, "", 0))))
+	start := time.Now().Add(50 * time.Millisecond)
+	cron.AddProgressFunc(start, time.Hour, func(p *Progress) {
+		p.Logger().Info("copied rows", "rows", 42)
+	}, "etl", WithTags("nightly", "db"))
+	cron.Start()
+	defer cron.Stop()
+
+	waitForHistory(t, cron, "etl", 1)
+	want := "copied rows, entry=etl, tags=nightly,db, scheduled=" + start.String() + ", attempt=1, rows=42"
+	if got := strings.TrimSpace(out.String()); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestRecoveredPanicIsLogged(t *testing.T) {
+	var out syncBuffer
+	cron := New(WithLogger(PrintfLogger(log.New(&out, "", 0))))
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() { panic("boom") }, "panicky")
+	cron.Start()
+	defer cron.Stop()
+
+	waitForHistory(t, cron, "panicky", 1)
+	if got := out.String(); !strings.HasPrefix(got, "job panicked, error=boom, entry=panicky") {
+		t.Errorf("unexpected log %q", got)
+	}
+}
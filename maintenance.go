@@ -0,0 +1,146 @@
+package scheduler
+
+import "time"
+
+// MaintenanceWindow is a recurring span of time during which the entries
+// with any of its tags don't run, such as a weekly database maintenance.
+// It is registered on the scheduler, see AddMaintenanceWindow.
+type MaintenanceWindow struct {
+	Name string
+
+	// The entries the window pauses, by tag. No tags means every entry.
+	Tags []string
+
+	// When the window opens, and for how long.
+	Start    Schedule
+	Duration time.Duration
+}
+
+// maintenance is a registered window and the watcher emitting its events.
+type maintenance struct {
+	window MaintenanceWindow
+	stop   chan struct{}
+}
+
+// AddMaintenanceWindow registers w, replacing the window of the same name if
+// any. Triggers of the entries it pauses that come while it is open are
+// skipped and recorded as OutcomePaused; runs started with RunNow still run.
+// EventMaintenanceStarted and EventMaintenanceEnded are emitted as it opens
+// and closes while the scheduler runs.
+func (c *Cron) AddMaintenanceWindow(w MaintenanceWindow) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeMaintenance(w.Name)
+	if c.maintenance == nil {
+		c.maintenance = make(map[string]*maintenance)
+	}
+	m := &maintenance{window: w}
+	c.maintenance[w.Name] = m
+	if c.watching {
+		c.watch(m)
+	}
+}
+
+// RemoveMaintenanceWindow unregisters the named window.
+func (c *Cron) RemoveMaintenanceWindow(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeMaintenance(name)
+}
+
+// removeMaintenance unregisters the named window. The caller must hold c.mu.
+func (c *Cron) removeMaintenance(name string) {
+	if m, ok := c.maintenance[name]; ok {
+		c.unwatch(m)
+		delete(c.maintenance, name)
+	}
+}
+
+// watchMaintenance starts or stops emitting the events of the windows, as
+// the run loop starts and stops.
+func (c *Cron) watchMaintenance(on bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.watching = on
+	for _, m := range c.maintenance {
+		if on {
+			c.watch(m)
+		} else {
+			c.unwatch(m)
+		}
+	}
+}
+
+// watch starts the watcher of m. The caller must hold c.mu.
+func (c *Cron) watch(m *maintenance) {
+	m.stop = make(chan struct{})
+	go c.watchWindow(m.window, m.stop)
+}
+
+// unwatch stops the watcher of m, if any. The caller must hold c.mu.
+func (c *Cron) unwatch(m *maintenance) {
+	if m.stop != nil {
+		close(m.stop)
+		m.stop = nil
+	}
+}
+
+// watchWindow emits the events of w as it opens and closes, until stop is
+// closed.
+func (c *Cron) watchWindow(w MaintenanceWindow, stop <-chan struct{}) {
+	for {
+		now := time.Now()
+		start := w.Start.Next(now.Add(-w.Duration))
+		if start.IsZero() {
+			return
+		}
+		if start.After(now) {
+			select {
+			case <-time.After(start.Sub(now)):
+			case <-stop:
+				return
+			}
+		}
+		c.log(SubsystemLifecycle).Info("maintenance window open", "window", w.Name)
+		c.emit(Event{Type: EventMaintenanceStarted, Name: w.Name})
+
+		select {
+		case <-time.After(time.Until(start.Add(w.Duration))):
+		case <-stop:
+			return
+		}
+		c.log(SubsystemLifecycle).Info("maintenance window closed", "window", w.Name)
+		c.emit(Event{Type: EventMaintenanceEnded, Name: w.Name})
+	}
+}
+
+// paused reports whether an open maintenance window pauses the entry.
+func (c *Cron) paused(e *Entry, at time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range c.maintenance {
+		if m.window.open(at) && m.window.pauses(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// open reports whether the window is open at t.
+func (w MaintenanceWindow) open(t time.Time) bool {
+	start := w.Start.Next(t.Add(-w.Duration))
+	return !start.IsZero() && !start.After(t)
+}
+
+// pauses reports whether the window applies to the entry.
+func (w MaintenanceWindow) pauses(e *Entry) bool {
+	if len(w.Tags) == 0 {
+		return true
+	}
+	for _, tag := range w.Tags {
+		if e.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
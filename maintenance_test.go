@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+// every is a Schedule of a time every interval from a start.
+type every struct {
+	from     time.Time
+	interval time.Duration
+}
+
+func (s every) Next(t time.Time) time.Time {
+	if t.Before(s.from) {
+		return s.from
+	}
+	return s.from.Add((t.Sub(s.from)/s.interval + 1) * s.interval)
+}
+
+func TestMaintenanceWindowPausesTaggedEntries(t *testing.T) {
+	events := make(chan Event, 100)
+	cron := New(WithEventHandler(func(e Event) { events <- e }))
+	cron.AddMaintenanceWindow(MaintenanceWindow{
+		Name:     "db-upgrade",
+		Tags:     []string{"db"},
+		Start:    every{from: time.Now().Add(-time.Second), interval: time.Hour},
+		Duration: time.Second + 300*time.Millisecond,
+	})
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() {}, "vacuum", WithTags("db"))
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() {}, "report")
+	cron.Start()
+	defer cron.Stop()
+
+	if e := waitForEvent(t, events, EventMaintenanceStarted); e.Name != "db-upgrade" {
+		t.Errorf("expected the db-upgrade window to open, got %q", e.Name)
+	}
+	if r := waitForHistory(t, cron, "vacuum", 1)[0]; r.Outcome != OutcomePaused {
+		t.Errorf("expected the tagged run to be paused, got %s", r.Outcome)
+	}
+	if r := waitForHistory(t, cron, "report", 1)[0]; r.Outcome != OutcomeSuccess {
+		t.Errorf("expected the untagged run to run, got %s", r.Outcome)
+	}
+	if s, _ := cron.Stats("vacuum"); s.Skipped != 1 {
+		t.Errorf("expected the paused run to count as skipped, got %+v", s)
+	}
+	if r, _ := cron.RunNow("vacuum"); r.Outcome != OutcomeSuccess {
+		t.Errorf("expected RunNow to run through the window, got %s", r.Outcome)
+	}
+
+	waitForEvent(t, events, EventMaintenanceEnded)
+	if r, _ := cron.RunNow("vacuum"); r.Outcome != OutcomeSuccess {
+		t.Errorf("expected a run after the window, got %s", r.Outcome)
+	}
+}
+
+func TestMaintenanceWindowRecurs(t *testing.T) {
+	events := make(chan Event, 100)
+	cron := New(WithEventHandler(func(e Event) { events <- e }))
+	cron.Start()
+	defer cron.Stop()
+
+	// Added while running, opening every 200ms for 50ms.
+	cron.AddMaintenanceWindow(MaintenanceWindow{
+		Name:     "flush",
+		Start:    every{from: time.Now().Add(100 * time.Millisecond), interval: 200 * time.Millisecond},
+		Duration: 50 * time.Millisecond,
+	})
+	for i := 0; i < 2; i++ {
+		waitForEvent(t, events, EventMaintenanceStarted)
+		waitForEvent(t, events, EventMaintenanceEnded)
+	}
+
+	cron.RemoveMaintenanceWindow("flush")
+	time.Sleep(50 * time.Millisecond)
+	for len(events) > 0 {
+		<-events
+	}
+	select {
+	case e := <-events:
+		t.Errorf("expected no events once removed, got %s", e.Type)
+	case <-time.After(400 * time.Millisecond):
+	}
+}
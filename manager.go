@@ -0,0 +1,108 @@
+package scheduler
+
+import "sync"
+
+// Manager owns several named Cron instances, for example one per tenant or
+// per priority class, and starts, stops and inspects them together. The
+// Crons it creates share its worker pool, if it has one.
+type Manager struct {
+	mu      sync.Mutex
+	pool    *WorkerPool
+	names   []string
+	crons   map[string]*Cron
+	running bool
+}
+
+// NewManager returns a Manager whose Crons share pool. pool may be nil, in
+// which case each Cron runs its jobs without a bound.
+func NewManager(pool *WorkerPool) *Manager {
+	return &Manager{pool: pool, crons: make(map[string]*Cron)}
+}
+
+// ManagedEntry is an entry along with the name of the Cron it belongs to.
+type ManagedEntry struct {
+	Cron string
+	*Entry
+}
+
+// Add creates a new Cron with the given name and options. If the Manager is
+// running, so is the new Cron. Adding a name twice returns the existing Cron.
+func (m *Manager) Add(name string, opts ...Option) *Cron {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c, ok := m.crons[name]; ok {
+		return c
+	}
+	c := New(append([]Option{WithWorkerPool(m.pool)}, opts...)...)
+	m.crons[name] = c
+	m.names = append(m.names, name)
+	if m.running {
+		c.Start()
+	}
+	return c
+}
+
+// Cron returns the named Cron, or nil if there is none.
+func (m *Manager) Cron(name string) *Cron {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.crons[name]
+}
+
+// Remove stops the named Cron and forgets about it.
+func (m *Manager) Remove(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.crons[name]
+	if !ok {
+		return
+	}
+	c.Stop()
+	delete(m.crons, name)
+	for i, n := range m.names {
+		if n == name {
+			m.names = append(m.names[:i], m.names[i+1:]...)
+			break
+		}
+	}
+}
+
+// Names returns the names of the Crons, in the order they were added.
+func (m *Manager) Names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.names...)
+}
+
+// Start starts all the Crons.
+func (m *Manager) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.running = true
+	for _, name := range m.names {
+		m.crons[name].Start()
+	}
+}
+
+// Stop stops all the Crons.
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.running = false
+	for _, name := range m.names {
+		m.crons[name].Stop()
+	}
+}
+
+// Entries returns a snapshot of the entries of all the Crons.
+func (m *Manager) Entries() []ManagedEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var all []ManagedEntry
+	for _, name := range m.names {
+		for _, e := range m.crons[name].Entries() {
+			all = append(all, ManagedEntry{Cron: name, Entry: e})
+		}
+	}
+	return all
+}
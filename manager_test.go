@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestManagerCombinedEntries(t *testing.T) {
+	m := NewManager(nil)
+	m.Add("tenant-a").AddFunc(time.Now(), time.Hour, func() {}, "report")
+	m.Add("tenant-b").AddFunc(time.Now(), time.Hour, func() {}, "report")
+	m.Start()
+	defer m.Stop()
+
+	entries := m.Entries()
+	if len(entries) != 2 || entries[0].Cron != "tenant-a" || entries[1].Cron != "tenant-b" {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+	if m.Add("tenant-a") != m.Cron("tenant-a") {
+		t.Error("expected adding an existing name to return the existing cron")
+	}
+}
+
+// Crons of the same manager share the worker pool.
+func TestManagerSharedPool(t *testing.T) {
+	m := NewManager(NewWorkerPool(1))
+	var running, maxRunning int32
+	job := func() {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+	}
+	start := time.Now().Add(100 * time.Millisecond)
+	a, b := m.Add("a"), m.Add("b")
+	a.AddFunc(start, time.Hour, job, "job")
+	b.AddFunc(start, time.Hour, job, "job")
+	m.Start()
+	defer m.Stop()
+
+	waitForHistory(t, a, "job", 1)
+	waitForHistory(t, b, "job", 1)
+	if max := atomic.LoadInt32(&maxRunning); max != 1 {
+		t.Errorf("expected at most 1 job at a time, got %d", max)
+	}
+}
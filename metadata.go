@@ -0,0 +1,34 @@
+package scheduler
+
+// WithMetadata attaches md to the entry, such as its owner, team or runbook
+// URL. The scheduler does nothing with it but carry it along: it is in
+// Entries, the View, the events, hand-offs and the admin API. Giving
+// WithMetadata more than once merges the maps.
+func WithMetadata(md map[string]string) EntryOption {
+	return func(e *Entry) {
+		if e.Metadata == nil {
+			e.Metadata = make(map[string]string, len(md))
+		}
+		for k, v := range md {
+			e.Metadata[k] = v
+		}
+	}
+}
+
+// Metadata returns a copy of the metadata of the entry, see WithMetadata.
+func (v EntryView) Metadata() map[string]string {
+	v.c.entriesMu.RLock()
+	defer v.c.entriesMu.RUnlock()
+	return cloneMetadata(v.e.Metadata)
+}
+
+func cloneMetadata(md map[string]string) map[string]string {
+	if md == nil {
+		return nil
+	}
+	cp := make(map[string]string, len(md))
+	for k, v := range md {
+		cp[k] = v
+	}
+	return cp
+}
@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMetadataCarriedAlong(t *testing.T) {
+	events := make(chan Event, 100)
+	cron := New(WithEventHandler(func(e Event) { events <- e }))
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "job",
+		WithMetadata(map[string]string{"owner": "billing"}),
+		WithMetadata(map[string]string{"runbook": "https://wiki/job"}))
+	cron.Start()
+	defer cron.Stop()
+	want := map[string]string{"owner": "billing", "runbook": "https://wiki/job"}
+
+	if e := waitForEvent(t, events, EventAdded); !reflect.DeepEqual(e.Metadata, want) {
+		t.Errorf("expected the added event to carry %v, got %v", want, e.Metadata)
+	}
+	snapshot := cron.Entries()[0]
+	if !reflect.DeepEqual(snapshot.Metadata, want) {
+		t.Errorf("expected the snapshot to carry %v, got %v", want, snapshot.Metadata)
+	}
+	snapshot.Metadata["owner"] = "someone else"
+	if v, _ := cron.View().Entry("job"); !reflect.DeepEqual(v.Metadata(), want) {
+		t.Errorf("expected the view to show %v, got %v", want, v.Metadata())
+	}
+
+	cron.RunNow("job")
+	if e := waitForEvent(t, events, EventRun); !reflect.DeepEqual(e.Metadata, want) {
+		t.Errorf("expected the run event to carry %v, got %v", want, e.Metadata)
+	}
+
+	w := adminRequest(t, NewAdmin(cron, func(*http.Request) Role { return RoleReader }), "GET", "/entries", "")
+	var entries []adminEntry
+	if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || !reflect.DeepEqual(entries[0].Metadata, want) {
+		t.Errorf("expected the admin API to show %v, got %s", want, w.Body)
+	}
+}
+
+func TestMetadataHandedOff(t *testing.T) {
+	store := NewMemoryStore()
+	old := New(WithJobStore(store))
+	old.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "job", WithMetadata(map[string]string{"owner": "billing"}))
+	old.Start()
+
+	replacement := New(WithJobStore(store))
+	replacement.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "job")
+	took := make(chan error, 1)
+	go func() { took <- replacement.Takeover(context.Background()) }()
+	if err := old.Drain(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-took; err != nil {
+		t.Fatal(err)
+	}
+	defer replacement.Stop()
+
+	if got := replacement.Entries()[0].Metadata["owner"]; got != "billing" {
+		t.Errorf("expected the metadata to be handed off, got %q", got)
+	}
+}
@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Metrics receives measurements about the runs of the jobs. Its methods are
+// called from the goroutines running the jobs, so they must be safe for
+// concurrent use and should not block.
+type Metrics interface {
+	// RunFinished is called after each run, vetoed ones included. The
+	// lateness of the run is r.Start.Sub(r.Scheduled) and its runtime
+	// r.End.Sub(r.Start).
+	RunFinished(r RunRecord)
+
+	// TriggerDropped is called when a trigger of the named entry is dropped
+	// because too many runs were already pending. See WithMaxPending.
+	TriggerDropped(name string)
+}
+
+// DogStatsD is a Metrics that sends to a DogStatsD agent, Datadog's flavor
+// of StatsD, over UDP. Every metric is tagged with the entry name as "job"
+// and, for runs, the outcome, the reason if any and the labels of the run,
+// besides the tags given to NewDogStatsD:
+//
+//	<prefix>.run.count       counter
+//	<prefix>.run.duration    timer, in milliseconds
+//	<prefix>.run.lateness    timer, in milliseconds
+//	<prefix>.trigger.dropped counter
+//
+// Send errors are ignored, as is usual with StatsD.
+type DogStatsD struct {
+	conn   net.Conn
+	prefix string
+	tags   []string
+}
+
+// NewDogStatsD returns a DogStatsD sending to addr, usually
+// "127.0.0.1:8125", with metric names starting with prefix and the given
+// extra tags, in Datadog's "key:value" form.
+func NewDogStatsD(addr, prefix string, tags ...string) (*DogStatsD, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, ".") {
+		prefix += "."
+	}
+	return &DogStatsD{conn: conn, prefix: prefix, tags: tags}, nil
+}
+
+// Close closes the connection to the agent.
+func (d *DogStatsD) Close() error { return d.conn.Close() }
+
+func (d *DogStatsD) RunFinished(r RunRecord) {
+	run := []string{"job:" + r.Name, "outcome:" + string(r.Outcome)}
+	if r.Reason != "" {
+		run = append(run, "reason:"+string(r.Reason))
+	}
+	tags := d.tagged(append(run, labelTags(r.Labels)...)...)
+	d.send("run.count", "1|c", tags)
+	d.send("run.duration", millis(r.End.Sub(r.Start))+"|ms", tags)
+	d.send("run.lateness", millis(r.Start.Sub(r.Scheduled))+"|ms", tags)
+}
+
+func (d *DogStatsD) TriggerDropped(name string) {
+	d.send("trigger.dropped", "1|c", d.tagged("job:"+name))
+}
+
+// labelTags returns the labels as "key:value" tags, sorted.
+func labelTags(labels map[string]string) []string {
+	tags := make([]string, 0, len(labels))
+	for key, value := range labels {
+		tags = append(tags, key+":"+value)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+func (d *DogStatsD) tagged(tags ...string) string {
+	all := append(append([]string(nil), d.tags...), tags...)
+	for i, tag := range all {
+		all[i] = statsdTagReplacer.Replace(tag)
+	}
+	return strings.Join(all, ",")
+}
+
+func (d *DogStatsD) send(name, value, tags string) {
+	fmt.Fprintf(d.conn, "%s%s:%s|#%s", d.prefix, name, value, tags)
+}
+
+// statsdTagReplacer drops the characters that delimit the parts of a
+// DogStatsD datagram.
+var statsdTagReplacer = strings.NewReplacer(",", "_", "|", "_", "#", "_", "\n", "_")
+
+func millis(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	return fmt.Sprintf("%g", float64(d)/float64(time.Millisecond))
+}
@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDogStatsD(t *testing.T) {
+	agent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer agent.Close()
+
+	d, err := NewDogStatsD(agent.LocalAddr().String(), "sched", "env:test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	start := time.Now()
+	d.RunFinished(RunRecord{
+		Name:      "nightly,report",
+		Scheduled: start.Add(-1500 * time.Microsecond),
+		Start:     start,
+		End:       start.Add(2 * time.Second),
+		Outcome:   OutcomeSuccess,
+	})
+	d.TriggerDropped("nightly")
+
+	var got []string
+	buf := make([]byte, 512)
+	agent.SetReadDeadline(time.Now().Add(ONE_SECOND))
+	for len(got) < 4 {
+		n, _, err := agent.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("got %q before %v", got, err)
+		}
+		got = append(got, string(buf[:n]))
+	}
+	sort.Strings(got)
+	want := []string{
+		"sched.run.count:1|c|#env:test,job:nightly_report,outcome:success",
+		"sched.run.duration:2000|ms|#env:test,job:nightly_report,outcome:success",
+		"sched.run.lateness:1.5|ms|#env:test,job:nightly_report,outcome:success",
+		"sched.trigger.dropped:1|c|#env:test,job:nightly",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected datagrams:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+type recordingMetrics struct {
+	runs chan RunRecord
+}
+
+func (m recordingMetrics) RunFinished(r RunRecord) { m.runs <- r }
+func (m recordingMetrics) TriggerDropped(string)   {}
+
+func TestMetricsHook(t *testing.T) {
+	m := recordingMetrics{make(chan RunRecord, 1)}
+	cron := New(WithMetrics(m))
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() {}, "job")
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case r := <-m.runs:
+		if r.Name != "job" || r.Outcome != OutcomeSuccess {
+			t.Errorf("unexpected record %+v", r)
+		}
+	case <-time.After(ONE_SECOND):
+		t.Fatal("run was not reported")
+	}
+}
@@ -0,0 +1,330 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Option represents a modification to the default behavior of a Cron.
+type Option func(*Cron)
+
+// WithPanicPolicy sets what happens when a job panics. The default is
+// PanicRecover.
+func WithPanicPolicy(p PanicPolicy) Option {
+	return func(c *Cron) {
+		c.panicPolicy = p
+	}
+}
+
+// WithPanicReporter passes every recovered panic on to r.
+func WithPanicReporter(r PanicReporter) Option {
+	return func(c *Cron) {
+		c.panicReporter = r
+	}
+}
+
+// WithErrorHandler calls h with the name of the entry and the value of every
+// recovered panic, or the error of every ErrJob that returned one, that is
+// for every run whose job failed. For panics it is a lighter
+// WithPanicReporter, and works alongside it.
+func WithErrorHandler(h func(name string, err interface{})) Option {
+	return func(c *Cron) {
+		c.errorHandler = h
+	}
+}
+
+// WithInterceptor installs an Interceptor, which is consulted before every
+// run of every entry.
+func WithInterceptor(i Interceptor) Option {
+	return func(c *Cron) {
+		c.interceptor = i
+	}
+}
+
+// WithWorkerPool runs the jobs in the given pool, which may be shared with
+// other Crons. Without one, every run gets its own goroutine straight away.
+func WithWorkerPool(p *WorkerPool) Option {
+	return func(c *Cron) {
+		c.pool = p
+	}
+}
+
+// WithTenantPool runs the jobs of the tenant, by default a namespace (see
+// WithTenant), in a pool of their own instead of that of WithWorkerPool, so
+// the tenants of the shared pool can't take the workers the tenant needs,
+// nor the other way around. A pool may be given to several tenants.
+func WithTenantPool(tenant string, p *WorkerPool) Option {
+	return func(c *Cron) {
+		if c.tenantPools == nil {
+			c.tenantPools = make(map[string]*WorkerPool)
+		}
+		c.tenantPools[tenant] = p
+	}
+}
+
+// WithMetrics reports the runs of the jobs to m.
+func WithMetrics(m Metrics) Option {
+	return func(c *Cron) {
+		c.metrics = m
+	}
+}
+
+// WithBudget caps what the matching entries may spend per window. It may be
+// given several times. A budget without a Window is ignored: there is no
+// window for it to cap the spending over, so it is unlimited.
+func WithBudget(b Budget) Option {
+	return func(c *Cron) {
+		if b.Window <= 0 {
+			return
+		}
+		c.budgets = append(c.budgets, &budgetState{Budget: b})
+	}
+}
+
+// WithResourceLimit allows at most max runs using the named resource at the
+// same time. See WithResources.
+func WithResourceLimit(resource string, max int) Option {
+	return func(c *Cron) {
+		if c.resources == nil {
+			c.resources = make(map[string]*WorkerPool)
+		}
+		c.resources[resource] = NewWorkerPool(max)
+	}
+}
+
+// WithJobStore persists the state of the scheduler in store, see Drain and
+// Takeover.
+func WithJobStore(store JobStore) Option {
+	return func(c *Cron) {
+		c.store = store
+	}
+}
+
+// WithEventHandler calls h with every Event, in order, from a goroutine of
+// its own.
+func WithEventHandler(h func(Event)) Option {
+	return func(c *Cron) {
+		c.onEvent = h
+	}
+}
+
+// WithEventLog writes every Event to w as a line of JSON, for log shippers
+// to pick up: its time, type, entry name, version and metadata, and for
+// EventRun the run, without its output. Writes happen off the run loop, in
+// order, and a failed write stops the event log. It works alongside
+// WithEventHandler.
+func WithEventLog(w io.Writer) Option {
+	return func(c *Cron) {
+		c.eventLog = newEventLog(w)
+	}
+}
+
+// WithChangeFeed publishes every change of the definitions of the entries
+// to sink, so that an external catalog stays in sync with what the
+// scheduler runs. Changes are published in order, off the run loop; one
+// that keeps failing is retried a few times, then logged and dropped.
+func WithChangeFeed(sink ChangeSink) Option {
+	return func(c *Cron) {
+		c.changeFeed = &changeFeed{sink: sink}
+	}
+}
+
+// WithBackfillRate sets how long Backfill waits between enqueueing two runs.
+// The default is one second.
+func WithBackfillRate(every time.Duration) Option {
+	return func(c *Cron) {
+		c.backfillRate = every
+	}
+}
+
+// WithArchiveTTL sets how long retired entries are kept by Archived. The
+// default is a day.
+func WithArchiveTTL(ttl time.Duration) Option {
+	return func(c *Cron) {
+		c.archiveTTL = ttl
+	}
+}
+
+// WithLogger logs what the scheduler does to l, and hands the jobs a logger
+// derived from it, see Progress.Logger. Use SlogLogger to log to a
+// *slog.Logger, with a level for each subsystem.
+func WithLogger(l Logger) Option {
+	return func(c *Cron) {
+		c.logger = l
+	}
+}
+
+// WithLocation sets the location the scheduler works in: the daily windows
+// of the entries are in it, and their NextTime is given in it. The default
+// is time.Local.
+func WithLocation(loc *time.Location) Option {
+	return func(c *Cron) {
+		c.location = loc
+	}
+}
+
+// WithMultiRegion is for fleets of schedulers spanning regions: they all
+// schedule in UTC, as if WithLocation(time.UTC), so that they agree on when
+// each entry is due whatever their local time. Entries show their times in
+// their own location, see WithDisplayLocation, and a warning is logged for
+// those with a schedule or preferred window in local time, which go by UTC.
+func WithMultiRegion() Option {
+	return func(c *Cron) {
+		c.location = time.UTC
+		c.multiRegion = true
+	}
+}
+
+// WithInstanceLabels labels the instance, for the entries of a fleet that
+// are pinned to instances with some labels, see WithAffinity.
+func WithInstanceLabels(labels ...string) Option {
+	return func(c *Cron) {
+		if c.instanceLabels == nil {
+			c.instanceLabels = make(map[string]bool, len(labels))
+		}
+		for _, label := range labels {
+			c.instanceLabels[label] = true
+		}
+	}
+}
+
+// WithTriggerFence fences the runs of a fleet of schedulers sharing their
+// entries against clock skew. Before a run starts, the clock of the
+// coordination backend is read through now, and the run is held until that
+// clock is at least the time the run was due less tolerance, so an
+// instance whose clock runs ahead doesn't start runs early. A run is held
+// for a minute at most, and goes ahead after that. An instance whose clock
+// is off the backend's by more than tolerance logs a warning. Runs started
+// by RunNow aren't held.
+func WithTriggerFence(tolerance time.Duration, now func(ctx context.Context) (time.Time, error)) Option {
+	return func(c *Cron) {
+		c.fenceTolerance = tolerance
+		c.clusterNow = now
+	}
+}
+
+// WithSecrets resolves the secrets jobs ask for through p, see
+// Progress.Secret.
+func WithSecrets(p SecretProvider) Option {
+	return func(c *Cron) {
+		c.secrets = p
+	}
+}
+
+// WithStormProtection guards against trigger storms: when more than
+// threshold entries come due at once, as they may after a long GC pause or
+// a clock step, their runs are spread evenly over the smear window instead
+// of all starting together.
+func WithStormProtection(threshold int, smear time.Duration) Option {
+	return func(c *Cron) {
+		c.stormThreshold = threshold
+		c.stormSmear = smear
+	}
+}
+
+// WithWarmup ramps up how many runs may go at once after Start, or
+// Takeover: from runs at first, up linearly to to runs by the end of
+// period, when the cap is lifted. A service restarted with many overdue
+// entries then eases into running them instead of hitting the systems they
+// depend on all at once. Runs held back by the ramp count as pending.
+func WithWarmup(period time.Duration, from, to int) Option {
+	return func(c *Cron) {
+		c.warmup = newWarmup(period, from, to)
+	}
+}
+
+// WithChain wraps the job of every run in the given wrappers, the first
+// being the outermost one, see Chain. Entries may add their own inside
+// them, see WithEntryChain, or replace them, see WithChainOverride.
+func WithChain(wrappers ...JobWrapper) Option {
+	return func(c *Cron) {
+		c.wrappers = append(c.wrappers, wrappers...)
+	}
+}
+
+// WithCronFormat parses the crontab expressions of AddCron, AddCronJob and
+// of the schedules of JobDefinitions in format, such as CronSeconds for
+// expressions with a seconds field. Entries may have their own, see
+// WithEntryCronFormat. The default is CronStandard.
+func WithCronFormat(format CronFormat) Option {
+	return func(c *Cron) {
+		c.cronFormat = format
+	}
+}
+
+// WithExecutor carries out the runs with x, see Executor. Entries may have
+// their own, see WithEntryExecutor.
+func WithExecutor(x Executor) Option {
+	return func(c *Cron) {
+		c.executor = x
+	}
+}
+
+// WithRunNowDedup coalesces a RunNow with the next scheduled run of the
+// entry if that is due within window: the manual run goes right away in its
+// place, and the schedule moves on to the run after. The record of the run
+// has Coalesced set, and the time the scheduled run was due as Scheduled.
+func WithRunNowDedup(window time.Duration) Option {
+	return func(c *Cron) {
+		c.runNowDedup = window
+	}
+}
+
+// WithDuplicatePolicy sets what adding an entry under a name that is taken
+// does. The default is DuplicateReplace.
+func WithDuplicatePolicy(p DuplicatePolicy) Option {
+	return func(c *Cron) {
+		c.duplicates = p
+	}
+}
+
+// WithTenant sets how the entries are grouped into tenants, which the
+// WorkerPool shares its workers fairly between. The default groups them by
+// namespace.
+func WithTenant(tenant func(e *Entry) string) Option {
+	return func(c *Cron) {
+		c.tenant = tenant
+	}
+}
+
+// WithClock runs the scheduler on clock instead of the system one: the run
+// loop tells the time and sleeps until the next runs by it. The runs
+// themselves go by the system clock. See FakeClock.
+func WithClock(clock Clock) Option {
+	return func(c *Cron) {
+		c.clock = clock
+	}
+}
+
+// WithTrace records every wake-up of the run loop, its decisions and what
+// became of each trigger to w, one JSON TraceEvent a line, for Replay to
+// reproduce the scheduling later on.
+func WithTrace(w io.Writer) Option {
+	return func(c *Cron) {
+		c.tracer = newTracer(w)
+	}
+}
+
+// WithMetricLabels lets the labels of the entries with the given keys, see
+// Entry.Labels, through to the Metrics, as RunRecord.Labels, and to the
+// jobs, for their tracing spans, see LabelsFromContext. Each key multiplies
+// the number of series the metrics make by the number of its values, so
+// only keys with a handful of them should be let through. By default none
+// are.
+func WithMetricLabels(keys ...string) Option {
+	return func(c *Cron) {
+		c.metricLabels = make(map[string]bool, len(keys))
+		for _, key := range keys {
+			c.metricLabels[key] = true
+		}
+	}
+}
+
+// WithFaults injects the faults f into the scheduler. It is meant for tests
+// only, see Faults.
+func WithFaults(f Faults) Option {
+	return func(c *Cron) {
+		c.faults = newFaultInjector(f)
+	}
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// PanicPolicy decides what happens when a job panics.
+type PanicPolicy int
+
+const (
+	// Recover from the panic and keep running the entry on its schedule.
+	PanicRecover PanicPolicy = iota
+
+	// Recover from the panic and disable the entry, so it doesn't run again.
+	PanicDisable
+
+	// Let the panic crash the program.
+	PanicCrash
+)
+
+// PanicReport describes a recovered panic, for a PanicReporter.
+type PanicReport struct {
+	// Copy of the entry whose job panicked, and when the run was due.
+	Entry     *Entry
+	Scheduled time.Time
+
+	// What the job panicked with, and the stack of the goroutine running it
+	// at the time.
+	Value interface{}
+	Stack []byte
+}
+
+// PanicReporter is told about every recovered panic, typically to forward it
+// to an error tracker such as Sentry. It is called from the goroutine of the
+// run that panicked.
+type PanicReporter func(PanicReport)
+
+// invokeRecovering runs the job of t and returns what it panicked with, as
+// an error, if it did.
+// Recovered panics are written to the run output, with their stack, and
+// passed on to the PanicReporter and the error handler.
+func (c *Cron) invokeRecovering(j Job, t trigger, p *Progress) (err error) {
+	if c.panicPolicy != PanicCrash {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%v", r)
+				stack := debug.Stack()
+				fmt.Fprintf(p.Output(), "panic: %v\n\n%s", r, stack)
+				p.Logger().Error(err, "job panicked", "stack", string(stack))
+				if c.panicReporter != nil {
+					c.panicReporter(PanicReport{Entry: t.view, Scheduled: t.scheduled, Value: r, Stack: stack})
+				}
+				if c.errorHandler != nil {
+					c.errorHandler(t.view.Name, r)
+				}
+			}
+		}()
+	}
+	invoke(j, p)
+	return nil
+}
@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPanicRecoverKeepsRunning(t *testing.T) {
+	cron := New()
+	cron.AddFunc(time.Now().Add(100*time.Millisecond), 100*time.Millisecond, func() {
+		panic("boom")
+	}, "panicky")
+	cron.Start()
+	defer cron.Stop()
+
+	runs := waitForHistory(t, cron, "panicky", 2)
+	if runs[0].Outcome != OutcomePanic || !strings.Contains(runs[0].Output, "boom") {
+		t.Errorf("unexpected record %+v", runs[0])
+	}
+	e := cron.Entries()[0]
+	if e.Panics < 2 || e.Disabled {
+		t.Errorf("expected the entry to keep running, got %d panics, disabled %v", e.Panics, e.Disabled)
+	}
+}
+
+func TestPanicDisableStopsEntry(t *testing.T) {
+	cron := New(WithPanicPolicy(PanicDisable))
+	cron.AddFunc(time.Now().Add(100*time.Millisecond), 100*time.Millisecond, func() {
+		panic("boom")
+	}, "panicky")
+	cron.Start()
+	defer cron.Stop()
+
+	waitForHistory(t, cron, "panicky", 1)
+	time.Sleep(300 * time.Millisecond)
+
+	e := cron.Entries()[0]
+	if e.Panics != 1 || !e.Disabled {
+		t.Errorf("expected the entry to be disabled after 1 panic, got %d panics, disabled %v", e.Panics, e.Disabled)
+	}
+	if n := len(cron.History("panicky")); n != 1 {
+		t.Errorf("expected 1 run, got %d", n)
+	}
+}
+
+func panicInThirdPartyCode() {
+	var m map[string]int
+	m["boom"]++
+}
+
+func TestPanicReporter(t *testing.T) {
+	reports := make(chan PanicReport, 1)
+	cron := New(WithPanicReporter(func(r PanicReport) { reports <- r }))
+	start := time.Now().Add(50 * time.Millisecond)
+	cron.AddFunc(start, time.Hour, panicInThirdPartyCode, "panicky", WithTags("billing"))
+	cron.Start()
+	defer cron.Stop()
+
+	var r PanicReport
+	select {
+	case r = <-reports:
+	case <-time.After(ONE_SECOND):
+		t.Fatal("panic was not reported")
+	}
+	if r.Entry.Name != "panicky" || !r.Entry.HasTag("billing") || !r.Scheduled.Equal(start) {
+		t.Errorf("unexpected entry metadata %+v at %v", r.Entry, r.Scheduled)
+	}
+	if !strings.Contains(fmt.Sprint(r.Value), "nil map") {
+		t.Errorf("unexpected panic value %v", r.Value)
+	}
+	if !strings.Contains(string(r.Stack), "panicInThirdPartyCode") {
+		t.Errorf("expected the stack to show where the panic happened, got\n%s", r.Stack)
+	}
+
+	run := waitForHistory(t, cron, "panicky", 1)[0]
+	if !strings.Contains(run.Output, "panicInThirdPartyCode") {
+		t.Errorf("expected the stack in the run output, got %q", run.Output)
+	}
+}
+
+func TestErrorHandler(t *testing.T) {
+	type failure struct {
+		name string
+		err  interface{}
+	}
+	failures := make(chan failure, 1)
+	reports := make(chan PanicReport, 1)
+	cron := New(
+		WithErrorHandler(func(name string, err interface{}) { failures <- failure{name, err} }),
+		WithPanicReporter(func(r PanicReport) { reports <- r }),
+	)
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() { panic("boom") }, "failing")
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case f := <-failures:
+		if f.name != "failing" || f.err != "boom" {
+			t.Errorf("unexpected failure %+v", f)
+		}
+	case <-time.After(ONE_SECOND):
+		t.Fatal("the error handler was not called")
+	}
+	select {
+	case <-reports:
+	case <-time.After(ONE_SECOND):
+		t.Error("expected the panic reporter to be called as well")
+	}
+}
@@ -0,0 +1,220 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrStalePlan is returned by Apply for a Plan made against entries that
+// have changed since.
+var ErrStalePlan = errors.New("scheduler: the entries changed since the plan was made")
+
+// Plan is what it takes to go from the entries of a scheduler to a new
+// declarative config, as worked out by Plan, to be reviewed, say by a
+// GitOps pipeline, before it is given to Apply.
+type Plan struct {
+	// The changes, removals last, each in the order of the names.
+	Changes []PlanChange
+
+	entries map[string]*Entry
+	defs    map[string]JobDefinition
+}
+
+// PlanChange is a change of a Plan to one entry.
+type PlanChange struct {
+	// EventAdded, EventUpdated or EventRemoved.
+	Type EventType `json:"type"`
+	Name string    `json:"name"`
+
+	// The version of the entry the change was planned against, none for an
+	// addition.
+	Version int `json:"version,omitempty"`
+
+	// When the entry runs, before and after the change, such as
+	// "weekly monday 09:00" or "every 1h0m0s from 2026-01-02T15:04:05Z".
+	OldSchedule string `json:"oldSchedule,omitempty"`
+	NewSchedule string `json:"newSchedule,omitempty"`
+
+	// For an update, what changed: "schedule", "kind", "params", "tags",
+	// "namespace" or "metadata".
+	Fields []string `json:"fields,omitempty"`
+}
+
+func (pc PlanChange) String() string {
+	switch pc.Type {
+	case EventAdded:
+		return fmt.Sprintf("+ %s: %s", pc.Name, pc.NewSchedule)
+	case EventRemoved:
+		return fmt.Sprintf("- %s: %s", pc.Name, pc.OldSchedule)
+	}
+	s := fmt.Sprintf("~ %s: %s", pc.Name, strings.Join(pc.Fields, ", "))
+	if pc.OldSchedule != pc.NewSchedule {
+		s += fmt.Sprintf(" (%s -> %s)", pc.OldSchedule, pc.NewSchedule)
+	}
+	return s
+}
+
+// Empty reports whether the plan changes nothing.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String lists the changes of the plan, one a line, as a diff would: + for
+// an addition, ~ for an update and - for a removal.
+func (p *Plan) String() string {
+	var b strings.Builder
+	for _, pc := range p.Changes {
+		b.WriteString(pc.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Plan works out how to go from the entries to those defs define, for Apply.
+// The config is what runs, in full: the entries defined by a kind, see
+// JobDefinition, that aren't in defs are removed, while those added by code
+// are left alone. The entries whose definition is unchanged are left as
+// they are, keeping their version and state. Plan fails, changing nothing,
+// on a definition that cannot be added, returning the errors of all of
+// them joined.
+func (c *Cron) Plan(defs []JobDefinition) (*Plan, error) {
+	p := &Plan{entries: make(map[string]*Entry), defs: make(map[string]JobDefinition)}
+	var errs []error
+	for _, def := range defs {
+		if _, dup := p.defs[def.Name]; dup {
+			errs = append(errs, fmt.Errorf("scheduler: %q is defined twice", def.Name))
+			continue
+		}
+		e, err := c.defined(def)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("scheduler: definition %q: %w", def.Name, err))
+			continue
+		}
+		p.entries[def.Name] = e
+		p.defs[def.Name] = def
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	var changes, removals []PlanChange
+	c.inLoop(func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for name, e := range p.entries {
+			cur := c.lookup(name)
+			if cur == nil {
+				changes = append(changes, PlanChange{Type: EventAdded, Name: name, NewSchedule: planSchedule(definitionDoc(e))})
+				continue
+			}
+			old, next := definitionDoc(cur), definitionDoc(e)
+			if fields := definitionDiff(old, next); fields != nil {
+				changes = append(changes, PlanChange{
+					Type: EventUpdated, Name: name, Version: cur.Version,
+					OldSchedule: planSchedule(old), NewSchedule: planSchedule(next),
+					Fields: fields,
+				})
+			}
+		}
+		for _, cur := range c.entries {
+			if _, kept := p.entries[cur.Name]; !kept && cur.Kind != "" {
+				removals = append(removals, PlanChange{
+					Type: EventRemoved, Name: cur.Name, Version: cur.Version,
+					OldSchedule: planSchedule(definitionDoc(cur)),
+				})
+			}
+		}
+	})
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	sort.Slice(removals, func(i, j int) bool { return removals[i].Name < removals[j].Name })
+	p.Changes = append(changes, removals...)
+	return p, nil
+}
+
+// Apply makes the changes of the plan, all at once: the run loop sees the
+// entries as they were or as the plan leaves them, nothing in between. If
+// any entry the plan changes was added, updated or removed since Plan, it
+// changes nothing and returns ErrStalePlan, and a new plan is needed. The
+// definitions added or updated are stored in the JobStore, if any, as by
+// AddDefinition; the error says which could not be, though they are
+// applied.
+func (c *Cron) Apply(p *Plan) error {
+	stale := false
+	c.inLoop(func() {
+		for _, pc := range p.Changes {
+			cur := c.lookup(pc.Name)
+			if pc.Type == EventAdded && cur != nil || pc.Type != EventAdded && (cur == nil || cur.Version != pc.Version) {
+				stale = true
+				return
+			}
+		}
+		for _, pc := range p.Changes {
+			if pc.Type == EventRemoved {
+				c.drop(pc.Name)
+				continue
+			}
+			e := p.entries[pc.Name]
+			e.update = true
+			e.persisted = c.store != nil
+			if err := c.put(e); err == nil && c.running {
+				e.Next()
+				c.reschedule(e)
+				c.watchSources(e)
+			}
+		}
+	})
+	if stale {
+		return ErrStalePlan
+	}
+	var errs []error
+	for _, pc := range p.Changes {
+		if pc.Type == EventRemoved {
+			continue
+		}
+		if err := c.storeDefinition(p.defs[pc.Name]); err != nil {
+			errs = append(errs, fmt.Errorf("scheduler: %q applied but not stored: %w", pc.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// planSchedule describes when the entry of d runs.
+func planSchedule(d *DefinitionDoc) string {
+	switch {
+	case d.Schedule != "":
+		return d.Schedule
+	case d.Interval > 0:
+		return fmt.Sprintf("every %s from %s", d.Interval, d.Start.UTC().Format(time.RFC3339))
+	}
+	return "once at " + d.Start.UTC().Format(time.RFC3339)
+}
+
+// definitionDiff returns the fields that differ between the definitions,
+// or nil if none do.
+func definitionDiff(old, next *DefinitionDoc) []string {
+	var fields []string
+	if planSchedule(old) != planSchedule(next) {
+		fields = append(fields, "schedule")
+	}
+	if old.Kind != next.Kind {
+		fields = append(fields, "kind")
+	}
+	// An empty map is as good as none.
+	if (len(old.Params) != 0 || len(next.Params) != 0) && !reflect.DeepEqual(old.Params, next.Params) {
+		fields = append(fields, "params")
+	}
+	if strings.Join(old.Tags, ",") != strings.Join(next.Tags, ",") {
+		fields = append(fields, "tags")
+	}
+	if old.Namespace != next.Namespace {
+		fields = append(fields, "namespace")
+	}
+	if (len(old.Metadata) != 0 || len(next.Metadata) != 0) && !reflect.DeepEqual(old.Metadata, next.Metadata) {
+		fields = append(fields, "metadata")
+	}
+	return fields
+}
@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPlanAndApply(t *testing.T) {
+	store := NewMemoryStore()
+	cron := New(WithJobStore(store))
+	cron.RegisterJobFactory("noop", func(map[string]interface{}) Job { return FuncJob(func() {}) })
+	cron.AddFunc(time.Now(), time.Hour, func() {}, "by-code")
+	for _, def := range []JobDefinition{
+		{Name: "kept", Kind: "noop", Schedule: "daily 03:00"},
+		{Name: "moved", Kind: "noop", Schedule: "daily 04:00", Params: map[string]interface{}{"to": "ops"}},
+		{Name: "dropped", Kind: "noop", Every: "1h"},
+	} {
+		if _, err := cron.AddDefinition(def); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	config := []JobDefinition{
+		{Name: "kept", Kind: "noop", Schedule: "daily 03:00"},
+		{Name: "moved", Kind: "noop", Schedule: "daily 05:00", Params: map[string]interface{}{"to": "ops"}},
+		{Name: "new", Kind: "noop", Schedule: "@hourly"},
+	}
+	plan, err := cron.Plan(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "~ moved: schedule (daily 04:00 -> daily 05:00)\n+ new: @hourly\n- dropped: every 1h0m0s from 0001-01-01T00:00:00Z\n"
+	if got := plan.String(); got != want {
+		t.Errorf("expected the plan\n%s\ngot\n%s", want, got)
+	}
+	if _, ok := cron.Entry("new"); ok {
+		t.Error("expected Plan to change nothing")
+	}
+
+	if err := cron.Apply(plan); err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]int{}
+	for _, e := range cron.Entries() {
+		names[e.Name] = e.Version
+	}
+	if len(names) != 4 || names["kept"] != 1 || names["moved"] != 2 || names["new"] != 1 || names["by-code"] != 1 {
+		t.Errorf("unexpected entries after the apply %v", names)
+	}
+	if e, _ := cron.Entry("moved"); e.NextTime.Hour() != 5 {
+		t.Errorf("expected the updated schedule to apply, next run at %v", e.NextTime)
+	}
+	if keys, _ := store.Keys(definitionPrefix); len(keys) != 3 {
+		t.Errorf("expected the stored definitions to follow, got %v", keys)
+	}
+
+	// Applying again is stale, as is a plan the entries moved on from.
+	if err := cron.Apply(plan); !errors.Is(err, ErrStalePlan) {
+		t.Errorf("expected a stale plan, got %v", err)
+	}
+	if plan, err := cron.Plan(config); err != nil || !plan.Empty() {
+		t.Errorf("expected nothing left to do, got %v %v", plan, err)
+	}
+	plan, _ = cron.Plan(config[:2])
+	cron.UpdateJob(time.Now(), time.Minute, FuncJob(func() {}), "new")
+	if err := cron.Apply(plan); !errors.Is(err, ErrStalePlan) {
+		t.Errorf("expected a stale plan, got %v", err)
+	}
+	if _, ok := cron.Entry("new"); !ok {
+		t.Error("expected a stale plan to change nothing")
+	}
+}
+
+func TestPlanRejectsBadConfig(t *testing.T) {
+	cron := New()
+	cron.RegisterJobFactory("noop", func(map[string]interface{}) Job { return FuncJob(func() {}) })
+	_, err := cron.Plan([]JobDefinition{
+		{Name: "a", Kind: "noop", Every: "1h"},
+		{Name: "a", Kind: "noop", Every: "2h"},
+		{Name: "b", Kind: "missing", Every: "1h"},
+	})
+	if !errors.Is(err, ErrUnknownJobKind) {
+		t.Errorf("expected the errors of the config, got %v", err)
+	}
+	if len(cron.Entries()) != 0 {
+		t.Error("expected a bad config to change nothing")
+	}
+}
@@ -0,0 +1,164 @@
+package scheduler
+
+import (
+	"sort"
+	"sync"
+)
+
+// WorkerPool bounds how many jobs run at the same time across all the Crons
+// that share it. Runs that find the pool full wait for a free worker.
+//
+// The waiting runs are served fairly across tenants, by default the
+// namespaces of their entries (see WithTenant): a free worker goes to the
+// tenant that got the least of its share of the pool lately, so that a
+// burst of runs from one tenant doesn't starve the others. Within a tenant,
+// runs are served in order.
+type WorkerPool struct {
+	mu         sync.Mutex
+	size, busy int
+
+	// Runs waiting for a worker, by tenant.
+	waiting map[string][]chan struct{}
+
+	// Weighted fair queuing: each tenant has a virtual time, advanced by the
+	// inverse of its weight each time it gets a worker, and the tenant with
+	// the earliest one gets the next worker. clock is the virtual time of
+	// the last worker given, which tenants that were idle start from.
+	weights map[string]int
+	pass    map[string]float64
+	clock   float64
+}
+
+// NewWorkerPool returns a pool of size workers.
+func NewWorkerPool(size int) *WorkerPool {
+	if size < 1 {
+		size = 1
+	}
+	return &WorkerPool{
+		size:    size,
+		waiting: make(map[string][]chan struct{}),
+		weights: make(map[string]int),
+		pass:    make(map[string]float64),
+	}
+}
+
+// Size returns the number of workers.
+func (p *WorkerPool) Size() int { return p.size }
+
+// Busy returns the number of workers currently running a job.
+func (p *WorkerPool) Busy() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.busy
+}
+
+// SetWeight sets the share of the pool the tenant gets when the pool is
+// saturated, relative to the others. The default weight is 1.
+func (p *WorkerPool) SetWeight(tenant string, weight int) {
+	if weight < 1 {
+		weight = 1
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.weights[tenant] = weight
+}
+
+func (p *WorkerPool) acquire() { p.acquireFor("") }
+
+// acquireFor waits for a worker for a run of tenant.
+func (p *WorkerPool) acquireFor(tenant string) {
+	p.mu.Lock()
+	if p.busy < p.size && len(p.waiting) == 0 {
+		p.busy++
+		p.grant(tenant)
+		p.mu.Unlock()
+		return
+	}
+	ready := make(chan struct{})
+	p.waiting[tenant] = append(p.waiting[tenant], ready)
+	p.mu.Unlock()
+	<-ready
+}
+
+func (p *WorkerPool) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.waiting) == 0 {
+		p.busy--
+		return
+	}
+	// The worker goes straight to the next run, so busy stays the same.
+	tenant := p.next()
+	queue := p.waiting[tenant]
+	if len(queue) == 1 {
+		delete(p.waiting, tenant)
+	} else {
+		p.waiting[tenant] = queue[1:]
+	}
+	p.grant(tenant)
+	close(queue[0])
+}
+
+// next returns the waiting tenant to give a worker to. The caller must hold
+// p.mu.
+func (p *WorkerPool) next() string {
+	tenants := make([]string, 0, len(p.waiting))
+	for t := range p.waiting {
+		tenants = append(tenants, t)
+	}
+	sort.Strings(tenants)
+	best := tenants[0]
+	for _, t := range tenants[1:] {
+		if p.start(t) < p.start(best) {
+			best = t
+		}
+	}
+	return best
+}
+
+// start returns the virtual time the next worker of tenant would be given
+// at. The caller must hold p.mu.
+func (p *WorkerPool) start(tenant string) float64 {
+	if pass := p.pass[tenant]; pass > p.clock {
+		return pass
+	}
+	return p.clock
+}
+
+// grant accounts for a worker given to tenant. The caller must hold p.mu.
+func (p *WorkerPool) grant(tenant string) {
+	weight := p.weights[tenant]
+	if weight < 1 {
+		weight = 1
+	}
+	p.clock = p.start(tenant)
+	p.pass[tenant] = p.clock + 1/float64(weight)
+}
+
+// queued returns the number of runs waiting for a worker.
+func (p *WorkerPool) queued() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, q := range p.waiting {
+		n += len(q)
+	}
+	return n
+}
+
+// poolOf returns the pool the runs of tenant go to: its own, see
+// WithTenantPool, or else the one of the scheduler, if any.
+func (c *Cron) poolOf(tenant string) *WorkerPool {
+	if p, ok := c.tenantPools[tenant]; ok {
+		return p
+	}
+	return c.pool
+}
+
+// tenantOf returns the tenant of the entry, see WithTenant.
+func (c *Cron) tenantOf(e *Entry) string {
+	if c.tenant != nil {
+		return c.tenant(e)
+	}
+	return e.Namespace
+}
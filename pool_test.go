@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// Saturate a pool with a burst from one tenant, expect another tenant's runs
+// to get workers in turn rather than after the whole burst.
+func TestWorkerPoolFairness(t *testing.T) {
+	pool := NewWorkerPool(1)
+	pool.acquireFor("noisy")
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	enqueue := func(tenant string, n int) {
+		for i := 0; i < n; i++ {
+			want := pool.queued() + 1
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				pool.acquireFor(tenant)
+				mu.Lock()
+				order = append(order, tenant)
+				mu.Unlock()
+				pool.release()
+			}()
+			for pool.queued() < want {
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}
+	enqueue("noisy", 6)
+	enqueue("quiet", 2)
+	pool.release()
+	wg.Wait()
+
+	// The noisy tenant just had a worker, so quiet goes first.
+	want := []string{"quiet", "noisy", "quiet", "noisy", "noisy", "noisy", "noisy", "noisy"}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, order)
+		}
+	}
+	if pool.Busy() != 0 {
+		t.Errorf("expected the pool to be idle, got %d busy", pool.Busy())
+	}
+}
+
+func TestWorkerPoolWeights(t *testing.T) {
+	pool := NewWorkerPool(1)
+	pool.SetWeight("big", 3)
+	// Both backlogged: big should get three workers for each of small's.
+	pool.waiting["big"] = make([]chan struct{}, 100)
+	pool.waiting["small"] = make([]chan struct{}, 100)
+	counts := map[string]int{}
+	for i := 0; i < 40; i++ {
+		tenant := pool.next()
+		pool.grant(tenant)
+		counts[tenant]++
+	}
+	if counts["big"] != 30 || counts["small"] != 10 {
+		t.Errorf("expected a 3:1 split, got %v", counts)
+	}
+}
+
+// A tenant with a pool of its own runs while the shared pool is saturated.
+func TestTenantPool(t *testing.T) {
+	shared := NewWorkerPool(1)
+	cron := New(WithWorkerPool(shared), WithTenantPool("system", NewWorkerPool(1)))
+	release := make(chan struct{})
+	start := time.Now().Add(50 * time.Millisecond)
+	cron.AddFunc(start, time.Hour, func() { <-release }, "batch-1", WithNamespace("batch"))
+	cron.AddFunc(start, time.Hour, func() { <-release }, "batch-2", WithNamespace("batch"))
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "health", WithNamespace("system"))
+	cron.Start()
+	defer cron.Stop()
+	defer close(release)
+
+	// Once the batch tenant holds the shared worker, the system tenant
+	// still runs, in its own pool.
+	for deadline := time.Now().Add(2 * time.Second); shared.Busy() != 1; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the batch tenant to hold the shared worker, got %d busy", shared.Busy())
+		}
+	}
+	done := make(chan RunRecord)
+	go func() {
+		r, _ := cron.RunNow("health")
+		done <- r
+	}()
+	select {
+	case r := <-done:
+		if r.Outcome != OutcomeSuccess {
+			t.Errorf("expected the system tenant to run, got %q", r.Outcome)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the system tenant not to wait for the shared worker")
+	}
+	if busy := shared.Busy(); busy != 1 {
+		t.Errorf("expected the batch tenant to hold the shared worker, got %d busy", busy)
+	}
+}
@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Progress is the handle a ProgressJob uses to report how far along its
+// current run is. The latest report is visible through Cron.Entries, so a
+// slow batch job can be told apart from one that hung.
+type Progress struct {
+	mu   sync.Mutex
+	last ProgressReport
+
+	// Heartbeat bookkeeping, see heartbeat.go.
+	lastBeat time.Time
+	stalled  bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Captured output of the run, see history.go.
+	output *outputBuffer
+
+	logger Logger
+
+	// Where Secret resolves secrets, see secret.go.
+	secrets SecretProvider
+
+	// The scratchpad of the entry, see scratch.go.
+	scratch *Scratchpad
+
+	// The run, as handed to the job and to remote workers, see runinfo.go
+	// and skew.go.
+	info    RunInfo
+	trigger TriggerMessage
+
+	// See SetTraceID.
+	traceID string
+
+	// The error of an ErrJob, see errjob.go.
+	err error
+}
+
+// newProgress returns the handle for a new run.
+func newProgress() *Progress {
+	return &Progress{output: &outputBuffer{limit: outputLimit}}
+}
+
+// start marks the beginning of the run, whose context derives from parent.
+func (p *Progress) start(parent context.Context) {
+	p.ctx, p.cancel = context.WithCancel(parent)
+	p.mu.Lock()
+	p.lastBeat = time.Now()
+	p.mu.Unlock()
+}
+
+// Context returns the context of the run. It is cancelled once the run is
+// over, or when the scheduler gives up on it (see WithHeartbeat), so long
+// running jobs should watch its Done channel.
+func (p *Progress) Context() context.Context {
+	if p == nil || p.ctx == nil {
+		return context.Background()
+	}
+	return p.ctx
+}
+
+// ProgressReport is the latest value reported through a Progress.
+type ProgressReport struct {
+	// Completion in percent, between 0 and 100.
+	Percent float64
+
+	// Free form description of what the job is doing.
+	Message string
+
+	// When the report was made. This is the zero time if the run has not
+	// reported anything yet.
+	Time time.Time
+}
+
+// Report records that the run is pct percent done. Values outside of 0-100
+// are clamped. A report also counts as a heartbeat.
+func (p *Progress) Report(pct float64, msg string) {
+	if p == nil {
+		return
+	}
+	if pct < 0 {
+		pct = 0
+	} else if pct > 100 {
+		pct = 100
+	}
+	now := time.Now()
+	p.mu.Lock()
+	p.last = ProgressReport{Percent: pct, Message: msg, Time: now}
+	p.lastBeat = now
+	p.mu.Unlock()
+}
+
+// Last returns the latest report.
+func (p *Progress) Last() ProgressReport {
+	if p == nil {
+		return ProgressReport{}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.last
+}
+
+// ProgressJob is a job that reports its progress while running.
+type ProgressJob interface {
+	Run(progress *Progress)
+}
+
+// A wrapper that turns a func(*Progress) into a ProgressJob.
+type ProgressFuncJob func(*Progress)
+
+func (f ProgressFuncJob) Run(p *Progress) { f(p) }
+
+// AddProgressFunc adds a func that reports its progress to the Cron to be run
+// on the given schedule.
+func (c *Cron) AddProgressFunc(startTime time.Time, Interval time.Duration, cmd func(*Progress), name string, opts ...EntryOption) error {
+	return c.AddProgressJob(startTime, Interval, ProgressFuncJob(cmd), name, opts...)
+}
+
+// AddProgressJob adds a ProgressJob to the Cron to be run on the given
+// schedule.
+func (c *Cron) AddProgressJob(startTime time.Time, Interval time.Duration, cmd ProgressJob, name string, opts ...EntryOption) error {
+	return c.Schedule(startTime, Interval, progressJob{cmd}, name, opts...)
+}
+
+// progressJob adapts a ProgressJob so it can be stored as an Entry's Job.
+// Run outside of the scheduler, the reports go nowhere.
+type progressJob struct {
+	job ProgressJob
+}
+
+func (j progressJob) Run() {
+	p := newProgress()
+	p.start(context.Background())
+	defer p.cancel()
+	j.job.Run(p)
+}
+
+// invoke runs the job, handing it p if it wants one.
+func invoke(j Job, p *Progress) {
+	if pj, ok := j.(progressJob); ok {
+		pj.job.Run(p)
+		return
+	}
+	j.Run()
+}
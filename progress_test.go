@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+// Start a job that reports progress, expect Entries to show the report while
+// the job is still running.
+func TestProgressVisibleInEntries(t *testing.T) {
+	cron := New()
+	release := make(chan struct{})
+	reported := make(chan struct{})
+	cron.AddProgressFunc(time.Now().Add(100*time.Millisecond), time.Hour, func(p *Progress) {
+		p.Report(60, "loading batch 3/5")
+		close(reported)
+		<-release
+	}, "batch")
+	cron.Start()
+	defer cron.Stop()
+	defer close(release)
+
+	select {
+	case <-reported:
+	case <-time.After(ONE_SECOND):
+		t.Fatal("job did not run")
+	}
+
+	report := cron.Entries()[0].Progress
+	if report.Percent != 60 || report.Message != "loading batch 3/5" {
+		t.Errorf("unexpected progress report: %+v", report)
+	}
+	if report.Time.IsZero() {
+		t.Error("expected the report to be timestamped")
+	}
+}
+
+func TestProgressReportClamps(t *testing.T) {
+	var p Progress
+	p.Report(150, "")
+	if got := p.Last().Percent; got != 100 {
+		t.Errorf("expected 100, got %v", got)
+	}
+	p.Report(-3, "")
+	if got := p.Last().Percent; got != 0 {
+		t.Errorf("expected 0, got %v", got)
+	}
+}
+
+// A ProgressJob scheduled through AddJob's adapter can still run on its own.
+func TestProgressJobRunsOutsideScheduler(t *testing.T) {
+	ran := false
+	job := progressJob{ProgressFuncJob(func(p *Progress) {
+		p.Report(10, "")
+		ran = true
+	})}
+	job.Run()
+	if !ran {
+		t.Error("expected the wrapped job to run")
+	}
+}
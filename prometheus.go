@@ -0,0 +1,273 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SetTraceID records the ID of the trace the run is part of, typically that
+// of the span the job started, so the metrics of the run link to it, see
+// PrometheusMetrics. It ends up in RunRecord.TraceID.
+func (p *Progress) SetTraceID(id string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.traceID = id
+	p.mu.Unlock()
+}
+
+// TraceID returns the ID given to SetTraceID.
+func (p *Progress) TraceID() string {
+	if p == nil {
+		return ""
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.traceID
+}
+
+// A metric exported by PrometheusMetrics, see prometheusMetrics.
+type promMetric struct {
+	name, typ, help string
+}
+
+// prometheusMetrics are the metrics PrometheusMetrics exports, and that
+// GrafanaDashboard charts, without the namespace.
+var prometheusMetrics = struct {
+	runs, duration, lateness, dropped promMetric
+}{
+	runs:     promMetric{"runs_total", "counter", "Runs of the entries, by outcome."},
+	duration: promMetric{"run_duration_seconds", "histogram", "How long the runs of the entries took."},
+	lateness: promMetric{"run_lateness_seconds", "histogram", "How late the runs of the entries started."},
+	dropped:  promMetric{"triggers_dropped_total", "counter", "Triggers dropped as too many runs were pending."},
+}
+
+// DefaultBuckets are the buckets of the histograms of PrometheusMetrics
+// unless given others, in seconds, from 10ms to an hour.
+var DefaultBuckets = []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 3600}
+
+// PrometheusMetrics is a Metrics kept in memory and served, as an
+// http.Handler, in the Prometheus text format:
+//
+//	<namespace>_runs_total             counter, by job, outcome and reason
+//	<namespace>_run_duration_seconds   histogram, by job and outcome
+//	<namespace>_run_lateness_seconds   histogram, by job
+//	<namespace>_triggers_dropped_total counter, by job
+//
+// The series of the runs also have the labels let through by
+// WithMetricLabels. Scrapers asking for OpenMetrics, as Prometheus does
+// with exemplar storage enabled, get the latest run in each bucket of the
+// duration histogram as an exemplar, with its trace_id, for the runs that
+// have one, see Progress.SetTraceID. GrafanaDashboard charts these metrics.
+type PrometheusMetrics struct {
+	namespace string
+	buckets   []float64
+
+	mu       sync.Mutex
+	runs     map[string]float64
+	duration map[string]*promHistogram
+	lateness map[string]*promHistogram
+	dropped  map[string]float64
+}
+
+// promHistogram is a histogram with the latest exemplar of each bucket, the
+// last bucket being +Inf.
+type promHistogram struct {
+	counts    []uint64
+	sum       float64
+	exemplars []promExemplar
+}
+
+type promExemplar struct {
+	traceID string
+	value   float64
+	at      time.Time
+}
+
+// NewPrometheusMetrics returns a PrometheusMetrics whose metric names start
+// with namespace, usually "scheduler", and whose histograms have the given
+// upper bounds, in seconds, or DefaultBuckets if none.
+func NewPrometheusMetrics(namespace string, buckets ...float64) *PrometheusMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &PrometheusMetrics{
+		namespace: namespace,
+		buckets:   buckets,
+		runs:      make(map[string]float64),
+		duration:  make(map[string]*promHistogram),
+		lateness:  make(map[string]*promHistogram),
+		dropped:   make(map[string]float64),
+	}
+}
+
+func (m *PrometheusMetrics) RunFinished(r RunRecord) {
+	keys := make([]string, 0, len(r.Labels))
+	for key := range r.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	labels := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		labels = append(labels, promName(key), r.Labels[key])
+	}
+	job := append([]string{"job", r.Name}, labels...)
+	outcome := append([]string{"job", r.Name, "outcome", string(r.Outcome)}, labels...)
+	runs := append([]string{"job", r.Name, "outcome", string(r.Outcome), "reason", string(r.Reason)}, labels...)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runs[promLabels(runs)]++
+	// Skipped runs took no time, and didn't start late either.
+	if r.Outcome.skipped() {
+		return
+	}
+	m.observe(m.duration, promLabels(outcome), r.End.Sub(r.Start).Seconds(), r.TraceID, r.End)
+	m.observe(m.lateness, promLabels(job), math.Max(r.Start.Sub(r.Scheduled).Seconds(), 0), "", r.End)
+}
+
+func (m *PrometheusMetrics) TriggerDropped(name string) {
+	m.mu.Lock()
+	m.dropped[promLabels([]string{"job", name})]++
+	m.mu.Unlock()
+}
+
+// observe adds v to the histogram of the series. The caller must hold m.mu.
+func (m *PrometheusMetrics) observe(series map[string]*promHistogram, labels string, v float64, traceID string, at time.Time) {
+	h := series[labels]
+	if h == nil {
+		h = &promHistogram{counts: make([]uint64, len(m.buckets)+1), exemplars: make([]promExemplar, len(m.buckets)+1)}
+		series[labels] = h
+	}
+	i := sort.SearchFloat64s(m.buckets, v)
+	h.counts[i]++
+	h.sum += v
+	if traceID != "" {
+		h.exemplars[i] = promExemplar{traceID: traceID, value: v, at: at}
+	}
+}
+
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	open := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+	if open {
+		w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	}
+	m.Expose(w, open)
+}
+
+// Expose writes the metrics to w in the Prometheus text format, or with
+// openMetrics, in OpenMetrics with the exemplars.
+func (m *PrometheusMetrics) Expose(w io.Writer, openMetrics bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pm := prometheusMetrics
+	var b strings.Builder
+	m.writeCounter(&b, pm.runs, m.runs, openMetrics)
+	m.writeHistogram(&b, pm.duration, m.duration, openMetrics)
+	m.writeHistogram(&b, pm.lateness, m.lateness, openMetrics)
+	m.writeCounter(&b, pm.dropped, m.dropped, openMetrics)
+	if openMetrics {
+		b.WriteString("# EOF\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// header writes the HELP and TYPE lines of the metric. OpenMetrics names
+// counters without their _total suffix.
+func (m *PrometheusMetrics) header(b *strings.Builder, metric promMetric, openMetrics bool) string {
+	name := m.name(metric)
+	family := name
+	if openMetrics && metric.typ == "counter" {
+		family = strings.TrimSuffix(name, "_total")
+	}
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", family, metric.help, family, metric.typ)
+	return name
+}
+
+func (m *PrometheusMetrics) writeCounter(b *strings.Builder, metric promMetric, series map[string]float64, openMetrics bool) {
+	name := m.header(b, metric, openMetrics)
+	keys := make([]string, 0, len(series))
+	for labels := range series {
+		keys = append(keys, labels)
+	}
+	sort.Strings(keys)
+	for _, labels := range keys {
+		fmt.Fprintf(b, "%s{%s} %g\n", name, labels, series[labels])
+	}
+}
+
+func (m *PrometheusMetrics) writeHistogram(b *strings.Builder, metric promMetric, series map[string]*promHistogram, openMetrics bool) {
+	name := m.header(b, metric, openMetrics)
+	keys := make([]string, 0, len(series))
+	for labels := range series {
+		keys = append(keys, labels)
+	}
+	sort.Strings(keys)
+	for _, labels := range keys {
+		h := series[labels]
+		var count uint64
+		for i, n := range h.counts {
+			count += n
+			le := "+Inf"
+			if i < len(m.buckets) {
+				le = strconv.FormatFloat(m.buckets[i], 'g', -1, 64)
+			}
+			fmt.Fprintf(b, "%s_bucket{%s,le=%q} %d", name, labels, le, count)
+			if x := h.exemplars[i]; openMetrics && x.traceID != "" {
+				fmt.Fprintf(b, " # {trace_id=%s} %g %.3f", promQuote(x.traceID), x.value, float64(x.at.UnixMilli())/1000)
+			}
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(b, "%s_sum{%s} %g\n%s_count{%s} %d\n", name, labels, h.sum, name, labels, count)
+	}
+}
+
+// name returns the full name of the metric.
+func (m *PrometheusMetrics) name(metric promMetric) string {
+	if m.namespace == "" {
+		return metric.name
+	}
+	return promName(m.namespace) + "_" + metric.name
+}
+
+// promLabels renders the label pairs of a series, names and values
+// alternating.
+func promLabels(pairs []string) string {
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, pairs[i]+"="+promQuote(pairs[i+1]))
+	}
+	return strings.Join(parts, ",")
+}
+
+var promEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func promQuote(s string) string {
+	return `"` + promEscaper.Replace(s) + `"`
+}
+
+// promName makes s a valid metric or label name, replacing what it may not
+// contain with underscores.
+func promName(s string) string {
+	b := []byte(s)
+	for i, ch := range b {
+		ok := ch == '_' || 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || i > 0 && '0' <= ch && ch <= '9'
+		if !ok {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	m := NewPrometheusMetrics("scheduler", 0.1, 1)
+	start := time.Date(2030, 1, 2, 3, 0, 0, 0, time.UTC)
+	m.RunFinished(RunRecord{Name: "report", Outcome: OutcomeSuccess, Scheduled: start, Start: start.Add(2 * time.Second), End: start.Add(2500 * time.Millisecond),
+		TraceID: "4bf92f3577b34da6", Labels: map[string]string{"team": "billing", "tier": "1"}})
+	m.RunFinished(RunRecord{Name: "report", Outcome: OutcomeOverBudget, Reason: "budget", Scheduled: start, Start: start, End: start})
+	m.TriggerDropped("report")
+
+	get := func(accept string) string {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+	open := get("application/openmetrics-text; version=1.0.0")
+	for _, want := range []string{
+		"# TYPE scheduler_runs counter\n",
+		`scheduler_runs_total{job="report",outcome="success",reason="",team="billing",tier="1"} 1`,
+		`scheduler_runs_total{job="report",outcome="over_budget",reason="budget"} 1`,
+		`scheduler_run_duration_seconds_bucket{job="report",outcome="success",team="billing",tier="1",le="1"} 1 # {trace_id="4bf92f3577b34da6"} 0.5 1893553202.500`,
+		`scheduler_run_duration_seconds_count{job="report",outcome="success",team="billing",tier="1"} 1`,
+		`scheduler_run_lateness_seconds_bucket{job="report",team="billing",tier="1",le="0.1"} 0`,
+		`scheduler_run_lateness_seconds_sum{job="report",team="billing",tier="1"} 2`,
+		`scheduler_triggers_dropped_total{job="report"} 1`,
+		"# EOF\n",
+	} {
+		if !strings.Contains(open, want) {
+			t.Errorf("expected %q in\n%s", want, open)
+		}
+	}
+	plain := get("text/plain")
+	if strings.Contains(plain, "trace_id") || strings.Contains(plain, "# EOF") || !strings.Contains(plain, "# TYPE scheduler_runs_total counter\n") {
+		t.Errorf("expected the Prometheus text format without exemplars, got\n%s", plain)
+	}
+}
+
+func TestRunTraceID(t *testing.T) {
+	cron := New()
+	cron.AddProgressFunc(time.Now().Add(50*time.Millisecond), time.Hour, func(p *Progress) { p.SetTraceID("abc") }, "job")
+	cron.Start()
+	defer cron.Stop()
+	if r := waitForHistory(t, cron, "job", 1)[0]; r.TraceID != "abc" {
+		t.Errorf("expected the trace ID in the history, got %q", r.TraceID)
+	}
+}
+
+// The dashboard shipped in grafana/ is the one GrafanaDashboard generates.
+func TestGrafanaDashboard(t *testing.T) {
+	b, err := GrafanaDashboard("scheduler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d struct {
+		Panels []struct {
+			Targets []struct {
+				Expr     string
+				Exemplar bool
+			}
+		}
+	}
+	if err := json.Unmarshal(b, &d); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Panels) == 0 || !strings.Contains(d.Panels[0].Targets[0].Expr, "scheduler_runs_total") {
+		t.Errorf("unexpected panels %+v", d.Panels)
+	}
+	shipped, err := os.ReadFile("grafana/scheduler.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bytes.TrimSpace(shipped), b) {
+		t.Error("grafana/scheduler.json is out of date, regenerate it with GrafanaDashboard(\"scheduler\")")
+	}
+}
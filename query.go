@@ -0,0 +1,167 @@
+package scheduler
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// HistoryQuery selects runs from the history of the entries, see
+// QueryHistory. The zero value of each field selects every run.
+type HistoryQuery struct {
+	// The entries, by name and by tag.
+	Names []string
+	Tag   string
+
+	// How the runs ended.
+	Outcomes []Outcome
+
+	// When the runs started: at or after From, and before To.
+	From, To time.Time
+
+	// How long the runs went, at least.
+	MinDuration time.Duration
+
+	// The page of the runs matching, newest first: Limit runs from Offset
+	// on. No limit means all of them.
+	Offset, Limit int
+}
+
+// HistoryPage is a page of the runs a HistoryQuery selected.
+type HistoryPage struct {
+	Runs []RunRecord `json:"runs"`
+
+	// How many runs matched in all.
+	Total int `json:"total"`
+}
+
+// QueryHistory returns the runs in the history that match q, newest first.
+func (c *Cron) QueryHistory(q HistoryQuery) HistoryPage {
+	tagged := c.tagged(q.Tag)
+	runs := []RunRecord{}
+	c.mu.Lock()
+	for name, history := range c.history {
+		if !q.picks(name, tagged) {
+			continue
+		}
+		for _, r := range history {
+			if q.matches(r) {
+				runs = append(runs, r)
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		if !runs[i].Start.Equal(runs[j].Start) {
+			return runs[i].Start.After(runs[j].Start)
+		}
+		return runs[i].Name < runs[j].Name
+	})
+	page := HistoryPage{Total: len(runs)}
+	if q.Offset < len(runs) {
+		runs = runs[max(q.Offset, 0):]
+		if q.Limit > 0 && q.Limit < len(runs) {
+			runs = runs[:q.Limit]
+		}
+		page.Runs = runs
+	} else {
+		page.Runs = []RunRecord{}
+	}
+	return page
+}
+
+// tagged returns the names of the entries with the tag, or nil for no tag.
+func (c *Cron) tagged(tag string) map[string]bool {
+	if tag == "" {
+		return nil
+	}
+	tagged := make(map[string]bool)
+	c.entriesMu.RLock()
+	for _, e := range c.entries {
+		if e.HasTag(tag) {
+			tagged[e.Name] = true
+		}
+	}
+	c.entriesMu.RUnlock()
+	return tagged
+}
+
+// picks reports whether the query picks the runs of the named entry, given
+// the entries with its tag.
+func (q HistoryQuery) picks(name string, tagged map[string]bool) bool {
+	return (len(q.Names) == 0 || contains(q.Names, name)) && (tagged == nil || tagged[name])
+}
+
+// matches reports whether r matches the query, but for its entry.
+func (q HistoryQuery) matches(r RunRecord) bool {
+	if len(q.Outcomes) > 0 && !containsOutcome(q.Outcomes, r.Outcome) {
+		return false
+	}
+	if !q.From.IsZero() && r.Start.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !r.Start.Before(q.To) {
+		return false
+	}
+	return r.End.Sub(r.Start) >= q.MinDuration
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsOutcome(outcomes []Outcome, o Outcome) bool {
+	for _, x := range outcomes {
+		if x == o {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseHistoryQuery parses a HistoryQuery from URL query parameters, as the
+// admin API takes them:
+//
+//	entry=a&entry=b&tag=nightly&outcome=panic&from=2024-03-01T00:00:00Z
+//	&to=2024-03-02T00:00:00Z&min_duration=1m&offset=20&limit=10
+//
+// The times are in RFC 3339, the duration as in time.ParseDuration.
+func ParseHistoryQuery(v url.Values) (HistoryQuery, error) {
+	q := HistoryQuery{Names: v["entry"], Tag: v.Get("tag")}
+	for _, o := range v["outcome"] {
+		q.Outcomes = append(q.Outcomes, Outcome(o))
+	}
+	var err error
+	parseTime := func(key string, t *time.Time) {
+		if s := v.Get(key); s != "" && err == nil {
+			if *t, err = time.Parse(time.RFC3339, s); err != nil {
+				err = fmt.Errorf("scheduler: bad %s: %w", key, err)
+			}
+		}
+	}
+	parseInt := func(key string, n *int) {
+		if s := v.Get(key); s != "" && err == nil {
+			if *n, err = strconv.Atoi(s); err != nil || *n < 0 {
+				err = fmt.Errorf("scheduler: bad %s %q", key, s)
+			}
+		}
+	}
+	parseTime("from", &q.From)
+	parseTime("to", &q.To)
+	parseInt("offset", &q.Offset)
+	parseInt("limit", &q.Limit)
+	if s := v.Get("min_duration"); s != "" && err == nil {
+		if q.MinDuration, err = time.ParseDuration(s); err != nil {
+			err = fmt.Errorf("scheduler: bad min_duration: %w", err)
+		}
+	}
+	return q, err
+}
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func queryHistoryCron() (*Cron, time.Time) {
+	cron := New()
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "etl", WithTags("nightly"))
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "report")
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i, r := range []struct {
+		name    string
+		outcome Outcome
+		took    time.Duration
+	}{
+		{"etl", OutcomeSuccess, time.Second},
+		{"report", OutcomeSuccess, time.Minute},
+		{"etl", OutcomePanic, 2 * time.Minute},
+		{"report", OutcomeStalled, time.Second},
+		{"etl", OutcomeSuccess, 3 * time.Minute},
+	} {
+		start := base.Add(time.Duration(i) * time.Hour)
+		cron.record(RunRecord{Name: r.name, Scheduled: start, Start: start, End: start.Add(r.took), Outcome: r.outcome})
+	}
+	return cron, base
+}
+
+func TestQueryHistory(t *testing.T) {
+	cron, base := queryHistoryCron()
+	for _, tc := range []struct {
+		name  string
+		q     HistoryQuery
+		total int
+		first time.Time
+	}{
+		{"all", HistoryQuery{}, 5, base.Add(4 * time.Hour)},
+		{"by name", HistoryQuery{Names: []string{"report"}}, 2, base.Add(3 * time.Hour)},
+		{"by tag", HistoryQuery{Tag: "nightly"}, 3, base.Add(4 * time.Hour)},
+		{"by outcome", HistoryQuery{Outcomes: []Outcome{OutcomePanic, OutcomeStalled}}, 2, base.Add(3 * time.Hour)},
+		{"by time", HistoryQuery{From: base.Add(time.Hour), To: base.Add(3 * time.Hour)}, 2, base.Add(2 * time.Hour)},
+		{"by duration", HistoryQuery{MinDuration: time.Minute}, 3, base.Add(4 * time.Hour)},
+		{"paged", HistoryQuery{Offset: 1, Limit: 2}, 5, base.Add(3 * time.Hour)},
+	} {
+		page := cron.QueryHistory(tc.q)
+		if page.Total != tc.total || len(page.Runs) == 0 || !page.Runs[0].Start.Equal(tc.first) {
+			t.Errorf("%s: expected %d runs from %s, got %d: %+v", tc.name, tc.total, tc.first, page.Total, page.Runs)
+		}
+		if tc.q.Limit > 0 && len(page.Runs) != tc.q.Limit {
+			t.Errorf("%s: expected a page of %d, got %d", tc.name, tc.q.Limit, len(page.Runs))
+		}
+	}
+	if page := cron.QueryHistory(HistoryQuery{Offset: 10}); page.Total != 5 || len(page.Runs) != 0 {
+		t.Errorf("expected an empty page past the end, got %+v", page)
+	}
+}
+
+func TestAdminHistoryQuery(t *testing.T) {
+	cron, _ := queryHistoryCron()
+	admin := NewAdmin(cron, TokenAuth(map[string]Role{"r": RoleReader}))
+
+	v := url.Values{"tag": {"nightly"}, "min_duration": {"1m"}, "limit": {"1"}}
+	w := adminRequest(t, admin, "GET", "/history?"+v.Encode(), "r")
+	var page HistoryPage
+	if err := json.NewDecoder(w.Body).Decode(&page); err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 2 || len(page.Runs) != 1 || page.Runs[0].Outcome != OutcomeSuccess {
+		t.Errorf("unexpected page %+v", page)
+	}
+
+	if w := adminRequest(t, admin, "GET", "/history?from=yesterday", "r"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected a bad request, got %d", w.Code)
+	}
+}
@@ -0,0 +1,25 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// queuePrefix starts the names of the entries of Enqueue.
+const queuePrefix = "queue/"
+
+// Enqueue runs job once, after delay, for deferred ad-hoc work such as
+// "send the reminder in 5 minutes". The task is a one-shot entry, named
+// "queue/" and a sequence number, so it goes through the WorkerPool, the
+// metrics, the history and the retries like any other entry, and the
+// options apply to it as to AddJob: WithRetries, say, retries a failed run.
+// It returns the name of the entry, to look it up or RemoveJob it while it
+// waits. Once the task has run, the entry is archived.
+func (c *Cron) Enqueue(job Job, delay time.Duration, opts ...EntryOption) (string, error) {
+	name := fmt.Sprintf("%s%d", queuePrefix, c.enqueued.Add(1))
+	at := c.clock.Now().Add(delay)
+	if err := c.AddJob(at, 0, job, name, append(opts, WithMaxRuns(1))...); err != nil {
+		return "", err
+	}
+	return name, nil
+}
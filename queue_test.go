@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEnqueue(t *testing.T) {
+	cron := New()
+	cron.Start()
+	defer cron.Stop()
+
+	var attempts int32
+	ran := make(chan time.Time, 1)
+	enqueued := time.Now()
+	name, err := cron.Enqueue(FuncJob(func() {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			panic("flaky")
+		}
+		ran <- time.Now()
+	}), 100*time.Millisecond, WithRetries(1, 10*time.Millisecond))
+	if err != nil || !strings.HasPrefix(name, queuePrefix) {
+		t.Fatalf("unexpected task %q: %v", name, err)
+	}
+	if other, _ := cron.Enqueue(FuncJob(func() {}), time.Hour); other == name {
+		t.Errorf("expected the tasks to be named apart, got %q twice", name)
+	}
+
+	select {
+	case at := <-ran:
+		if at.Sub(enqueued) < 100*time.Millisecond {
+			t.Errorf("expected the task to wait its delay, ran after %v", at.Sub(enqueued))
+		}
+	case <-time.After(ONE_SECOND):
+		t.Fatal("the task did not run")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := cron.Entry(name); ok {
+		t.Error("expected the task to be gone from the schedule once run")
+	}
+	if archived := cron.Archived(); len(archived) != 1 || archived[0].Name != name {
+		t.Errorf("expected the task archived, got %+v", archived)
+	}
+}
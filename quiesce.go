@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// How long Quiesce needs before the next trigger to hold dispatch, and how
+// often it checks for a moment like that.
+const (
+	quiesceWindow = time.Second
+	quiescePoll   = 10 * time.Millisecond
+)
+
+// Quiesce waits for a safe point: a moment when no run is going and no
+// trigger is due within the next second. It then holds dispatch until
+// release is called, so a consistent snapshot can be taken of the state the
+// jobs change. Meanwhile the triggers that come due wait, and run once
+// released, and RunNow fails with ErrNotDispatched. Quiesce gives up when
+// ctx is done, returning its error.
+func (c *Cron) Quiesce(ctx context.Context) (release func(), err error) {
+	for {
+		held := false
+		c.inLoop(func() {
+			if c.held || !c.idle(c.clock.Now().Add(quiesceWindow)) {
+				return
+			}
+			c.held, held = true, true
+		})
+		if held {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(quiescePoll):
+		}
+	}
+	c.log(SubsystemLifecycle).Info("scheduler quiesced")
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.inLoop(func() { c.held = false })
+			c.log(SubsystemLifecycle).Info("scheduler released")
+		})
+	}, nil
+}
+
+// idle reports whether no run is going and no trigger is due by until. It
+// is called from the run loop.
+func (c *Cron) idle(until time.Time) bool {
+	c.mu.Lock()
+	running := c.inFlight
+	c.mu.Unlock()
+	if running > 0 {
+		return false
+	}
+	return len(c.entries) == 0 || c.entries[0].NextTime.IsZero() || c.entries[0].NextTime.After(until)
+}
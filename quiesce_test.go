@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestQuiesce(t *testing.T) {
+	cron := New()
+	now := time.Now()
+	cron.AddFunc(now.Add(50*time.Millisecond), time.Hour, func() { time.Sleep(200 * time.Millisecond) }, "slow")
+	cron.AddFunc(now.Add(1600*time.Millisecond), time.Hour, func() {}, "later")
+	cron.Start()
+	defer cron.Stop()
+
+	time.Sleep(100 * time.Millisecond)
+	release, err := cron.Quiesce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(cron.History("slow")); n != 1 {
+		t.Errorf("expected the running job to be over, got %d runs", n)
+	}
+	if _, err := cron.RunNow("slow"); err != ErrNotDispatched {
+		t.Errorf("expected RunNow to be refused while quiesced, got %v", err)
+	}
+
+	time.Sleep(time.Until(now.Add(1800 * time.Millisecond)))
+	if n := len(cron.History("later")); n != 0 {
+		t.Fatal("expected the trigger held while quiesced")
+	}
+	release()
+	release()
+	waitForHistory(t, cron, "later", 1)
+}
+
+func TestQuiesceGivesUp(t *testing.T) {
+	cron := New()
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() { time.Sleep(500 * time.Millisecond) }, "slow")
+	cron.Start()
+	defer cron.Stop()
+
+	time.Sleep(100 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := cron.Quiesce(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected the deadline, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package scheduler
+
+// Reason tells, in a machine-readable way, why a trigger was skipped,
+// deferred or coalesced. It is carried by RunRecord.Reason, Event.Reason and
+// the "reason" tag of the DogStatsD run metrics.
+type Reason string
+
+const (
+	// The run waited for the previous run of the entry to be over, or was
+	// dropped because too many were waiting already. See WithOverlap and
+	// WithMaxPending.
+	ReasonOverlap Reason = "overlap"
+
+	// A maintenance window was open, see AddMaintenanceWindow.
+	ReasonBlackout Reason = "blackout"
+
+	// The Interceptor vetoed the run.
+	ReasonPredicate Reason = "predicate"
+
+	// The run was held back by storm protection, see WithStormProtection.
+	ReasonRateLimit Reason = "rate_limit"
+
+	// The entry is disabled, or its circuit is open, see WithCircuitBreaker.
+	ReasonPaused Reason = "paused"
+
+	// The run would have gone over a budget.
+	ReasonQuota Reason = "quota"
+
+	// A recent enough result was reused, see WithResultCache.
+	ReasonCached Reason = "cached"
+
+	// A dependency of the entry was unhealthy, see WithDependsOn.
+	ReasonUnhealthy Reason = "unhealthy"
+
+	// The instance lacks labels the entry is pinned to, see WithAffinity.
+	ReasonAffinity Reason = "affinity"
+
+	// The run, started by RunNow, took the place of a scheduled run, see
+	// WithRunNowDedup.
+	ReasonCoalesced Reason = "coalesced"
+)
+
+// reasonOf returns why a run that ended with outcome was skipped, if it was.
+func reasonOf(outcome Outcome) Reason {
+	switch outcome {
+	case OutcomeVetoed:
+		return ReasonPredicate
+	case OutcomeOverBudget:
+		return ReasonQuota
+	case OutcomeCached:
+		return ReasonCached
+	case OutcomePaused:
+		return ReasonBlackout
+	case OutcomeUnhealthy:
+		return ReasonUnhealthy
+	}
+	return ""
+}
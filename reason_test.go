@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReasonOfSkippedRun(t *testing.T) {
+	events := make(chan Event, 100)
+	cron := New(WithEventHandler(func(e Event) { events <- e }), WithInterceptor(func(ctx context.Context, e *Entry, _ time.Time) (context.Context, Verdict) {
+		return ctx, Verdict{Veto: true}
+	}))
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() {}, "job")
+	cron.Start()
+	defer cron.Stop()
+
+	if r := waitForHistory(t, cron, "job", 1)[0]; r.Reason != ReasonPredicate {
+		t.Errorf("expected the veto recorded as %s, got %q", ReasonPredicate, r.Reason)
+	}
+	if e := waitForEvent(t, events, EventRun); e.Reason != ReasonPredicate {
+		t.Errorf("expected the event to carry %s, got %q", ReasonPredicate, e.Reason)
+	}
+}
+
+func TestReasonOfIgnoredTrigger(t *testing.T) {
+	events := make(chan Event, 100)
+	cron := New(WithEventHandler(func(e Event) { events <- e }), WithPanicPolicy(PanicDisable))
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() { panic("boom") }, "job")
+	cron.Start()
+	defer cron.Stop()
+
+	cron.RunNow("job")
+	if _, err := cron.RunNow("job"); err != ErrNotDispatched {
+		t.Fatalf("expected the disabled entry not to run, got %v", err)
+	}
+	if e := waitForEvent(t, events, EventTriggerSkipped); e.Name != "job" || e.Reason != ReasonPaused {
+		t.Errorf("expected the trigger skipped as %s, got %+v", ReasonPaused, e)
+	}
+}
+
+func TestReasonOfQueuedRun(t *testing.T) {
+	cron := New()
+	release := make(chan struct{})
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() { <-release }, "job", WithOverlap(OverlapSerialize))
+	cron.Start()
+	defer cron.Stop()
+
+	go cron.RunNow("job")
+	for len(cron.Entries()) == 0 || cron.Entries()[0].Runs < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	go cron.RunNow("job")
+	for cron.Entries()[0].Pending < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	runs := waitForHistory(t, cron, "job", 2)
+	if runs[0].Reason != "" || runs[1].Reason != ReasonOverlap {
+		t.Errorf("expected the second run deferred as %s, got %q and %q", ReasonOverlap, runs[0].Reason, runs[1].Reason)
+	}
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import "time"
+
+// WithDisplayLocation sets the location the times of the entry are shown
+// in, such as in the admin API, see Entry.DisplayTime. It has no bearing on
+// when the entry runs.
+func WithDisplayLocation(loc *time.Location) EntryOption {
+	return func(e *Entry) {
+		e.DisplayLocation = loc
+	}
+}
+
+// DisplayTime returns t in the display location of the entry, or else in
+// the location of the scheduler.
+func (e *Entry) DisplayTime(t time.Time) time.Time {
+	switch {
+	case e.DisplayLocation != nil:
+		return t.In(e.DisplayLocation)
+	case e.location != nil:
+		return t.In(e.location)
+	}
+	return t
+}
+
+// localTime reports whether the schedule is given in local time, such as
+// Daily, whose times depend on the location it is asked in.
+func localTime(s Schedule) bool {
+	switch s := s.(type) {
+	case wallClock:
+		return true
+	case merged:
+		for _, s := range s {
+			if localTime(s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// warnAmbiguous logs a warning about an entry added in multi-region mode
+// whose schedule depends on a time of day, as instances in different
+// regions would not agree on it unless they all go by UTC.
+func (c *Cron) warnAmbiguous(e *Entry) {
+	if !c.multiRegion {
+		return
+	}
+	var what string
+	switch {
+	case localTime(e.Schedule):
+		what = "schedule"
+	case e.Flex > 0:
+		what = "preferred window"
+	default:
+		return
+	}
+	keysAndValues := []interface{}{"entry", e.Name, "what", what}
+	if e.DisplayLocation != nil && e.DisplayLocation != time.UTC {
+		keysAndValues = append(keysAndValues, "display_location", e.DisplayLocation.String())
+	}
+	c.log(SubsystemLifecycle).Info("local time is ambiguous across regions, going by UTC", keysAndValues...)
+}
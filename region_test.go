@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMultiRegionDisplayLocation(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+	cron := New(WithMultiRegion())
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	cron.AddFunc(start, time.Hour, func() {}, "report", WithDisplayLocation(tokyo))
+	cron.AddFunc(start, time.Hour, func() {}, "plain")
+	cron.Start()
+	defer cron.Stop()
+
+	for _, e := range cron.Entries() {
+		if e.NextTime.Location() != time.UTC {
+			t.Errorf("expected %s to be scheduled in UTC, got %s", e.Name, e.NextTime.Location())
+		}
+		shown := e.DisplayTime(e.NextTime)
+		want := "2030-01-01 00:00:00 +0000 UTC"
+		if e.Name == "report" {
+			want = "2030-01-01 09:00:00 +0900 JST"
+		}
+		if shown.String() != want || !shown.Equal(e.NextTime) {
+			t.Errorf("expected %s to show %s, got %s", e.Name, want, shown)
+		}
+	}
+}
+
+func TestMultiRegionWarnsAboutLocalTime(t *testing.T) {
+	var out syncBuffer
+	cron := New(WithMultiRegion(), WithLogger(PrintfLogger(log.New(&out, "", 0))))
+	cron.AddFuncOn(Daily(2, 0, 0), func() {}, "backup")
+	cron.AddFunc(time.Now(), time.Hour, func() {}, "hourly")
+	cron.AddFunc(time.Now(), 24*time.Hour, func() {}, "compact", WithPreferredWindow(time.Hour, 5*time.Hour, 10*time.Hour))
+
+	got := out.String()
+	for _, want := range []string{"entry=backup, what=schedule", "entry=compact, what=preferred window"} {
+		if !strings.Contains(got, "local time is ambiguous across regions, going by UTC, "+want) {
+			t.Errorf("expected a warning with %q, got\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "hourly") {
+		t.Errorf("expected no warning about an interval, got\n%s", got)
+	}
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrUnknownJobKind is returned for a job kind no factory is registered for.
+var ErrUnknownJobKind = errors.New("scheduler: unknown job kind")
+
+// JobFactory makes the Job of a kind from its parameters. A ProgressJob is
+// made into a Job with AsJob.
+type JobFactory func(params map[string]interface{}) Job
+
+// jobRegistry maps job kinds to their factories.
+type jobRegistry struct {
+	mu        sync.RWMutex
+	factories map[string]JobFactory
+}
+
+func (r *jobRegistry) register(kind string, f JobFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f == nil {
+		panic("scheduler: RegisterJobFactory factory is nil")
+	}
+	if _, dup := r.factories[kind]; dup {
+		panic("scheduler: RegisterJobFactory called twice for kind " + kind)
+	}
+	if r.factories == nil {
+		r.factories = make(map[string]JobFactory)
+	}
+	r.factories[kind] = f
+}
+
+func (r *jobRegistry) lookup(kind string) (JobFactory, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.factories[kind]
+	return f, ok
+}
+
+var jobFactories jobRegistry
+
+// RegisterJobFactory makes the jobs of the given kind from their parameters
+// with f, for every Cron. Entries added with AddRegisteredJob only hold the
+// kind and the parameters of their job, so they can be persisted and later
+// rehydrated. It panics if a factory is already registered for the kind.
+func RegisterJobFactory(kind string, f JobFactory) {
+	jobFactories.register(kind, f)
+}
+
+// RegisterJobFactory is RegisterJobFactory for this Cron only. Its factories
+// come before the global ones.
+func (c *Cron) RegisterJobFactory(kind string, f JobFactory) {
+	c.jobFactories.register(kind, f)
+}
+
+// NewJob makes the Job of the given kind from its parameters.
+func (c *Cron) NewJob(kind string, params map[string]interface{}) (Job, error) {
+	f, ok := c.jobFactories.lookup(kind)
+	if !ok {
+		f, ok = jobFactories.lookup(kind)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownJobKind, kind)
+	}
+	return f(params), nil
+}
+
+// AddRegisteredJob adds the Job of the given kind, made from its parameters,
+// to the Cron to be run on the given schedule. The entry keeps the kind and
+// the parameters, see Entry.Kind.
+func (c *Cron) AddRegisteredJob(startTime time.Time, Interval time.Duration, kind string, params map[string]interface{}, name string, opts ...EntryOption) error {
+	job, err := c.NewJob(kind, params)
+	if err != nil {
+		return err
+	}
+	return c.Schedule(startTime, Interval, job, name, append([]EntryOption{registered(kind, params)}, opts...)...)
+}
+
+// registered records the kind and parameters the job of the entry was made
+// from.
+func registered(kind string, params map[string]interface{}) EntryOption {
+	cp := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		cp[k] = v
+	}
+	return func(e *Entry) {
+		e.Kind = kind
+		e.Params = cp
+	}
+}
+
+// AsJob turns a ProgressJob into a Job, for JobFactory. The job still gets
+// its Progress when the scheduler runs it.
+func AsJob(j ProgressJob) Job {
+	return progressJob{j}
+}
@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRegisteredJob(t *testing.T) {
+	RegisterJobFactory("test-echo", func(params map[string]interface{}) Job {
+		return AsJob(ProgressFuncJob(func(p *Progress) {
+			fmt.Fprint(p.Output(), params["text"])
+		}))
+	})
+	cron := New()
+	err := cron.AddRegisteredJob(time.Now().Add(50*time.Millisecond), time.Hour, "test-echo", map[string]interface{}{"text": "hello"}, "echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	if out := waitForHistory(t, cron, "echo", 1)[0].Output; out != "hello" {
+		t.Errorf("expected the job made from its params, got %q", out)
+	}
+	e := cron.Entries()[0]
+	if e.Kind != "test-echo" || e.Params["text"] != "hello" {
+		t.Errorf("expected the entry to keep its kind and params, got %q %v", e.Kind, e.Params)
+	}
+}
+
+func TestRegistryPerCron(t *testing.T) {
+	cron := New()
+	cron.RegisterJobFactory("local", func(map[string]interface{}) Job { return FuncJob(func() {}) })
+	if _, err := cron.NewJob("local", nil); err != nil {
+		t.Error(err)
+	}
+	if _, err := New().NewJob("local", nil); !errors.Is(err, ErrUnknownJobKind) {
+		t.Errorf("expected another Cron not to know the kind, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected registering a kind twice to panic")
+		}
+	}()
+	cron.RegisterJobFactory("local", func(map[string]interface{}) Job { return nil })
+}
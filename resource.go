@@ -0,0 +1,33 @@
+package scheduler
+
+import "sort"
+
+// WithResources declares the resources the runs of the entry use. A run
+// waits until every resource with a limit (see WithResourceLimit) has room
+// for it, independently of the WorkerPool. Resources without a limit are
+// not constrained.
+func WithResources(resources ...string) EntryOption {
+	return func(e *Entry) {
+		e.Resources = append(e.Resources, resources...)
+	}
+}
+
+// resourcesOf returns the limited resources the entry uses, always in the
+// same order so that runs don't deadlock acquiring them.
+func (c *Cron) resourcesOf(e *Entry) []*WorkerPool {
+	if len(c.resources) == 0 || len(e.Resources) == 0 {
+		return nil
+	}
+	names := append([]string(nil), e.Resources...)
+	sort.Strings(names)
+	var pools []*WorkerPool
+	for i, name := range names {
+		if i > 0 && name == names[i-1] {
+			continue
+		}
+		if pool, ok := c.resources[name]; ok {
+			pools = append(pools, pool)
+		}
+	}
+	return pools
+}
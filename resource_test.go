@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResourceLimit(t *testing.T) {
+	cron := New(WithResourceLimit("db", 2))
+	var running, maxRunning, free int32
+	job := func() {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		time.Sleep(150 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+	}
+	start := time.Now().Add(50 * time.Millisecond)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		cron.AddFunc(start, time.Hour, job, name, WithResources("db", "db"))
+	}
+	cron.AddFunc(start, time.Hour, func() { atomic.AddInt32(&free, 1) }, "unconstrained", WithResources("cache"))
+	cron.Start()
+	defer cron.Stop()
+
+	for _, name := range []string{"a", "b", "c", "d"} {
+		waitForHistory(t, cron, name, 1)
+	}
+	if max := atomic.LoadInt32(&maxRunning); max != 2 {
+		t.Errorf("expected at most 2 runs using db at once, got %d", max)
+	}
+	if atomic.LoadInt32(&free) != 1 {
+		t.Error("expected the entry without a limited resource to run")
+	}
+}
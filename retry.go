@@ -0,0 +1,79 @@
+package scheduler
+
+import "time"
+
+// WithRetries retries a failed run of the entry up to n times, backoff after
+// it failed, doubling the backoff for each further attempt. Each attempt is
+// recorded in the history on its own, with RunRecord.Attempt, Error and
+// Backoff telling them apart. RunNow returns the record of the last one.
+func WithRetries(n int, backoff time.Duration) EntryOption {
+	return func(e *Entry) {
+		e.Retries = n
+		e.RetryBackoff = backoff
+	}
+}
+
+// retries reports whether the run of t, which ended with outcome, is to be
+// retried.
+func (t trigger) retries(outcome Outcome) bool {
+	return outcome.failed() && t.attemptNumber() <= t.view.Retries
+}
+
+// retry returns the next attempt at the run of t, and how long to wait
+// before it starts.
+func (t trigger) retry() (trigger, time.Duration) {
+	backoff := t.view.RetryBackoff << (t.attemptNumber() - 1)
+	t.attempt = t.attemptNumber() + 1
+	t.backoff = backoff
+	return t, backoff
+}
+
+// WithRetryBudget caps the retries of the entry at n per window, so that
+// retries don't pile up load on a dependency that is down: once the budget
+// is spent a failed run is not retried until the next window. Windows are
+// aligned to the zero time, like those of a Budget. The retries left are in
+// EntryStats.RetryBudget.
+func WithRetryBudget(n int, window time.Duration) EntryOption {
+	return func(e *Entry) {
+		e.RetryBudget = n
+		e.RetryWindow = window
+	}
+}
+
+// retrying reports whether the run of t of e, which ended with outcome, is
+// to be retried, spending the retry budget of e if so.
+func (c *Cron) retrying(e *Entry, t trigger, outcome Outcome) bool {
+	if !t.retries(outcome) {
+		return false
+	}
+	if t.view.RetryWindow <= 0 {
+		c.mu.Lock()
+		e.stats.Retries++
+		c.mu.Unlock()
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	left := e.retriesLeft(time.Now())
+	if left <= 0 {
+		c.debug(SubsystemDispatch, "retry budget spent, not retrying", "entry", e.Name, "scheduled", t.scheduled, "attempt", t.attemptNumber())
+		e.stats.RetriesDenied++
+		return false
+	}
+	e.retriesSpent++
+	e.stats.Retries++
+	return true
+}
+
+// retriesLeft returns how many retries the budget of e has left at now, or
+// -1 without a budget. The caller must hold c.mu.
+func (e *Entry) retriesLeft(now time.Time) int {
+	if e.RetryWindow <= 0 {
+		return -1
+	}
+	if start := now.Truncate(e.RetryWindow); !start.Equal(e.retryWindowStart) {
+		e.retryWindowStart = start
+		e.retriesSpent = 0
+	}
+	return max(e.RetryBudget-e.retriesSpent, 0)
+}
@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetriesRecordEachAttempt(t *testing.T) {
+	var calls atomic.Int32
+	cron := New()
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {
+		if calls.Add(1) < 3 {
+			panic("boom")
+		}
+	}, "flaky", WithRetries(3, 20*time.Millisecond))
+	cron.Start()
+	defer cron.Stop()
+
+	r, err := cron.RunNow("flaky")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Outcome != OutcomeSuccess || r.Attempt != 3 {
+		t.Errorf("expected RunNow to return the third, successful attempt, got %+v", r)
+	}
+	history := cron.History("flaky")
+	if len(history) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(history))
+	}
+	for i, want := range []struct {
+		outcome Outcome
+		err     string
+		backoff time.Duration
+	}{
+		{OutcomePanic, "boom", 0},
+		{OutcomePanic, "boom", 20 * time.Millisecond},
+		{OutcomeSuccess, "", 40 * time.Millisecond},
+	} {
+		got := history[i]
+		if got.Attempt != i+1 || got.Outcome != want.outcome || got.Error != want.err || got.Backoff != want.backoff {
+			t.Errorf("attempt %d: expected %v %q after %v, got %+v", i+1, want.outcome, want.err, want.backoff, got)
+		}
+	}
+}
+
+func TestRetriesGiveUp(t *testing.T) {
+	cron := New()
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() { panic("boom") }, "broken", WithRetries(1, time.Millisecond))
+	cron.Start()
+	defer cron.Stop()
+
+	if r, _ := cron.RunNow("broken"); r.Outcome != OutcomePanic || r.Attempt != 2 {
+		t.Errorf("expected the last attempt to fail, got %+v", r)
+	}
+	if n := len(cron.History("broken")); n != 2 {
+		t.Errorf("expected 2 attempts, got %d", n)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	cron := New()
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() { panic("down") }, "flaky",
+		WithRetries(2, time.Millisecond), WithRetryBudget(3, time.Hour))
+	cron.Start()
+	defer cron.Stop()
+
+	// The first run retries twice, the second only once before the budget
+	// is spent, and the third not at all.
+	for _, want := range []int{3, 2, 1} {
+		r, err := cron.RunNow("flaky")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Attempt != want {
+			t.Errorf("expected %d attempts, got %d", want, r.Attempt)
+		}
+	}
+	stats, _ := cron.Stats("flaky")
+	if stats.Retries != 3 || stats.RetriesDenied != 2 || stats.RetryBudget != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "unlimited", WithRetries(2, time.Millisecond))
+	if stats, _ := cron.Stats("unlimited"); stats.RetryBudget != -1 {
+		t.Errorf("expected -1 for an entry without a retry budget, got %d", stats.RetryBudget)
+	}
+}
+
+func TestRetryGivenUpOnStop(t *testing.T) {
+	cron := New()
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() { panic("boom") }, "broken", WithRetries(3, time.Hour))
+	cron.Start()
+
+	done := make(chan RunRecord)
+	go func() {
+		r, _ := cron.RunNow("broken")
+		done <- r
+	}()
+	waitForHistory(t, cron, "broken", 1)
+	cron.Stop()
+	select {
+	case r := <-done:
+		if r.Outcome != OutcomePanic || r.Attempt != 1 {
+			t.Errorf("expected RunNow to return the first attempt, got %+v", r)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the retry to be given up once the scheduler stopped")
+	}
+	if n := len(cron.History("broken")); n != 1 {
+		t.Errorf("expected a single attempt, got %d", n)
+	}
+}
@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// RunInfo describes the run a job is in. Pipelines should work off its
+// Scheduled time, the logical time of the run, rather than time.Now().
+type RunInfo struct {
+	Name    string
+	Version int
+
+	// When the run was due, and when it actually started.
+	Scheduled time.Time
+	Start     time.Time
+
+	// Which attempt at the run this is, starting at 1.
+	Attempt int
+
+	// Numbers the triggers of the entry, from 1 up, the attempts at a run
+	// sharing one, so the systems downstream can tell a trigger that was
+	// lost, a gap, from one that was run twice. With a JobStore the
+	// numbering goes on across restarts, updates and removals, the number
+	// being stored before the run starts: a run lost to a crash leaves a
+	// gap. If storing it fails, that is logged and the run goes ahead, so
+	// after a restart the numbers since the last one stored are handed out
+	// again. Instances sharing a store should not run the same entry.
+	Sequence uint64
+
+	// Set if the run was enqueued by Backfill or started by RunNow.
+	Backfill bool
+	Manual   bool
+}
+
+// runInfoKey is the context key of the RunInfo.
+type runInfoKey struct{}
+
+// Info returns the RunInfo of the current run. Outside of the scheduler it
+// is the zero RunInfo.
+func (p *Progress) Info() RunInfo {
+	if p == nil {
+		return RunInfo{}
+	}
+	return p.info
+}
+
+// RunInfoFromContext returns the RunInfo of the run whose context ctx is or
+// derives from, see Progress.Context.
+func RunInfoFromContext(ctx context.Context) (RunInfo, bool) {
+	info, ok := ctx.Value(runInfoKey{}).(RunInfo)
+	return info, ok
+}
+
+// runInfo returns the RunInfo of a run of t starting at start.
+func (t trigger) runInfo(start time.Time) RunInfo {
+	return RunInfo{
+		Name:      t.view.Name,
+		Version:   t.view.Version,
+		Scheduled: t.scheduled,
+		Start:     start,
+		Attempt:   t.attemptNumber(),
+		Sequence:  t.sequence,
+		Backfill:  t.backfill,
+		Manual:    t.manual,
+	}
+}
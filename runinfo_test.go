@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunInfo(t *testing.T) {
+	cron := New()
+	infos := make(chan RunInfo, 2)
+	fromCtx := make(chan RunInfo, 2)
+	start := time.Now().Add(50 * time.Millisecond)
+	cron.AddProgressFunc(start, time.Hour, func(p *Progress) {
+		infos <- p.Info()
+		info, _ := RunInfoFromContext(p.Context())
+		fromCtx <- info
+	}, "pipeline")
+	cron.Start()
+	defer cron.Stop()
+
+	info := <-infos
+	if info.Name != "pipeline" || !info.Scheduled.Equal(start) || info.Start.Before(start) || info.Attempt != 1 || info.Backfill || info.Manual {
+		t.Errorf("unexpected run info %+v", info)
+	}
+	if ctxInfo := <-fromCtx; ctxInfo != info {
+		t.Errorf("expected the context to carry the run info, got %+v", ctxInfo)
+	}
+
+	cron.RunNow("pipeline")
+	if info := <-infos; !info.Manual {
+		t.Errorf("expected a manual run, got %+v", info)
+	}
+}
+
+func TestRunInfoOutsideScheduler(t *testing.T) {
+	if _, ok := RunInfoFromContext((*Progress)(nil).Context()); ok {
+		t.Error("expected no run info outside of a run")
+	}
+}
@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"sort"
+	"time"
+)
+
+// RunningInfo is a run in flight, see Running.
+type RunningInfo struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+
+	// When the run was due, when it started and how long it has been going.
+	Scheduled time.Time     `json:"scheduled"`
+	Start     time.Time     `json:"start"`
+	Elapsed   time.Duration `json:"elapsed"`
+
+	// Which attempt at the run this is, starting at 1, and the sequence
+	// number of its trigger, see RunInfo.
+	Attempt  int    `json:"attempt"`
+	Sequence uint64 `json:"sequence,omitempty"`
+
+	// Set if the run was started by RunNow.
+	Manual bool `json:"manual,omitempty"`
+
+	// The last progress the run reported, see Progress.Report.
+	Progress ProgressReport `json:"progress"`
+}
+
+// Running returns the runs in flight, those of the same entry overlapping
+// included, oldest first, for a panel of what is running right now. Runs
+// waiting for a worker or queued behind the previous one aren't running
+// yet, see Entry.Pending.
+func (c *Cron) Running() []RunningInfo {
+	now := time.Now()
+	running := []RunningInfo{}
+	c.mu.Lock()
+	for p := range c.going {
+		running = append(running, RunningInfo{
+			Name:      p.info.Name,
+			Version:   p.info.Version,
+			Scheduled: p.info.Scheduled,
+			Start:     p.info.Start,
+			Elapsed:   now.Sub(p.info.Start),
+			Attempt:   p.info.Attempt,
+			Sequence:  p.info.Sequence,
+			Manual:    p.info.Manual,
+			Progress:  p.Last(),
+		})
+	}
+	c.mu.Unlock()
+	sort.Slice(running, func(i, j int) bool {
+		if !running[i].Start.Equal(running[j].Start) {
+			return running[i].Start.Before(running[j].Start)
+		}
+		return running[i].Name < running[j].Name
+	})
+	return running
+}
@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestRunning(t *testing.T) {
+	cron := New()
+	started, release := make(chan struct{}, 2), make(chan struct{})
+	cron.AddProgressFunc(time.Now().Add(time.Hour), time.Hour, func(p *Progress) {
+		p.Report(25, "copying")
+		started <- struct{}{}
+		<-release
+	}, "copy")
+	cron.Start()
+	defer cron.Stop()
+
+	if got := cron.Running(); len(got) != 0 {
+		t.Fatalf("expected nothing running, got %v", got)
+	}
+	// Overlapping runs of the entry each show.
+	for i := 0; i < 2; i++ {
+		go cron.RunNow("copy")
+		<-started
+	}
+	running := cron.Running()
+	if len(running) != 2 {
+		t.Fatalf("expected both runs, got %v", running)
+	}
+	for _, r := range running {
+		if r.Name != "copy" || r.Attempt != 1 || !r.Manual || r.Elapsed <= 0 || r.Progress.Message != "copying" {
+			t.Errorf("unexpected run %+v", r)
+		}
+	}
+	if running[0].Start.After(running[1].Start) {
+		t.Error("expected the oldest run first")
+	}
+
+	admin := NewAdmin(cron, TokenAuth(map[string]Role{"r": RoleReader}))
+	w := adminRequest(t, admin, "GET", "/running", "r")
+	var listed []RunningInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &listed); err != nil || len(listed) != 2 {
+		t.Errorf("expected the runs from the admin API, got %d %s", w.Code, w.Body)
+	}
+
+	close(release)
+	deadline := time.Now().Add(ONE_SECOND)
+	for len(cron.Running()) != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := cron.Running(); len(got) != 0 {
+		t.Errorf("expected the runs gone once over, got %v", got)
+	}
+}
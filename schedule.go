@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// merged is a Schedule firing at the occurrences of all of its schedules.
+type merged []Schedule
+
+// Merge returns a Schedule firing at the occurrences of all the given ones,
+// so that one entry, with one name and one history, can run on several:
+//
+//	c.AddFuncOn(Merge(Daily(1, 0, 0), Weekly(time.Monday, 9, 0)), sync, "sync")
+//
+// Occurrences the schedules share fire once.
+func Merge(schedules ...Schedule) Schedule {
+	return merged(schedules)
+}
+
+func (m merged) Next(t time.Time) time.Time {
+	var next time.Time
+	for _, s := range m {
+		n := s.Next(t)
+		if n.IsZero() {
+			continue
+		}
+		if next.IsZero() || n.Before(next) {
+			next = n
+		}
+	}
+	return next
+}
+
+// intervalSchedule is the Schedule of an interval entry, firing every
+// interval from start, see Entry.schedule.
+type intervalSchedule struct {
+	start    time.Time
+	interval time.Duration
+}
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	if t.Before(s.start) {
+		return s.start
+	}
+	return s.start.Add((t.Sub(s.start)/s.interval + 1) * s.interval)
+}
+
+// everyInterval is a Schedule firing at a fixed interval.
+type everyInterval time.Duration
+
+// Every returns a Schedule firing every d, counted from when the entry is
+// scheduled and then from each occurrence, as "@every" does for ParseCron.
+// Unlike an Interval, it has no start time to line up with.
+func Every(d time.Duration) Schedule {
+	return everyInterval(d)
+}
+
+func (d everyInterval) Next(t time.Time) time.Time {
+	if d <= 0 {
+		return time.Time{}
+	}
+	return t.Add(time.Duration(d))
+}
+
+func (d everyInterval) String() string {
+	return fmt.Sprintf("@every %s", time.Duration(d))
+}
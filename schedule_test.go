@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	s := Merge(Daily(1, 0, 0), Daily(13, 0, 0), Weekly(time.Wednesday, 1, 0))
+	from := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC) // a Tuesday
+	var got []time.Time
+	for t := s.Next(from); len(got) < 4; t = s.Next(t) {
+		got = append(got, t)
+	}
+	want := []time.Time{
+		time.Date(2030, 1, 1, 13, 0, 0, 0, time.UTC),
+		// Daily and weekly at 01:00 on Wednesday, once.
+		time.Date(2030, 1, 2, 1, 0, 0, 0, time.UTC),
+		time.Date(2030, 1, 2, 13, 0, 0, 0, time.UTC),
+		time.Date(2030, 1, 3, 1, 0, 0, 0, time.UTC),
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("occurrence %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+}
+
+// One entry on merged schedules backfills their merged occurrences.
+func TestMergeEntryOccurrences(t *testing.T) {
+	e := &Entry{Schedule: Merge(Daily(1, 0, 0), Daily(13, 0, 0)), location: time.UTC}
+	from := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	if n := len(e.occurrences(from, from.AddDate(0, 0, 3))); n != 6 {
+		t.Errorf("expected 6 occurrences over 3 days, got %d", n)
+	}
+}
+
+// ticks is a custom Schedule firing on the multiples of d since the epoch.
+type ticks time.Duration
+
+func (d ticks) Next(t time.Time) time.Time {
+	return t.Truncate(time.Duration(d)).Add(time.Duration(d))
+}
+
+func TestScheduleJob(t *testing.T) {
+	cron := New()
+	done := make(chan time.Time, 10)
+	cron.ScheduleJob(ticks(100*time.Millisecond), FuncJob(func() { done <- time.Now() }), "ticks")
+	cron.Start()
+	defer cron.Stop()
+	for i := 0; i < 3; i++ {
+		select {
+		case at := <-done:
+			if off := at.Sub(at.Truncate(100 * time.Millisecond)); off > 50*time.Millisecond {
+				t.Errorf("run %d: expected it on a tick, got %v past one", i, off)
+			}
+		case <-time.After(ONE_SECOND):
+			t.Fatalf("expected 3 runs, got %d", i)
+		}
+	}
+}
+
+// Interval entries run on a Schedule of their own, which is due at their
+// start time and then on the grid of their interval.
+func TestIntervalSchedule(t *testing.T) {
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := intervalSchedule{start: start, interval: time.Hour}
+	for _, tc := range []struct{ from, want time.Time }{
+		{start.Add(-time.Minute), start},
+		{start, start.Add(time.Hour)},
+		{start.Add(90 * time.Minute), start.Add(2 * time.Hour)},
+	} {
+		if got := s.Next(tc.from); !got.Equal(tc.want) {
+			t.Errorf("Next(%v): expected %v, got %v", tc.from, tc.want, got)
+		}
+	}
+}
@@ -3,7 +3,10 @@
 package scheduler
 
 import (
-	"sort"
+	"container/heap"
+	"context"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -13,12 +16,163 @@ type entries []*Entry
 // specified by the schedule. It may be started, stopped, and the entries may
 // be inspected while running.
 type Cron struct {
-	entries  entries
-	stop     chan struct{}
-	add      chan *Entry
-	remove   chan string
-	snapshot chan entries
-	running  bool
+	entries entries
+	stop    chan struct{}
+	do      chan func()
+	running bool
+
+	// lifecycle guards running: Start and Stop hold it, and inLoop holds it
+	// for reading, so a change is made by the run loop or without it, never
+	// while it starts or stops.
+	lifecycle sync.RWMutex
+
+	// mu guards the run state of the entries and the run history, which are
+	// written by the goroutines running the jobs. live has the latest run
+	// of each entry in flight, going all of them.
+	mu      sync.Mutex
+	history map[string][]RunRecord
+	live    map[string]*Progress
+	going   map[*Progress]bool
+
+	// The context the runs derive theirs from, until Shutdown cancels it.
+	runCtx    context.Context
+	runCancel context.CancelFunc
+
+	// Closed by Stop, so the runs waiting to retry or held by the fence stop
+	// waiting, see sleep. Guarded by mu.
+	halt chan struct{}
+
+	panicPolicy   PanicPolicy
+	panicReporter PanicReporter
+	errorHandler  func(name string, err interface{})
+	interceptor   Interceptor
+	pool          *WorkerPool
+	metrics       Metrics
+	budgets       []*budgetState
+	resources     map[string]*WorkerPool
+	store         JobStore
+	logger        Logger
+
+	// The clock of the coordination backend, and how far the runs may be
+	// ahead of it, see WithTriggerFence.
+	clusterNow     func(ctx context.Context) (time.Time, error)
+	fenceTolerance time.Duration
+
+	// Runs in flight, and the state taken over from a drained instance.
+	runs    sync.WaitGroup
+	handoff map[string]handoffState
+
+	backfillRate time.Duration
+	location     *time.Location
+
+	// The clock of the run loop, and its trace if any, see WithClock and
+	// WithTrace.
+	clock  Clock
+	tracer *tracer
+
+	// Set by WithMultiRegion.
+	multiRegion bool
+
+	// The labels of the instance, see WithInstanceLabels.
+	instanceLabels map[string]bool
+
+	// The label keys let through to the metrics, see WithMetricLabels.
+	metricLabels map[string]bool
+
+	// Retired entries, see Archived.
+	archive    []*Entry
+	archiveTTL time.Duration
+
+	// Previous definitions of the entries, see Rollback.
+	definitions map[string][]*Entry
+
+	onEvent    func(Event)
+	eventLog   *eventLog
+	changeFeed *changeFeed
+	events     chan Event
+	eventsMu   sync.Mutex
+
+	faults  *faultInjector
+	secrets SecretProvider
+
+	// Trigger storm protection, see WithStormProtection.
+	stormThreshold int
+	stormSmear     time.Duration
+
+	// Caps the runs going at once after Start, see WithWarmup.
+	warmup *warmup
+
+	// Wrap the job of every run, see WithChain.
+	wrappers Chain
+
+	// Carries out the runs, see WithExecutor.
+	executor Executor
+
+	// The longest the run loop sleeps, and the tasks of its housekeeping
+	// ticks, see WithHorizon.
+	horizon      time.Duration
+	housekeeping []func(time.Time)
+
+	// The time of the last tick, by the clock of the scheduler, owned by the
+	// run loop, and whether its tasks are still going.
+	lastTick      time.Time
+	housekeepBusy atomic.Bool
+
+	// Numbers the tasks of Enqueue.
+	enqueued atomic.Uint64
+
+	// The last sequence number stored for each entry, see storeSequence.
+	seqMu      sync.Mutex
+	storedSeqs map[string]uint64
+
+	// The idempotency keys of Trigger, by entry and key, and when they were
+	// seen. Guarded by mu.
+	triggerKeys map[string]time.Time
+
+	// See WithCronFormat.
+	cronFormat CronFormat
+
+	// See WithRunNowDedup.
+	runNowDedup time.Duration
+
+	// The tag of the entries to spread over their interval, see WithSpread.
+	spreadTag string
+
+	// Serializes the updates of the scratchpads, see Scratchpad.
+	scratchMu sync.Mutex
+
+	// Set while dispatch is held, see Quiesce. It is owned by the run loop.
+	held bool
+
+	// Runs in flight, guarded by mu, for Quiesce.
+	inFlight int
+
+	// Held by the run loop while it changes the entries or their NextTime,
+	// so View can read them from outside of the loop.
+	entriesMu sync.RWMutex
+
+	// The entries by name, and those to take off the schedule once they
+	// dispatched their last run. See heap.go.
+	byName   map[string]*Entry
+	retiring []*Entry
+
+	// Job factories of this Cron, see RegisterJobFactory.
+	jobFactories jobRegistry
+
+	duplicates DuplicatePolicy
+
+	// Tells the tenant of an entry, for the WorkerPool, see WithTenant, and
+	// the tenants with a pool of their own, see WithTenantPool.
+	tenant      func(*Entry) string
+	tenantPools map[string]*WorkerPool
+
+	// Maintenance windows, whose events are emitted while watching, that is
+	// while the run loop runs. See AddMaintenanceWindow.
+	maintenance map[string]*maintenance
+	watching    bool
+
+	// Health checks the entries may depend on, see AddHealthCheck.
+	health map[string]HealthCheck
 }
 
 // Job is an interface for submitted cron jobs.
@@ -26,7 +180,9 @@ type Job interface {
 	Run()
 }
 
-// The Schedule describes a job's duty cycle.
+// The Schedule describes a job's duty cycle. Besides those of this package,
+// such as Daily, ParseCron and Every, any implementation may be plugged in
+// with ScheduleJob.
 type Schedule interface {
 	// Return the next activation time, later than the given time.
 	// Next is invoked initially, and then each time the job is run.
@@ -40,6 +196,10 @@ type Entry struct {
 
 	//执行周期
 	Interval time.Duration
+
+	// If set, the entry runs on this schedule instead of every Interval
+	// from its start time. See ScheduleJob.
+	Schedule Schedule
 	// started or this entry's schedule is unsatisfiable
 	// The next time the job will run. This is the zero time if Cron has not been
 	NextTime time.Time
@@ -47,8 +207,276 @@ type Entry struct {
 	// The Job to run.
 	Job Job
 
+	// The kind and parameters the Job was made from, for entries added with
+	// AddRegisteredJob. See RegisterJobFactory.
+	Kind   string
+	Params map[string]interface{}
+
 	// Unique name to identify the Entry so as to be able to remove it later.
 	Name string
+
+	// Version of the definition of the entry, starting at 1 and bumped each
+	// time the entry is redefined. See UpdateJob.
+	Version int
+
+	// Free form labels, and the namespace (tenant, team, ...) the entry
+	// belongs to. Policies such as budgets select entries by these.
+	Tags      []string
+	Namespace string
+
+	// Where the times of the entry are shown, see WithDisplayLocation.
+	DisplayLocation *time.Location
+
+	// Whatever integrators attach to the entry, see WithMetadata.
+	Metadata map[string]string
+
+	// Resources the runs use, such as "db" or "gpu". See WithResources.
+	Resources []string
+
+	// Labels the instance running the entry must have. See WithAffinity.
+	Affinity []string
+
+	// What a run costs, in whatever unit the budgets use. See WithCost.
+	Cost float64
+
+	// If Flex is non-zero, runs may start up to Flex later than scheduled
+	// so as to land in the Preferred window. See WithPreferredWindow.
+	Preferred DailyWindow
+	Flex      time.Duration
+
+	// Wrap the job of each run, inside the chain of the scheduler, or in
+	// its place if ChainOverride. See WithEntryChain and WithChainOverride.
+	Chain         Chain
+	ChainOverride bool
+
+	// Carries out the runs of the entry in place of the executor of the
+	// scheduler, see WithEntryExecutor.
+	Executor Executor
+
+	// Trigger runs of the entry on top of its schedule, see WithSource.
+	Sources []Source
+
+	// If non-zero, the entry runs at this interval after a failed run until
+	// one succeeds. See WithFailureInterval.
+	FailureInterval time.Duration
+
+	// How much later than their nominal times the runs of the entry start,
+	// to spread them with the others of its interval, see WithSpread.
+	Phase time.Duration
+
+	// How many times a failed run is retried, the first retry after
+	// RetryBackoff, see WithRetries.
+	Retries      int
+	RetryBackoff time.Duration
+
+	// How many retries the entry may make per RetryWindow, see
+	// WithRetryBudget.
+	RetryBudget int
+	RetryWindow time.Duration
+
+	// The health checks runs wait for, and for how long at most before
+	// Deferral applies. See WithDependsOn and WithMaxDeferral.
+	DependsOn   []string
+	MaxDeferral time.Duration
+	Deferral    DeferralPolicy
+
+	// If non-zero, a run that goes longer than this without a heartbeat is
+	// cancelled and recorded as stalled. See WithHeartbeat.
+	HeartbeatTimeout time.Duration
+
+	// Whether the context of each run has a deadline, Margin before the
+	// next occurrence of the entry is due. See WithNextRunDeadline.
+	NextRunDeadline bool
+	NextRunMargin   time.Duration
+
+	// How the last run ended. Empty if the entry has not run yet.
+	LastOutcome Outcome
+
+	// How many runs panicked and were recovered.
+	Panics int
+
+	// A disabled entry is kept, but no longer run. See PanicDisable.
+	Disabled bool
+
+	// Pauses the entry once its runs keep failing, and the state it is in.
+	// See WithCircuitBreaker.
+	Breaker *CircuitBreaker
+	Circuit CircuitState
+
+	// What to do with a run that is due while the previous one is still
+	// going. See WithOverlap.
+	Overlap OverlapPolicy
+
+	// If non-zero, how many runs of the entry may go at once. See
+	// WithMaxConcurrent.
+	MaxConcurrent int
+
+	// If non-zero, the entry is retired after that many runs, see
+	// WithMaxRuns. Runs counts the runs dispatched so far.
+	MaxRuns int
+	Runs    int
+
+	// The sequence number of the last trigger dispatched, see
+	// RunInfo.Sequence.
+	Sequence uint64
+
+	// When the entry was archived, on the entries returned by Archived.
+	RetiredAt time.Time
+
+	// If non-zero, a run triggered within CacheFor of the end of the last
+	// successful one is skipped. See WithResultCache.
+	CacheFor time.Duration
+
+	// If set, an update to the entry runs as a canary of the definition it
+	// replaces, see WithCanary. Stable is the version of that definition
+	// while the canary lasts.
+	Canary *Canary
+	Stable int
+
+	// If non-zero, how many runs may be waiting to start before further
+	// triggers are dropped. See WithMaxPending.
+	MaxPending int
+
+	// How many runs are waiting to start, and how many triggers were
+	// dropped because too many were.
+	Pending         int
+	DroppedTriggers int
+
+	// The latest progress reported by the current (or last) run of a
+	// ProgressJob. Only filled in on the copies returned by Entries.
+	Progress ProgressReport
+
+	// Index of the entry in the schedule heap, see heap.go.
+	index int
+
+	// The time the run is due by the interval alone, before NextTime is
+	// moved into the Preferred window.
+	nominal time.Time
+
+	// Handle handed to the current run, see dispatch.go.
+	progress *Progress
+
+	// Runs in flight, and triggers queued behind them by OverlapSerialize or
+	// WithMaxConcurrent.
+	active int
+	queue  []trigger
+
+	// Set on an entry put back by Rollback, or redefined by UpdateJob or a
+	// Plan. UpdateJob sets mustExist too: admit fails with ErrNoSuchEntry if
+	// there is no entry to redefine by then.
+	rollback  bool
+	update    bool
+	mustExist bool
+
+	// Set once the entry has dispatched its last run.
+	retired bool
+
+	// The last successful run, for the result cache.
+	lastSuccess *RunRecord
+
+	// Location and clock of the scheduler, see WithLocation and WithClock.
+	location *time.Location
+	clock    Clock
+
+	// The definition running alongside a canary, see canary.go.
+	canary *canaryState
+
+	// Set while the entry is on its failure interval, see failure.go.
+	failing bool
+
+	// The failed runs in a row, and the timer ending the cool-down of an
+	// open circuit, see circuit.go.
+	failures int
+	coolDown *time.Timer
+
+	// The current window of the retry budget and the retries spent in it,
+	// see WithRetryBudget.
+	retryWindowStart time.Time
+	retriesSpent     int
+
+	// Stops watching the sources while the scheduler runs, see source.go.
+	unwatch func()
+
+	// Set if the definition of the entry is in the JobStore, see
+	// AddDefinition.
+	persisted bool
+
+	// The format of the crontab expression of the entry, see
+	// WithEntryCronFormat. It only matters while the entry is added.
+	cronFormat CronFormat
+
+	// Counters of the runs, see Stats.
+	stats EntryStats
+}
+
+// EntryOption configures an Entry as it is added to the Cron.
+type EntryOption func(*Entry)
+
+// WithTags labels the entry.
+func WithTags(tags ...string) EntryOption {
+	return func(e *Entry) {
+		e.Tags = append(e.Tags, tags...)
+	}
+}
+
+// WithNamespace puts the entry in the given namespace.
+func WithNamespace(ns string) EntryOption {
+	return func(e *Entry) {
+		e.Namespace = ns
+	}
+}
+
+// HasTag reports whether the entry is labelled with tag.
+func (e *Entry) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Outcome describes how a run of a job ended.
+type Outcome string
+
+const (
+	// The job returned on its own.
+	OutcomeSuccess Outcome = "success"
+
+	// The job stopped sending heartbeats and was cancelled.
+	OutcomeStalled Outcome = "stalled"
+
+	// The job panicked, and the panic was recovered.
+	OutcomePanic Outcome = "panic"
+
+	// The job returned an error, see ErrJob.
+	OutcomeError Outcome = "error"
+
+	// The run was vetoed by the Interceptor and the job did not run.
+	OutcomeVetoed Outcome = "vetoed"
+
+	// The run would have gone over a budget and was skipped.
+	OutcomeOverBudget Outcome = "over_budget"
+
+	// The previous run was recent enough that it was skipped, see
+	// WithResultCache.
+	OutcomeCached Outcome = "cached"
+
+	// A maintenance window was open, see AddMaintenanceWindow.
+	OutcomePaused Outcome = "paused"
+
+	// A dependency stayed unhealthy for longer than the run could be
+	// deferred, see WithMaxDeferral.
+	OutcomeUnhealthy Outcome = "unhealthy"
+)
+
+// skipped reports whether the job did not run at all.
+func (o Outcome) skipped() bool {
+	switch o {
+	case OutcomeVetoed, OutcomeOverBudget, OutcomeCached, OutcomePaused, OutcomeUnhealthy:
+		return true
+	}
+	return false
 }
 
 // byTime is a wrapper for sorting the entry array by time
@@ -71,30 +499,85 @@ func (s byTime) Less(i, j int) bool {
 }
 
 func (t *Entry) Next() {
-	if t.NextTime.IsZero() {
-		if t.setStartTime.Before(time.Now()) {
-			dur := time.Now().Sub(t.setStartTime)
-			cnt := dur.Nanoseconds() / t.Interval.Nanoseconds()
-			t.NextTime = t.setStartTime.Add(time.Duration((cnt + 1) * t.Interval.Nanoseconds()))
+	if t.failing && t.FailureInterval > 0 {
+		if t.nominal.IsZero() {
+			t.nominal = t.now()
+		}
+		t.nominal = t.nominal.Add(t.FailureInterval)
+		t.NextTime = t.place(t.nominal)
+		return
+	}
+	s := t.schedule()
+	if s == nil {
+		// A one-shot entry runs at its start time, however late, and then
+		// never again.
+		if t.nominal.IsZero() {
+			t.nominal = t.setStartTime
+			t.NextTime = t.place(t.nominal)
 		} else {
-			//t.NextTime = t.setStartTime.Add(t.Interval)
-			t.NextTime = t.setStartTime
+			t.NextTime = time.Time{}
+		}
+		return
+	}
+	from := t.nominal
+	if from.IsZero() {
+		from = t.now()
+		// An interval entry runs at its start time first, unless that has
+		// passed already.
+		if t.Schedule == nil && !t.setStartTime.Before(from) {
+			t.nominal = t.setStartTime
+			t.NextTime = t.place(t.nominal)
+			return
 		}
-	} else {
-		t.NextTime = t.NextTime.Add(t.Interval)
 	}
+	loc := t.location
+	if loc == nil {
+		loc = time.Local
+	}
+	t.nominal = s.Next(from.In(loc))
+	t.NextTime = t.place(t.nominal)
+}
+
+// schedule returns the Schedule the entry runs on: its own, or for an
+// interval entry, one every Interval from its start time. It is nil for a
+// one-shot entry.
+func (t *Entry) schedule() Schedule {
+	switch {
+	case t.Schedule != nil:
+		return t.Schedule
+	case t.Interval > 0:
+		return intervalSchedule{start: t.setStartTime, interval: t.Interval}
+	}
+	return nil
 }
 
-// New returns a new Cron job runner.
-func New() *Cron {
-	return &Cron{
-		entries:  nil,
-		add:      make(chan *Entry),
-		remove:   make(chan string),
-		stop:     make(chan struct{}),
-		snapshot: make(chan entries),
-		running:  false,
+// now returns the time by the clock of the scheduler of the entry.
+func (t *Entry) now() time.Time {
+	if t.clock == nil {
+		return time.Now()
 	}
+	return t.clock.Now()
+}
+
+// New returns a new Cron job runner, modified by the given options.
+func New(opts ...Option) *Cron {
+	c := &Cron{
+		entries: nil,
+		stop:    make(chan struct{}),
+		do:      make(chan func()),
+		running: false,
+
+		logger:       DiscardLogger,
+		location:     time.Local,
+		clock:        realClock{},
+		backfillRate: time.Second,
+		archiveTTL:   24 * time.Hour,
+		horizon:      defaultHorizon,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // A wrapper that turns a func() into a cron.Job
@@ -103,160 +586,409 @@ type FuncJob func()
 func (f FuncJob) Run() { f() }
 
 // AddFunc adds a func to the Cron to be run on the given schedule.
-func (c *Cron) AddFunc(startTime time.Time, Interval time.Duration, cmd func(), name string) {
-	c.AddJob(startTime, Interval, FuncJob(cmd), name)
+func (c *Cron) AddFunc(startTime time.Time, Interval time.Duration, cmd func(), name string, opts ...EntryOption) error {
+	return c.AddJob(startTime, Interval, FuncJob(cmd), name, opts...)
 }
 
 // AddFunc adds a Job to the Cron to be run on the given schedule.
-func (c *Cron) AddJob(startTime time.Time, Interval time.Duration, cmd Job, name string) {
-	c.Schedule(startTime, Interval, cmd, name)
+func (c *Cron) AddJob(startTime time.Time, Interval time.Duration, cmd Job, name string, opts ...EntryOption) error {
+	return c.Schedule(startTime, Interval, cmd, name, opts...)
 }
 
-// RemoveJob removes a Job from the Cron based on name.
-func (c *Cron) RemoveJob(name string) {
-	if !c.running {
-		i := c.entries.pos(name)
+// AddFuncOn adds a func to the Cron to be run on the given Schedule, such as
+// Daily or Weekly.
+func (c *Cron) AddFuncOn(s Schedule, cmd func(), name string, opts ...EntryOption) error {
+	return c.AddJobOn(s, FuncJob(cmd), name, opts...)
+}
 
-		if i == -1 {
-			return
-		}
+// AddJobOn adds a Job to the Cron to be run on the given Schedule.
+func (c *Cron) AddJobOn(s Schedule, cmd Job, name string, opts ...EntryOption) error {
+	return c.ScheduleJob(s, cmd, name, opts...)
+}
 
-		c.entries = c.entries[:i+copy(c.entries[i:], c.entries[i+1:])]
-		return
+// ScheduleJob adds a Job to the Cron to be run on the given Schedule, which
+// may be any implementation: the entry runs at sched.Next of now once the
+// scheduler starts, and then at sched.Next of each run's time, until Next
+// returns the zero time. Next is also called to lint and backfill the
+// entry, so it should only depend on the time it is given. Otherwise it is
+// like Schedule.
+func (c *Cron) ScheduleJob(sched Schedule, job Job, name string, opts ...EntryOption) error {
+	return c.Schedule(time.Time{}, 0, job, name, append([]EntryOption{onSchedule(sched)}, opts...)...)
+}
+
+// onSchedule sets the Schedule of the entry.
+func onSchedule(s Schedule) EntryOption {
+	return func(e *Entry) {
+		e.Schedule = s
 	}
+}
 
-	c.remove <- name
+// RemoveJob removes a Job from the Cron based on name. The entry is gone
+// from Entries once it returns.
+func (c *Cron) RemoveJob(name string) {
+	c.removeJob(name)
 }
 
-func (entrySlice entries) pos(name string) int {
-	for p, e := range entrySlice {
-		if e.Name == name {
-			return p
-		}
-	}
-	return -1
+// removeJob is RemoveJob, telling whether there was an entry to remove.
+func (c *Cron) removeJob(name string) (found bool) {
+	c.inLoop(func() {
+		found = c.drop(name)
+	})
+	return found
 }
 
-// Schedule adds a Job to the Cron to be run on the given schedule.
-func (c *Cron) Schedule(startTime time.Time, Interval time.Duration, cmd Job, name string) {
+// Schedule adds a Job to the Cron to be run on the given schedule. What
+// happens if there is an entry with that name already depends on the
+// DuplicatePolicy; by default it is replaced. The error is only ever
+// ErrDuplicateName, under DuplicateError. It returns once the entry is on
+// the schedule, so Entries and Entry show it, whether the scheduler is
+// running, starting or stopping.
+func (c *Cron) Schedule(startTime time.Time, Interval time.Duration, cmd Job, name string, opts ...EntryOption) error {
 	entry := &Entry{
 		setStartTime: startTime,
 		Interval:     Interval,
 		Job:          cmd,
 		Name:         name,
 	}
+	for _, opt := range opts {
+		opt(entry)
+	}
+	return c.admit(entry)
+}
 
-	if !c.running {
-		i := c.entries.pos(entry.Name)
-		if i != -1 {
-			c.entries = c.entries[:i+copy(c.entries[i:], c.entries[i+1:])]
+// admit puts the entry on the schedule, through the run loop while running.
+func (c *Cron) admit(entry *Entry) (err error) {
+	c.inLoop(func() {
+		if entry.mustExist && c.lookup(entry.Name) == nil {
+			err = ErrNoSuchEntry
+			return
+		}
+		if err = c.put(entry); err == nil && c.running {
+			entry.Next()
+			c.reschedule(entry)
+			c.watchSources(entry)
+		}
+	})
+	return err
+}
+
+// put adds the entry, replacing the one with the same name if any, whose
+// version it bumps, as the DuplicatePolicy says. It is called from the run
+// loop while running.
+func (c *Cron) put(entry *Entry) error {
+	if err := c.checkDuplicate(entry); err != nil {
+		return err
+	}
+	event := EventAdded
+	entry.Version = 1
+	entry.location = c.location
+	entry.clock = c.clock
+	c.warnAmbiguous(entry)
+	c.warnLint(entry)
+	prev := c.lookup(entry.Name)
+	c.loadSequence(entry, prev)
+	if prev != nil {
+		event = EventUpdated
+		entry.Version = prev.Version + 1
+		startCanary(entry, prev)
+		c.unlink(prev)
+		if prev.Interval != entry.Interval {
+			defer c.spread(prev.Interval)
 		}
-		c.entries = append(c.entries, entry)
-		return
 	}
+	if entry.rollback {
+		event = EventRolledBack
+		entry.rollback = false
+	}
+	entry.update = false
+	entry.mustExist = false
+	entry.stats.Since = time.Now()
+	c.insert(entry)
+	c.spread(entry.Interval)
+	c.keepDefinition(entry)
+	c.emit(Event{Type: event, Name: entry.Name, Version: entry.Version, Metadata: entry.Metadata})
+	c.publishChange(event, entry)
+	return nil
+}
 
-	c.add <- entry
+// drop removes the named entry, telling whether there was one. It is
+// called from the run loop while running.
+func (c *Cron) drop(name string) bool {
+	e := c.lookup(name)
+	if e == nil {
+		return false
+	}
+	c.unlink(e)
+	c.spread(e.Interval)
+	c.forget(e)
+	c.emit(Event{Type: EventRemoved, Name: name, Version: e.Version, Metadata: e.Metadata})
+	c.publishChange(EventRemoved, e)
+	return true
 }
 
-// Entries returns a snapshot of the cron entries.
+// Entries returns a snapshot of the cron entries, soonest to run first. It
+// takes the snapshot under the lock the run loop changes the entries with,
+// so it works the same whether the scheduler is running, stopped or not
+// started yet.
 func (c *Cron) Entries() []*Entry {
-	if c.running {
-		c.snapshot <- nil
-		x := <-c.snapshot
-		return x
-	}
+	c.entriesMu.RLock()
+	defer c.entriesMu.RUnlock()
 	return c.entrySnapshot()
 }
 
+// Entry returns a snapshot of the named entry, as Entries does.
+func (c *Cron) Entry(name string) (*Entry, bool) {
+	c.entriesMu.RLock()
+	defer c.entriesMu.RUnlock()
+	e := c.lookup(name)
+	if e == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return e.copy(), true
+}
+
 // Start the cron scheduler in its own go-routine.
 func (c *Cron) Start() {
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
 	if c.running == false {
 		c.running = true
+		c.mu.Lock()
+		if c.runCtx != nil && c.runCtx.Err() != nil {
+			// Shut down before.
+			c.runCtx = nil
+		}
+		c.halt = make(chan struct{})
+		c.mu.Unlock()
+		c.scheduleEntries()
+		for _, e := range c.entries {
+			c.watchSources(e)
+		}
 		go c.run()
 	}
 }
 
-// Run the scheduler.. this is private just due to the need to synchronize
-// access to the 'running' state variable.
-func (c *Cron) run() {
-	// Figure out the next activation times for each entry.
-	now := time.Now().Local()
+// scheduleEntries figures out the next activation times for each entry,
+// before the run loop starts, so Entries shows them once Start returns.
+func (c *Cron) scheduleEntries() {
+	c.entriesMu.Lock()
 	for _, entry := range c.entries {
 		entry.Next()
 	}
+	if c.warmup != nil {
+		c.warmup.restart()
+	}
+	c.resumeHandoff()
+	heap.Init(&c.entries)
+	c.trace(TraceEvent{Kind: TraceStart, Entries: traceNames(c.entries.sorted())})
+	c.entriesMu.Unlock()
+}
 
+// Run the scheduler.. this is private just due to the need to synchronize
+// access to the 'running' state variable.
+func (c *Cron) run() {
+	now := c.clock.Now().In(c.location)
+	c.watchMaintenance(true)
+	defer c.watchMaintenance(false)
+	c.debug(SubsystemLifecycle, "scheduler started", "entries", len(c.entries))
+	defer c.debug(SubsystemLifecycle, "scheduler stopped")
+
+	c.lastTick = now
 	for {
-		// Determine the next entry to run.
-		sort.Sort(byTime(c.entries))
+		// Determine the next entry to run. Without any, the loop sleeps
+		// until the next housekeeping tick, still handling new entries and
+		// stop requests.
 		var effective time.Time
-		if len(c.entries) == 0 || c.entries[0].NextTime.IsZero() {
-			// If there are no entries yet, just sleep - it still handles new entries
-			// and stop requests.
-			effective = now.AddDate(10, 0, 0)
-		} else {
+		if len(c.entries) > 0 {
 			effective = c.entries[0].NextTime
 		}
+		tick := c.lastTick.Add(c.horizon)
+		wakeAt := effective
+		if wakeAt.IsZero() || tick.Before(wakeAt) {
+			wakeAt = tick
+		}
 
-		select {
-		case now = <-time.After(effective.Sub(now)):
-			// Run every entry whose next time was this effective time.
-			for _, e := range c.entries {
-				if !e.NextTime.Round(time.Second).Equal(effective.Round(time.Second)) {
-					break
-				}
-				go e.Job.Run()
-				e.Next()
-			}
-			continue
+		// While quiesced the loop doesn't wake up for the triggers, which
+		// then run once released, but it keeps ticking.
+		if c.held {
+			wakeAt = tick
+		}
+		wake := c.clock.After(wakeAt.Sub(now))
 
-		case newEntry := <-c.add:
-			i := c.entries.pos(newEntry.Name)
-			if i != -1 {
-				c.entries = c.entries[:i+copy(c.entries[i:], c.entries[i+1:])]
+		select {
+		case now = <-wake:
+			if !wakeAt.Before(tick) {
+				c.housekeep(now)
 			}
-			c.entries = append(c.entries, newEntry)
-			newEntry.Next()
-
-		case name := <-c.remove:
-			i := c.entries.pos(name)
-
-			if i == -1 {
-				break
+			if !c.held && wakeAt.Equal(effective) {
+				c.entriesMu.Lock()
+				c.fire(effective)
+				c.entriesMu.Unlock()
 			}
+			continue
 
-			c.entries = c.entries[:i+copy(c.entries[i:], c.entries[i+1:])]
-
-		case <-c.snapshot:
-			c.snapshot <- c.entrySnapshot()
+		case f := <-c.do:
+			c.entriesMu.Lock()
+			f()
+			c.entriesMu.Unlock()
 
 		case <-c.stop:
 			return
 		}
 
-		// 'now' should be updated after newEntry and snapshot cases.
-		now = time.Now().Local()
+		// 'now' should be updated after the do case.
+		now = c.clock.Now().In(c.location)
+	}
+}
+
+// fire runs every entry whose next time was effective, and moves them on to
+// their next time. The caller must hold c.entriesMu.
+func (c *Cron) fire(effective time.Time) {
+	skip := c.faults.skip()
+	due := c.due(effective)
+	c.trace(TraceEvent{Kind: TraceWake, Scheduled: effective, Entries: traceNames(due)})
+	if skip {
+		c.trace(TraceEvent{Kind: TraceDecision, Decision: "skipped"})
+	}
+	smear := c.smear(len(due))
+	for i, e := range due {
+		if smear > 0 && i > 0 {
+			c.trace(TraceEvent{Kind: TraceDecision, Entry: e.Name, Decision: "smeared", Delay: smear * time.Duration(i)})
+		}
+		if !skip {
+			t := trigger{scheduled: e.NextTime, delay: smear * time.Duration(i)}
+			if t.delay > 0 {
+				t.reason = ReasonRateLimit
+			}
+			c.dispatchTrigger(e, t)
+		}
+		e.Next()
+		c.requeue(e)
+	}
+	c.retire()
+}
+
+// inLoop calls f from the run loop, where it may touch the entries, and
+// waits for it to return. If the scheduler isn't running, f is called
+// straight away, under the same lock. Start and Stop wait for f, which may
+// read c.running.
+func (c *Cron) inLoop(f func()) {
+	c.lifecycle.RLock()
+	defer c.lifecycle.RUnlock()
+	c.loop(f)
+}
+
+// loop is inLoop, for the caller holding c.lifecycle.
+func (c *Cron) loop(f func()) {
+	if !c.running {
+		c.entriesMu.Lock()
+		defer c.entriesMu.Unlock()
+		f()
+		return
+	}
+	done := make(chan struct{})
+	c.do <- func() {
+		f()
+		close(done)
 	}
+	<-done
 }
 
-// Stop the cron scheduler.
+// Stop the cron scheduler. The runs going carry on, see Shutdown.
 func (c *Cron) Stop() {
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
 	if c.running == true {
+		c.loop(func() {
+			for _, e := range c.entries {
+				e.unwatchSources()
+			}
+		})
 		c.stop <- struct{}{}
 		c.running = false
+		c.mu.Lock()
+		close(c.halt)
+		c.mu.Unlock()
 	}
 }
 
-// entrySnapshot returns a copy of the current cron entry list.
+// entrySnapshot returns a copy of the current cron entry list. The caller
+// must hold c.entriesMu.
 func (c *Cron) entrySnapshot() []*Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	entries := []*Entry{}
-	for _, e := range c.entries {
-		entries = append(entries, &Entry{
-			setStartTime: e.setStartTime,
-			NextTime:     e.NextTime,
-			Interval:     e.Interval,
-			Job:          e.Job,
-			Name:         e.Name,
-		})
+	for _, e := range c.entries.sorted() {
+		entries = append(entries, e.copy())
 	}
 	return entries
 }
+
+// copy returns a copy of the entry, as handed out by Entries. The caller must
+// hold c.mu.
+func (e *Entry) copy() *Entry {
+	cp := e.definition()
+	cp.NextTime = e.NextTime
+	cp.LastOutcome = e.LastOutcome
+	cp.Panics = e.Panics
+	cp.Disabled = e.Disabled
+	cp.Circuit = e.Circuit
+	cp.Runs = e.Runs
+	cp.Sequence = e.Sequence
+	cp.Pending = e.Pending
+	cp.DroppedTriggers = e.DroppedTriggers
+	cp.Stable = e.Stable
+	cp.Phase = e.Phase
+	cp.Progress = e.progress.Last()
+	return cp
+}
+
+// definition returns a copy of what the entry was added with, without any
+// of its run state.
+func (e *Entry) definition() *Entry {
+	return &Entry{
+		setStartTime:     e.setStartTime,
+		Interval:         e.Interval,
+		Schedule:         e.Schedule,
+		Job:              e.Job,
+		Kind:             e.Kind,
+		Params:           e.Params,
+		Name:             e.Name,
+		Version:          e.Version,
+		Tags:             append([]string(nil), e.Tags...),
+		Namespace:        e.Namespace,
+		DisplayLocation:  e.DisplayLocation,
+		Metadata:         cloneMetadata(e.Metadata),
+		Resources:        append([]string(nil), e.Resources...),
+		Affinity:         append([]string(nil), e.Affinity...),
+		Cost:             e.Cost,
+		Preferred:        e.Preferred,
+		Flex:             e.Flex,
+		HeartbeatTimeout: e.HeartbeatTimeout,
+		NextRunDeadline:  e.NextRunDeadline,
+		NextRunMargin:    e.NextRunMargin,
+		FailureInterval:  e.FailureInterval,
+		Retries:          e.Retries,
+		RetryBackoff:     e.RetryBackoff,
+		RetryBudget:      e.RetryBudget,
+		RetryWindow:      e.RetryWindow,
+		DependsOn:        append([]string(nil), e.DependsOn...),
+		MaxDeferral:      e.MaxDeferral,
+		Deferral:         e.Deferral,
+		Chain:            e.Chain,
+		ChainOverride:    e.ChainOverride,
+		Sources:          e.Sources,
+		Executor:         e.Executor,
+		Overlap:          e.Overlap,
+		MaxConcurrent:    e.MaxConcurrent,
+		MaxPending:       e.MaxPending,
+		MaxRuns:          e.MaxRuns,
+		CacheFor:         e.CacheFor,
+		Canary:           e.Canary,
+		Breaker:          e.Breaker,
+		location:         e.location,
+		persisted:        e.persisted,
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 const ONE_SECOND = 1*time.Second + 10*time.Millisecond
@@ -261,3 +262,59 @@ func stop(cron *Cron) chan bool {
 	}()
 	return ch
 }
+
+// Test that the entries can still be inspected once the scheduler is stopped.
+func TestEntriesAfterStop(t *testing.T) {
+	cron := New()
+	next := time.Now().Add(time.Hour)
+	cron.AddFunc(next, time.Hour, func() {}, "job")
+	cron.Start()
+	cron.Stop()
+
+	done := make(chan []*Entry)
+	go func() { done <- cron.Entries() }()
+	select {
+	case entries := <-done:
+		if len(entries) != 1 || !entries[0].NextTime.Equal(next) {
+			t.Errorf("unexpected entries %v", entries)
+		}
+	case <-time.After(ONE_SECOND):
+		t.Fatal("Entries blocked after Stop")
+	}
+	if e, ok := cron.Entry("job"); !ok || e.Name != "job" {
+		t.Errorf("expected the entry, got %v", e)
+	}
+	if _, ok := cron.Entry("missing"); ok {
+		t.Error("expected no such entry")
+	}
+	admin := NewAdmin(cron, TokenAuth(map[string]Role{"r": RoleReader}))
+	if w := adminRequest(t, admin, "GET", "/entries", "r"); w.Code != 200 || !strings.Contains(w.Body.String(), `"job"`) {
+		t.Errorf("unexpected admin response %d %s", w.Code, w.Body)
+	}
+}
+
+// Test that an entry added or removed while the scheduler starts or stops
+// shows once the call returns.
+func TestChangesWhileStartingAndStopping(t *testing.T) {
+	cron := New()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			cron.Start()
+			cron.Stop()
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		name := "job" + strconv.Itoa(i)
+		cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, name)
+		if _, ok := cron.Entry(name); !ok {
+			t.Fatalf("expected %s once added", name)
+		}
+		cron.RemoveJob(name)
+		if _, ok := cron.Entry(name); ok {
+			t.Fatalf("expected %s gone once removed", name)
+		}
+	}
+	<-done
+}
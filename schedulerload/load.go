@@ -0,0 +1,216 @@
+// Package schedulerload puts a scheduler under synthetic load and measures
+// how promptly it dispatches the runs, to validate the sizing of a
+// deployment, such as its WorkerPool, before rolling it out:
+//
+//	report, err := schedulerload.Run(schedulerload.Config{
+//		Entries:  5000,
+//		Interval: schedulerload.Uniform(time.Minute, time.Hour),
+//		Runtime:  schedulerload.Exponential(2 * time.Second),
+//		Duration: 10 * time.Minute,
+//		Options:  []scheduler.Option{scheduler.WithWorkerPool(scheduler.NewWorkerPool(200))},
+//	})
+package schedulerload
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	scheduler "github.com/flamingo-sky/go-scheduler"
+)
+
+// Distribution draws a duration, such as the interval of an entry or how
+// long a run takes, from r.
+type Distribution func(r *rand.Rand) time.Duration
+
+// Constant always draws d.
+func Constant(d time.Duration) Distribution {
+	return func(*rand.Rand) time.Duration { return d }
+}
+
+// Uniform draws evenly from [min, max).
+func Uniform(min, max time.Duration) Distribution {
+	return func(r *rand.Rand) time.Duration {
+		if max <= min {
+			return min
+		}
+		return min + time.Duration(r.Int63n(int64(max-min)))
+	}
+}
+
+// Exponential draws from an exponential distribution of the given mean,
+// which is how the runtimes of many jobs are spread: mostly short, with a
+// long tail.
+func Exponential(mean time.Duration) Distribution {
+	return func(r *rand.Rand) time.Duration {
+		return time.Duration(r.ExpFloat64() * float64(mean))
+	}
+}
+
+// Normal draws from a normal distribution, never below zero.
+func Normal(mean, stddev time.Duration) Distribution {
+	return func(r *rand.Rand) time.Duration {
+		return max(0, time.Duration(r.NormFloat64()*float64(stddev))+mean)
+	}
+}
+
+// Config describes the load to generate.
+type Config struct {
+	// How many entries to add, each running a synthetic job.
+	Entries int
+
+	// The interval of each entry. The scheduler goes by whole seconds, so
+	// intervals below one are raised to it. The default is a minute.
+	Interval Distribution
+
+	// When the first run of each entry is due after the load starts. The
+	// default spreads them over the first interval of the entry.
+	Offset Distribution
+
+	// How long each run of the synthetic jobs takes. The default is no time
+	// at all.
+	Runtime Distribution
+
+	// How long to run the load for.
+	Duration time.Duration
+
+	// The options of the scheduler under test, such as its WorkerPool. The
+	// Metrics are taken over to measure the runs.
+	Options []scheduler.Option
+
+	// Seed of the draws, so a load can be reproduced.
+	Seed int64
+}
+
+// Report is what Run measured.
+type Report struct {
+	// Runs that ended during the load, and the runs of each outcome.
+	Runs     int
+	Outcomes map[scheduler.Outcome]int
+
+	// Dispatch latency: from when a run was due to when its job started.
+	Latency Percentiles
+
+	// Triggers dropped by the scheduler, see scheduler.WithMaxPending.
+	Dropped int
+}
+
+// Percentiles summarizes a set of durations.
+type Percentiles struct {
+	P50, P90, P99, Max time.Duration
+	Mean               time.Duration
+}
+
+func (p Percentiles) String() string {
+	return fmt.Sprintf("p50=%v p90=%v p99=%v max=%v mean=%v", p.P50, p.P90, p.P99, p.Max, p.Mean)
+}
+
+// Run generates the load of cfg on a new scheduler, for cfg.Duration, and
+// reports how it coped. Runs still going when the load ends aren't counted.
+func Run(cfg Config) (Report, error) {
+	if cfg.Entries < 1 || cfg.Duration <= 0 {
+		return Report{}, errors.New("schedulerload: Entries and Duration must be positive")
+	}
+	if cfg.Interval == nil {
+		cfg.Interval = Constant(time.Minute)
+	}
+	if cfg.Runtime == nil {
+		cfg.Runtime = Constant(0)
+	}
+	r := rand.New(rand.NewSource(cfg.Seed))
+
+	m := &collector{outcomes: make(map[scheduler.Outcome]int)}
+	cron := scheduler.New(append(cfg.Options, scheduler.WithMetrics(m))...)
+	start := time.Now()
+	for i := 0; i < cfg.Entries; i++ {
+		interval := max(time.Second, cfg.Interval(r))
+		offset := Uniform(0, interval)(r)
+		if cfg.Offset != nil {
+			offset = cfg.Offset(r)
+		}
+		job := newJob(cfg.Runtime, r.Int63())
+		if err := cron.AddFunc(start.Add(offset), interval, job, fmt.Sprintf("load-%d", i)); err != nil {
+			return Report{}, err
+		}
+	}
+	cron.Start()
+	time.Sleep(time.Until(start.Add(cfg.Duration)))
+	cron.Stop()
+	return m.report(), nil
+}
+
+// newJob returns a synthetic job running for draws of runtime.
+func newJob(runtime Distribution, seed int64) func() {
+	var mu sync.Mutex
+	r := rand.New(rand.NewSource(seed))
+	return func() {
+		mu.Lock()
+		d := runtime(r)
+		mu.Unlock()
+		time.Sleep(d)
+	}
+}
+
+// collector is the Metrics of the scheduler under load.
+type collector struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	outcomes  map[scheduler.Outcome]int
+	dropped   int
+}
+
+func (m *collector) RunFinished(r scheduler.RunRecord) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latencies = append(m.latencies, r.Start.Sub(r.Scheduled))
+	m.outcomes[r.Outcome]++
+}
+
+func (m *collector) TriggerDropped(string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dropped++
+}
+
+// report returns what was collected so far.
+func (m *collector) report() Report {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	outcomes := make(map[scheduler.Outcome]int, len(m.outcomes))
+	for o, n := range m.outcomes {
+		outcomes[o] = n
+	}
+	return Report{
+		Runs:     len(m.latencies),
+		Outcomes: outcomes,
+		Latency:  Summarize(m.latencies),
+		Dropped:  m.dropped,
+	}
+}
+
+// Summarize returns the percentiles of durations, by the nearest rank.
+func Summarize(durations []time.Duration) Percentiles {
+	if len(durations) == 0 {
+		return Percentiles{}
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := func(p float64) time.Duration {
+		return sorted[int(math.Ceil(p*float64(len(sorted))))-1]
+	}
+	var sum time.Duration
+	for _, d := range sorted {
+		sum += d
+	}
+	return Percentiles{
+		P50:  rank(0.50),
+		P90:  rank(0.90),
+		P99:  rank(0.99),
+		Max:  sorted[len(sorted)-1],
+		Mean: sum / time.Duration(len(sorted)),
+	}
+}
@@ -0,0 +1,51 @@
+package schedulerload
+
+import (
+	"testing"
+	"time"
+
+	scheduler "github.com/flamingo-sky/go-scheduler"
+)
+
+func TestSummarize(t *testing.T) {
+	var durations []time.Duration
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	p := Summarize(durations)
+	if p.P50 != 50*time.Millisecond || p.P90 != 90*time.Millisecond || p.P99 != 99*time.Millisecond || p.Max != 100*time.Millisecond {
+		t.Errorf("unexpected percentiles %v", p)
+	}
+	if p.Mean != 50500*time.Microsecond {
+		t.Errorf("expected a mean of 50.5ms, got %v", p.Mean)
+	}
+	if (Summarize(nil) != Percentiles{}) {
+		t.Error("expected nothing to summarize to zero")
+	}
+}
+
+func TestRun(t *testing.T) {
+	report, err := Run(Config{
+		Entries:  50,
+		Interval: Constant(time.Second),
+		Offset:   Uniform(100*time.Millisecond, 500*time.Millisecond),
+		Runtime:  Uniform(0, 10*time.Millisecond),
+		Duration: 1500 * time.Millisecond,
+		Options:  []scheduler.Option{scheduler.WithWorkerPool(scheduler.NewWorkerPool(10))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Runs < 50 || report.Outcomes[scheduler.OutcomeSuccess] != report.Runs {
+		t.Errorf("expected every entry to run successfully, got %+v", report)
+	}
+	if report.Latency.Max <= 0 || report.Latency.P50 > report.Latency.Max {
+		t.Errorf("unexpected latency %v", report.Latency)
+	}
+}
+
+func TestRunNeedsLoad(t *testing.T) {
+	if _, err := Run(Config{}); err == nil {
+		t.Error("expected an error for an empty load")
+	}
+}
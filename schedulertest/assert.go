@@ -0,0 +1,67 @@
+// Package schedulertest helps test Schedule implementations without running
+// a scheduler, such as in table tests over the locations a schedule must
+// hold up in:
+//
+//	for _, loc := range []*time.Location{time.UTC, newYork, sydney} {
+//		from := time.Date(2030, 1, 1, 0, 0, 0, 0, loc)
+//		schedulertest.AssertFireTimes(t, scheduler.Daily(2, 30, 0), from, []time.Time{
+//			time.Date(2030, 1, 1, 2, 30, 0, 0, loc),
+//			time.Date(2030, 1, 2, 2, 30, 0, 0, loc),
+//		})
+//	}
+package schedulertest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	scheduler "github.com/flamingo-sky/go-scheduler"
+)
+
+// FireTimes returns the first n times s fires after from, in the location of
+// from, as the scheduler would ask for them. There are fewer if s runs out
+// of occurrences.
+func FireTimes(s scheduler.Schedule, from time.Time, n int) []time.Time {
+	var times []time.Time
+	for next := from; len(times) < n; {
+		next = s.Next(next)
+		if next.IsZero() {
+			break
+		}
+		times = append(times, next)
+	}
+	return times
+}
+
+// AssertFireTimes checks that the first times s fires after from are want,
+// in order, and that the times go forward. Times compare as instants, so
+// want may be in any location; from sets the one s is asked in. Each
+// mismatch is reported with t.Errorf.
+func AssertFireTimes(t testing.TB, s scheduler.Schedule, from time.Time, want []time.Time) {
+	t.Helper()
+	got := FireTimes(s, from, len(want))
+	var errs []string
+	prev := from
+	for i, w := range want {
+		if i >= len(got) {
+			errs = append(errs, fmt.Sprintf("no occurrence %d, expected %s", i, format(w)))
+			break
+		}
+		if !got[i].After(prev) {
+			errs = append(errs, fmt.Sprintf("occurrence %d at %s is not after %s", i, format(got[i]), format(prev)))
+		}
+		if !got[i].Equal(w) {
+			errs = append(errs, fmt.Sprintf("occurrence %d: expected %s, got %s", i, format(w), format(got[i])))
+		}
+		prev = got[i]
+	}
+	if len(errs) > 0 {
+		t.Errorf("fire times from %s:\n\t%s", format(from), strings.Join(errs, "\n\t"))
+	}
+}
+
+func format(t time.Time) string {
+	return t.Format("2006-01-02 15:04:05.999999999 -0700 MST")
+}
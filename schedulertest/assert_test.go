@@ -0,0 +1,77 @@
+package schedulertest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	scheduler "github.com/flamingo-sky/go-scheduler"
+)
+
+// recorder is a testing.TB taking note of the errors instead of failing.
+type recorder struct {
+	testing.TB
+	errs []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func TestAssertFireTimesAcrossLocations(t *testing.T) {
+	for _, name := range []string{"UTC", "America/New_York", "Australia/Sydney"} {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			t.Skip(err)
+		}
+		from := time.Date(2030, 1, 1, 0, 0, 0, 0, loc)
+		AssertFireTimes(t, scheduler.Daily(2, 30, 0), from, []time.Time{
+			time.Date(2030, 1, 1, 2, 30, 0, 0, loc),
+			time.Date(2030, 1, 2, 2, 30, 0, 0, loc),
+			time.Date(2030, 1, 3, 2, 30, 0, 0, loc),
+		})
+	}
+}
+
+func TestAssertFireTimesSpringForward(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	// The clocks skip from 02:00 to 03:00 on March 10, 2030.
+	edt := time.FixedZone("EDT", -4*60*60)
+	AssertFireTimes(t, scheduler.Daily(2, 30, 0), time.Date(2030, 3, 9, 12, 0, 0, 0, loc), []time.Time{
+		time.Date(2030, 3, 10, 3, 30, 0, 0, edt),
+		time.Date(2030, 3, 11, 2, 30, 0, 0, edt),
+	})
+}
+
+func TestAssertFireTimesReportsMismatches(t *testing.T) {
+	from := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := &recorder{TB: t}
+	AssertFireTimes(r, scheduler.Daily(1, 0, 0), from, []time.Time{
+		time.Date(2030, 1, 1, 1, 0, 0, 0, time.UTC),
+		time.Date(2030, 1, 2, 2, 0, 0, 0, time.UTC),
+	})
+	if len(r.errs) != 1 || !strings.Contains(r.errs[0], "occurrence 1: expected 2030-01-02 02:00:00 +0000 UTC, got 2030-01-02 01:00:00 +0000 UTC") {
+		t.Errorf("unexpected errors %q", r.errs)
+	}
+
+	// A schedule that runs out of occurrences.
+	r = &recorder{TB: t}
+	AssertFireTimes(r, scheduler.Merge(), from, []time.Time{from.Add(time.Hour)})
+	if len(r.errs) != 1 || !strings.Contains(r.errs[0], "no occurrence 0") {
+		t.Errorf("unexpected errors %q", r.errs)
+	}
+}
+
+func TestFireTimes(t *testing.T) {
+	from := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	got := FireTimes(scheduler.Weekly(time.Monday, 9, 0), from, 2)
+	if len(got) != 2 || !got[0].Equal(time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)) || got[1].Sub(got[0]) != 7*24*time.Hour {
+		t.Errorf("unexpected fire times %v", got)
+	}
+}
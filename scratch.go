@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+const scratchPrefix = "scratch/"
+
+// Scratchpad is a small string key-value store of an entry that outlives its
+// runs, for incremental jobs to keep a cursor or a watermark in between:
+//
+//	func sync(p *Progress) {
+//		since, _, _ := p.Scratchpad().Get("cursor")
+//		...
+//		p.Scratchpad().Set("cursor", last)
+//	}
+//
+// It is persisted in the JobStore of the scheduler, see WithJobStore, under
+// "scratch/" and the name of the entry, so it is shared by the instances
+// sharing the store. Without a JobStore every call returns ErrNoJobStore.
+// Each call reads or writes through to the store, so the scratchpad is
+// meant for a few small values, not for the data of the job.
+type Scratchpad struct {
+	c    *Cron
+	name string
+}
+
+// Scratchpad returns the scratchpad of the entry of the run.
+func (p *Progress) Scratchpad() *Scratchpad {
+	if p == nil || p.scratch == nil {
+		return &Scratchpad{}
+	}
+	return p.scratch
+}
+
+// Scratchpad returns the scratchpad of the named entry, for seeding or
+// inspecting it from outside of its runs. The entry need not exist.
+func (c *Cron) Scratchpad(name string) *Scratchpad {
+	return &Scratchpad{c: c, name: name}
+}
+
+// Get returns the value under key, and whether there is one.
+func (s *Scratchpad) Get(key string) (string, bool, error) {
+	values, err := s.load()
+	if err != nil {
+		return "", false, err
+	}
+	v, ok := values[key]
+	return v, ok, nil
+}
+
+// Set stores value under key.
+func (s *Scratchpad) Set(key, value string) error {
+	return s.update(func(values map[string]string) {
+		values[key] = value
+	})
+}
+
+// Delete removes key. Deleting a missing key is not an error.
+func (s *Scratchpad) Delete(key string) error {
+	return s.update(func(values map[string]string) {
+		delete(values, key)
+	})
+}
+
+// Keys returns the keys of the scratchpad, sorted.
+func (s *Scratchpad) Keys() ([]string, error) {
+	values, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Clear removes all the keys.
+func (s *Scratchpad) Clear() error {
+	if s.c == nil || s.c.store == nil {
+		return ErrNoJobStore
+	}
+	s.c.scratchMu.Lock()
+	defer s.c.scratchMu.Unlock()
+	return s.c.store.Delete(scratchPrefix + s.name)
+}
+
+func (s *Scratchpad) load() (map[string]string, error) {
+	if s.c == nil || s.c.store == nil {
+		return nil, ErrNoJobStore
+	}
+	s.c.scratchMu.Lock()
+	defer s.c.scratchMu.Unlock()
+	return s.read()
+}
+
+// update applies f to the values and writes them back. Updates by this Cron
+// are serialized, those of other instances sharing the store are not.
+func (s *Scratchpad) update(f func(map[string]string)) error {
+	if s.c == nil || s.c.store == nil {
+		return ErrNoJobStore
+	}
+	s.c.scratchMu.Lock()
+	defer s.c.scratchMu.Unlock()
+	values, err := s.read()
+	if err != nil {
+		return err
+	}
+	f(values)
+	if len(values) == 0 {
+		return s.c.store.Delete(scratchPrefix + s.name)
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return s.c.store.Put(scratchPrefix+s.name, data)
+}
+
+// read returns the values in the store. The caller must hold c.scratchMu.
+func (s *Scratchpad) read() (map[string]string, error) {
+	values := make(map[string]string)
+	data, err := s.c.store.Get(scratchPrefix + s.name)
+	if err == ErrNotFound {
+		return values, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
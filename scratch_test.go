@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScratchpad(t *testing.T) {
+	store := NewMemoryStore()
+	cron := New(WithJobStore(store))
+	var cursors []string
+	cron.AddProgressFunc(time.Now(), 100*time.Millisecond, func(p *Progress) {
+		s := p.Scratchpad()
+		cursor, _, err := s.Get("cursor")
+		if err != nil {
+			t.Error(err)
+		}
+		cursors = append(cursors, cursor)
+		if err := s.Set("cursor", cursor+"x"); err != nil {
+			t.Error(err)
+		}
+	}, "incremental")
+	cron.Scratchpad("incremental").Set("cursor", "a")
+	cron.Start()
+	waitForHistory(t, cron, "incremental", 3)
+	cron.Stop()
+
+	if len(cursors) < 3 || cursors[0] != "a" || cursors[1] != "ax" || cursors[2] != "axx" {
+		t.Errorf("unexpected cursors %q", cursors)
+	}
+
+	// Another scheduler on the same store picks up where the first left off.
+	other := New(WithJobStore(store))
+	got, ok, err := other.Scratchpad("incremental").Get("cursor")
+	if err != nil || !ok || got != cursors[len(cursors)-1]+"x" {
+		t.Errorf("expected the last cursor, got %q, %v, %v", got, ok, err)
+	}
+	s := other.Scratchpad("incremental")
+	s.Set("seen", "1")
+	if keys, _ := s.Keys(); len(keys) != 2 || keys[0] != "cursor" || keys[1] != "seen" {
+		t.Errorf("unexpected keys %q", keys)
+	}
+	s.Delete("seen")
+	if _, ok, _ := s.Get("seen"); ok {
+		t.Error("expected seen to be deleted")
+	}
+	if err := s.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(scratchPrefix + "incremental"); err != ErrNotFound {
+		t.Errorf("expected the scratchpad to be gone, got %v", err)
+	}
+}
+
+func TestScratchpadNoStore(t *testing.T) {
+	if err := New().Scratchpad("x").Set("k", "v"); !errors.Is(err, ErrNoJobStore) {
+		t.Errorf("expected ErrNoJobStore, got %v", err)
+	}
+	var p *Progress
+	if _, _, err := p.Scratchpad().Get("k"); !errors.Is(err, ErrNoJobStore) {
+		t.Errorf("expected ErrNoJobStore, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoSecret is returned for a secret a SecretProvider doesn't have.
+var ErrNoSecret = errors.New("scheduler: no such secret")
+
+// SecretProvider resolves secrets by name, so job definitions can refer to
+// tokens and credentials instead of embedding them. Secrets are resolved at
+// each run, see Progress.Secret. Implementations must be safe for
+// concurrent use.
+type SecretProvider interface {
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// EnvSecrets is a SecretProvider reading secrets from the environment of the
+// scheduler, the secret "token" being the variable Prefix+"token".
+type EnvSecrets struct {
+	Prefix string
+}
+
+func (s EnvSecrets) Secret(ctx context.Context, name string) (string, error) {
+	v, ok := os.LookupEnv(s.Prefix + name)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrNoSecret, name)
+	}
+	return v, nil
+}
+
+// FileSecrets is a SecretProvider reading each secret from the file of that
+// name in a directory, as mounted by Docker or Kubernetes. A trailing
+// newline is dropped.
+type FileSecrets string
+
+func (dir FileSecrets) Secret(ctx context.Context, name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("%w: %q", ErrNoSecret, name)
+	}
+	b, err := os.ReadFile(filepath.Join(string(dir), name))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: %q", ErrNoSecret, name)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+// Secret resolves the named secret through the SecretProvider of the
+// scheduler, see WithSecrets.
+func (p *Progress) Secret(name string) (string, error) {
+	if p == nil || p.secrets == nil {
+		return "", fmt.Errorf("%w: %q, no SecretProvider", ErrNoSecret, name)
+	}
+	return p.secrets.Secret(p.Context(), name)
+}
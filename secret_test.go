@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileSecrets(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "token"), []byte("s3cret\n"), 0600)
+	secrets := FileSecrets(dir)
+
+	if v, err := secrets.Secret(context.Background(), "token"); err != nil || v != "s3cret" {
+		t.Errorf("expected s3cret, got %q (%v)", v, err)
+	}
+	for _, name := range []string{"missing", "../token", ""} {
+		if _, err := secrets.Secret(context.Background(), name); !errors.Is(err, ErrNoSecret) {
+			t.Errorf("%q: expected ErrNoSecret, got %v", name, err)
+		}
+	}
+}
+
+// A ShellJob gets its secrets resolved at run time, through the provider of
+// the scheduler.
+func TestShellJobSecrets(t *testing.T) {
+	t.Setenv("SECRET_api", "hunter2")
+	cron := New(WithSecrets(EnvSecrets{Prefix: "SECRET_"}))
+	cron.AddProgressJob(time.Now().Add(50*time.Millisecond), time.Hour, ShellJob{
+		Command: "echo token=$API_TOKEN",
+		Env:     []string{"PATH=" + os.Getenv("PATH")},
+		Secrets: map[string]string{"API_TOKEN": "api"},
+	}, "with-secret")
+	cron.AddProgressJob(time.Now().Add(50*time.Millisecond), time.Hour, ShellJob{
+		Command: "echo ran",
+		Secrets: map[string]string{"API_TOKEN": "missing"},
+	}, "missing-secret")
+	cron.Start()
+	defer cron.Stop()
+
+	if out := waitForHistory(t, cron, "with-secret", 1)[0].Output; !strings.Contains(out, "token=hunter2") {
+		t.Errorf("expected the secret in the environment, got %q", out)
+	}
+	r := waitForHistory(t, cron, "missing-secret", 1)[0]
+	if strings.Contains(r.Output, "ran") || !strings.Contains(r.Output, "no such secret") {
+		t.Errorf("expected the run to fail on the missing secret, got %q", r.Output)
+	}
+	if r.Outcome != OutcomeError || !strings.Contains(r.Error, "no such secret") {
+		t.Errorf("expected the run recorded as failed, got %s %q", r.Outcome, r.Error)
+	}
+}
@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"errors"
+	"strconv"
+)
+
+// The last sequence number of each entry is stored under "sequences/" and
+// its name, see RunInfo.Sequence.
+const sequencePrefix = "sequences/"
+
+// loadSequence sets where the numbering of the triggers of entry goes on
+// from: the last number of the entry it replaces, or that stored.
+func (c *Cron) loadSequence(entry, prev *Entry) {
+	if prev != nil {
+		entry.Sequence = prev.Sequence
+	}
+	if c.store == nil {
+		return
+	}
+	data, err := c.store.Get(sequencePrefix + entry.Name)
+	if errors.Is(err, ErrNotFound) {
+		return
+	}
+	if err != nil {
+		c.log(SubsystemLifecycle).Error(err, "loading the sequence number failed", "entry", entry.Name)
+		return
+	}
+	if n, err := strconv.ParseUint(string(data), 10, 64); err == nil && n > entry.Sequence {
+		entry.Sequence = n
+	}
+}
+
+// storeSequence stores n as the last sequence number of the named entry,
+// unless a later one was stored already. It is called as the trigger is
+// dispatched, on the run loop, so the Put of each trigger holds up the
+// others while it lasts. A failed Put is logged, the run isn't held.
+func (c *Cron) storeSequence(name string, n uint64) {
+	if c.store == nil {
+		return
+	}
+	c.seqMu.Lock()
+	defer c.seqMu.Unlock()
+	if c.storedSeqs == nil {
+		c.storedSeqs = make(map[string]uint64)
+	}
+	if n <= c.storedSeqs[name] {
+		return
+	}
+	if err := c.store.Put(sequencePrefix+name, []byte(strconv.FormatUint(n, 10))); err != nil {
+		c.log(SubsystemLifecycle).Error(err, "storing the sequence number failed", "entry", name, "sequence", n)
+		return
+	}
+	c.storedSeqs[name] = n
+}
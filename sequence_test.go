@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSequenceAcrossRestarts(t *testing.T) {
+	store := NewMemoryStore()
+	var seqs []uint64
+	add := func(cron *Cron) {
+		cron.AddProgressFunc(time.Now().Add(time.Hour), time.Hour, func(p *Progress) {
+			seqs = append(seqs, p.Info().Sequence)
+		}, "numbered")
+	}
+	cron := New(WithJobStore(store))
+	add(cron)
+	cron.Start()
+	cron.RunNow("numbered")
+	cron.RunNow("numbered")
+	cron.Stop()
+	if e, _ := cron.Entry("numbered"); e.Sequence != 2 {
+		t.Errorf("expected the entry at sequence 2, got %d", e.Sequence)
+	}
+
+	// A new instance on the store goes on where the last left off.
+	cron = New(WithJobStore(store))
+	add(cron)
+	cron.Start()
+	defer cron.Stop()
+	cron.RunNow("numbered")
+	if len(seqs) != 3 || seqs[0] != 1 || seqs[1] != 2 || seqs[2] != 3 {
+		t.Errorf("expected the sequence numbers 1 2 3, got %v", seqs)
+	}
+}
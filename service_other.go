@@ -0,0 +1,10 @@
+//go:build !windows
+
+package scheduler
+
+import "errors"
+
+// RunService runs c as a Windows service. It is only supported on Windows.
+func RunService(name string, c *Cron, opts ...SignalOption) error {
+	return errors.New("scheduler: Windows services are only supported on Windows")
+}
@@ -0,0 +1,111 @@
+//go:build windows
+
+package scheduler
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	advapi32                          = syscall.NewLazyDLL("advapi32.dll")
+	procStartServiceCtrlDispatcherW   = advapi32.NewProc("StartServiceCtrlDispatcherW")
+	procRegisterServiceCtrlHandlerExW = advapi32.NewProc("RegisterServiceCtrlHandlerExW")
+	procSetServiceStatus              = advapi32.NewProc("SetServiceStatus")
+)
+
+// From winsvc.h.
+const (
+	serviceWin32OwnProcess = 0x10
+
+	serviceStopped      = 1
+	serviceStartPending = 2
+	serviceStopPending  = 3
+	serviceRunning      = 4
+
+	serviceAcceptStop     = 1
+	serviceAcceptShutdown = 4
+
+	serviceControlStop     = 1
+	serviceControlShutdown = 5
+
+	errorServiceSpecificError = 1066
+)
+
+type serviceTableEntry struct {
+	name *uint16
+	proc uintptr
+}
+
+type serviceStatus struct {
+	serviceType             uint32
+	currentState            uint32
+	controlsAccepted        uint32
+	win32ExitCode           uint32
+	serviceSpecificExitCode uint32
+	checkPoint              uint32
+	waitHint                uint32
+}
+
+// RunService runs c as the Windows service of the given name, until the
+// service control manager stops it or the system shuts down, when c is shut
+// down as by RunUntilSignal. It returns once the service is stopped. It
+// must be called from a process started by the service control manager.
+func RunService(name string, c *Cron, opts ...SignalOption) error {
+	cfg := newSignalConfig(opts)
+	namep, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return err
+	}
+	var runErr error
+	main := syscall.NewCallback(func(argc, argv uintptr) uintptr {
+		runErr = runService(name, namep, c, cfg)
+		return 0
+	})
+	table := []serviceTableEntry{{name: namep, proc: main}, {}}
+	// Returns once the service is stopped.
+	if r, _, err := procStartServiceCtrlDispatcherW.Call(uintptr(unsafe.Pointer(&table[0]))); r == 0 {
+		return fmt.Errorf("scheduler: starting the service: %w", err)
+	}
+	return runErr
+}
+
+// runService is the ServiceMain of RunService.
+func runService(name string, namep *uint16, c *Cron, cfg signalConfig) error {
+	stop := make(chan struct{}, 1)
+	handler := syscall.NewCallback(func(control, eventType, eventData, context uintptr) uintptr {
+		switch control {
+		case serviceControlStop, serviceControlShutdown:
+			select {
+			case stop <- struct{}{}:
+			default:
+			}
+		}
+		return 0
+	})
+	h, _, err := procRegisterServiceCtrlHandlerExW.Call(uintptr(unsafe.Pointer(namep)), handler, 0)
+	if h == 0 {
+		return fmt.Errorf("scheduler: registering the service control handler: %w", err)
+	}
+	set := func(s serviceStatus) {
+		s.serviceType = serviceWin32OwnProcess
+		procSetServiceStatus.Call(h, uintptr(unsafe.Pointer(&s)))
+	}
+
+	set(serviceStatus{currentState: serviceStartPending})
+	c.Start()
+	set(serviceStatus{currentState: serviceRunning, controlsAccepted: serviceAcceptStop | serviceAcceptShutdown})
+	<-stop
+	c.log(SubsystemLifecycle).Info("shutting down", "service", name, "timeout", cfg.timeout)
+	set(serviceStatus{currentState: serviceStopPending, waitHint: uint32(cfg.timeout / time.Millisecond)})
+	runErr := cfg.shutdown(c, nil)
+	stopped := serviceStatus{currentState: serviceStopped}
+	if runErr != nil {
+		stopped.win32ExitCode = errorServiceSpecificError
+		stopped.serviceSpecificExitCode = 1
+	}
+	set(stopped)
+	return runErr
+}
@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ShellJob runs a command through "sh -c". Its stdout and stderr end up in
+// the run history. A command that can't be started, exits non-zero or times
+// out fails the run, as OutcomeError. Add it with AddProgressJob.
+//
+// By default the command runs like the scheduler itself does; the other
+// fields restrict it, so scheduled commands can run with least privilege.
+type ShellJob struct {
+	Command string
+
+	// Working directory of the command. Empty means the scheduler's.
+	Dir string
+
+	// Env sets variables for the command, as "KEY=value". PassEnv lists the
+	// variables of the scheduler's environment the command gets. If either
+	// is set the command gets nothing else; otherwise it inherits the whole
+	// environment of the scheduler.
+	Env     []string
+	PassEnv []string
+
+	// Secrets sets variables of the command to secrets, resolved at each run
+	// through the SecretProvider of the scheduler: {"API_TOKEN": "api-token"}
+	// sets $API_TOKEN to the secret "api-token". A run whose secrets can't
+	// be resolved fails without running the command.
+	Secrets map[string]string
+
+	// If non-zero, the umask the command runs with, as in 0027.
+	Umask os.FileMode
+
+	// If set, the directory to chroot into and the user to run as. These
+	// need the privileges to do so, and are only supported on Unix.
+	Chroot string
+	User   *ShellUser
+
+	// If non-zero, how long the command may run before it is cancelled.
+	Timeout time.Duration
+
+	// How long a cancelled command, by Timeout or because the run was
+	// cancelled, has between SIGTERM and SIGKILL. Both go to the whole
+	// process group of the command, so whatever it spawned goes with it. The
+	// default is 5s. On Windows the command is killed straight away.
+	KillGrace time.Duration
+}
+
+// The default ShellJob.KillGrace.
+const defaultKillGrace = 5 * time.Second
+
+// ShellUser is the user and group a ShellJob runs as.
+type ShellUser struct {
+	UID, GID uint32
+}
+
+func (j ShellJob) Run(p *Progress) {
+	ctx := p.Context()
+	if j.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.Timeout)
+		defer cancel()
+	}
+	err := j.command(ctx, p).Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %v: %w", j.Timeout, ctx.Err())
+	}
+	if err != nil {
+		fmt.Fprintln(p.Output(), err)
+		p.fail(err)
+	}
+}
+
+// command returns the command to run for p, cancelled along with ctx.
+func (j ShellJob) command(ctx context.Context, p *Progress) *exec.Cmd {
+	script := j.Command
+	if j.Umask != 0 {
+		script = fmt.Sprintf("umask %04o; %s", j.Umask&os.ModePerm, script)
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", script)
+	cmd.Stdout = p.Output()
+	cmd.Stderr = p.Output()
+	cmd.Dir = j.Dir
+	cmd.Env = j.environ()
+	attr, err := j.sysProcAttr()
+	if err != nil {
+		cmd.Err = err
+	}
+	cmd.SysProcAttr = attr
+	grace := j.KillGrace
+	if grace <= 0 {
+		grace = defaultKillGrace
+	}
+	killGroup(cmd, grace)
+	if len(j.Secrets) > 0 {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		names := make([]string, 0, len(j.Secrets))
+		for name := range j.Secrets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			v, err := p.Secret(j.Secrets[name])
+			if err != nil {
+				cmd.Err = err
+				break
+			}
+			cmd.Env = append(cmd.Env, name+"="+v)
+		}
+	}
+	return cmd
+}
+
+// environ returns the environment of the command, nil meaning the
+// scheduler's.
+func (j ShellJob) environ() []string {
+	if j.Env == nil && j.PassEnv == nil {
+		return nil
+	}
+	env := []string{}
+	for _, kv := range os.Environ() {
+		name := kv[:strings.IndexByte(kv+"=", '=')]
+		for _, pass := range j.PassEnv {
+			if name == pass {
+				env = append(env, kv)
+				break
+			}
+		}
+	}
+	return append(env, j.Env...)
+}
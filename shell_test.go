@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func runShell(t *testing.T, j ShellJob) string {
+	p := newProgress()
+	p.start(context.Background())
+	defer p.cancel()
+	j.Run(p)
+	out, _ := p.output.contents()
+	return out
+}
+
+func TestShellJobEnvironment(t *testing.T) {
+	t.Setenv("SCHEDULER_PASSED", "yes")
+	t.Setenv("SCHEDULER_SECRET", "hunter2")
+
+	out := runShell(t, ShellJob{Command: "env"})
+	if !strings.Contains(out, "SCHEDULER_SECRET=hunter2") {
+		t.Error("expected the whole environment to be inherited by default")
+	}
+
+	out = runShell(t, ShellJob{
+		Command: "env",
+		Env:     []string{"ONLY=this"},
+		PassEnv: []string{"SCHEDULER_PASSED"},
+	})
+	if !strings.Contains(out, "ONLY=this") || !strings.Contains(out, "SCHEDULER_PASSED=yes") {
+		t.Errorf("expected the allowed variables, got %q", out)
+	}
+	if strings.Contains(out, "SCHEDULER_SECRET") {
+		t.Errorf("expected other variables to be dropped, got %q", out)
+	}
+}
+
+func TestShellJobDirAndUmask(t *testing.T) {
+	dir := t.TempDir()
+	out := runShell(t, ShellJob{Command: "pwd; umask", Dir: dir, Umask: 0027})
+	lines := strings.Fields(out)
+	if len(lines) != 2 {
+		t.Fatalf("unexpected output %q", out)
+	}
+	if got, _ := os.Stat(lines[0]); got == nil || !os.SameFile(got, mustStat(t, dir)) {
+		t.Errorf("expected to run in %s, got %s", dir, lines[0])
+	}
+	if lines[1] != "0027" {
+		t.Errorf("expected umask 0027, got %s", lines[1])
+	}
+}
+
+func mustStat(t *testing.T, path string) os.FileInfo {
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fi
+}
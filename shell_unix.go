@@ -0,0 +1,32 @@
+//go:build !windows
+
+package scheduler
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+func (j ShellJob) sysProcAttr() (*syscall.SysProcAttr, error) {
+	// The command leads a process group of its own, for killGroup.
+	attr := &syscall.SysProcAttr{Chroot: j.Chroot, Setpgid: true}
+	if j.User != nil {
+		attr.Credential = &syscall.Credential{Uid: j.User.UID, Gid: j.User.GID}
+	}
+	return attr, nil
+}
+
+// killGroup makes cancelling cmd send SIGTERM to its process group, then
+// SIGKILL once grace is over to whatever is left of it.
+func killGroup(cmd *exec.Cmd, grace time.Duration) {
+	cmd.Cancel = func() error {
+		pgid := -cmd.Process.Pid
+		time.AfterFunc(grace, func() {
+			syscall.Kill(pgid, syscall.SIGKILL)
+		})
+		return syscall.Kill(pgid, syscall.SIGTERM)
+	}
+	// Wait gives up on the output of whatever holds on to it past the kill.
+	cmd.WaitDelay = grace + time.Second
+}
@@ -0,0 +1,45 @@
+//go:build !windows
+
+package scheduler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// A command that times out is killed along with what it spawned, even if it
+// ignores SIGTERM.
+func TestShellJobTimeoutKillsGroup(t *testing.T) {
+	leaked := filepath.Join(t.TempDir(), "leaked")
+	cron := New()
+	cron.AddProgressJob(time.Now().Add(time.Hour), time.Hour, ShellJob{
+		Command:   "trap '' TERM; (sleep 1; touch " + leaked + ") & wait",
+		Timeout:   200 * time.Millisecond,
+		KillGrace: 200 * time.Millisecond,
+	}, "slow")
+	cron.Start()
+	defer cron.Stop()
+
+	start := time.Now()
+	r, err := cron.RunNow("slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("expected the command killed after its grace, took %v", d)
+	}
+	if !strings.Contains(r.Output, "timed out after 200ms") {
+		t.Errorf("expected the timeout in the output, got %q", r.Output)
+	}
+	if r.Outcome != OutcomeError || !strings.Contains(r.Error, context.DeadlineExceeded.Error()) {
+		t.Errorf("expected the run to fail on the timeout, got %s %q", r.Outcome, r.Error)
+	}
+	time.Sleep(time.Second)
+	if _, err := os.Stat(leaked); err == nil {
+		t.Error("the child of the command outlived it")
+	}
+}
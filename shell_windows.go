@@ -0,0 +1,23 @@
+//go:build windows
+
+package scheduler
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+func (j ShellJob) sysProcAttr() (*syscall.SysProcAttr, error) {
+	if j.Chroot != "" || j.User != nil {
+		return nil, errors.New("scheduler: ShellJob.Chroot and ShellJob.User are not supported on Windows")
+	}
+	return nil, nil
+}
+
+// killGroup leaves cmd to be killed when cancelled, there being no process
+// group to signal, but bounds how long Wait holds on to its output.
+func killGroup(cmd *exec.Cmd, grace time.Duration) {
+	cmd.WaitDelay = grace
+}
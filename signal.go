@@ -0,0 +1,160 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// SignalOption configures RunUntilSignal.
+type SignalOption func(*signalConfig)
+
+type signalConfig struct {
+	signals []os.Signal
+	timeout time.Duration
+	handoff bool
+	systemd bool
+}
+
+// newSignalConfig returns the defaults, modified by the given options.
+func newSignalConfig(opts []SignalOption) signalConfig {
+	cfg := signalConfig{
+		signals: []os.Signal{os.Interrupt, syscall.SIGTERM},
+		timeout: 30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithSignals sets the signals RunUntilSignal shuts down on. The default is
+// SIGINT and SIGTERM.
+func WithSignals(signals ...os.Signal) SignalOption {
+	return func(cfg *signalConfig) {
+		cfg.signals = signals
+	}
+}
+
+// WithDrainTimeout sets how long RunUntilSignal waits for the runs in
+// flight to finish. The default is 30 seconds.
+func WithDrainTimeout(d time.Duration) SignalOption {
+	return func(cfg *signalConfig) {
+		cfg.timeout = d
+	}
+}
+
+// WithHandoff makes RunUntilSignal shut down with Drain, handing the
+// scheduler over to the instance taking over, see Takeover.
+func WithHandoff() SignalOption {
+	return func(cfg *signalConfig) {
+		cfg.handoff = true
+	}
+}
+
+// RunUntilSignal starts c, blocks until the process gets SIGINT or SIGTERM,
+// and shuts c down: no new runs start, and the runs in flight get the drain
+// timeout to finish. A second signal cuts the wait short. The runs still
+// going then have their contexts cancelled, and the error of the wait is
+// returned. It returns nil if every run finished in time.
+func RunUntilSignal(c *Cron, opts ...SignalOption) error {
+	cfg := newSignalConfig(opts)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, cfg.signals...)
+	defer signal.Stop(signals)
+
+	stopNotify := cfg.start(c)
+	sig := <-signals
+	c.log(SubsystemLifecycle).Info("shutting down", "signal", sig, "timeout", cfg.timeout)
+	stopNotify()
+	return cfg.shutdown(c, signals)
+}
+
+// start starts c, and tells systemd it is ready if asked to. It returns a
+// func that tells systemd it is stopping.
+func (cfg signalConfig) start(c *Cron) (stopping func()) {
+	c.Start()
+	if !cfg.systemd {
+		return func() {}
+	}
+	return c.notifySystemd()
+}
+
+// shutdown shuts c down as RunUntilSignal does. Anything received on abort
+// cuts the wait for the runs short.
+func (cfg signalConfig) shutdown(c *Cron, abort <-chan os.Signal) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.timeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-abort:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var err error
+	if cfg.handoff {
+		err = c.Drain(ctx)
+	} else {
+		c.Stop()
+		err = c.waitRuns(ctx)
+	}
+	if ctx.Err() != nil {
+		c.cancelRuns()
+	}
+	return err
+}
+
+// Shutdown stops the scheduler, cancels the contexts of the runs in flight,
+// as passed to JobCtx and ProgressJob, and waits for the runs to return or
+// ctx to be done, and returns ctx's error in the latter case. Unlike
+// RunUntilSignal, it doesn't give the runs a grace period before cancelling
+// them.
+func (c *Cron) Shutdown(ctx context.Context) error {
+	c.Stop()
+	c.mu.Lock()
+	if c.runCancel != nil {
+		c.runCancel()
+	}
+	c.mu.Unlock()
+	return c.waitRuns(ctx)
+}
+
+// runContext returns the context the runs derive theirs from, a new one
+// once the scheduler starts again after Shutdown.
+func (c *Cron) runContext() context.Context {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.runCtx == nil {
+		c.runCtx, c.runCancel = context.WithCancel(context.Background())
+	}
+	return c.runCtx
+}
+
+// waitRuns waits for the runs in flight to finish or ctx to be done, and
+// returns ctx's error in the latter case.
+func (c *Cron) waitRuns(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		c.runs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// cancelRuns cancels the contexts of the runs in flight.
+func (c *Cron) cancelRuns() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for p := range c.going {
+		p.cancel()
+	}
+}
@@ -0,0 +1,70 @@
+//go:build !windows
+
+package scheduler
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// runUntilSignal runs RunUntilSignal on cron, sends SIGTERM once started
+// has been closed, and returns what it returned.
+func runUntilSignal(t *testing.T, cron *Cron, started <-chan struct{}, opts ...SignalOption) error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- RunUntilSignal(cron, opts...) }()
+	select {
+	case <-started:
+	case <-time.After(2 * ONE_SECOND):
+		t.Fatal("job did not start")
+	}
+	syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(2 * ONE_SECOND):
+		t.Fatal("RunUntilSignal did not return")
+		return nil
+	}
+}
+
+func TestRunUntilSignalWaitsForRuns(t *testing.T) {
+	cron := New()
+	started := make(chan struct{})
+	finished := false
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		finished = true
+	}, "slow")
+
+	if err := runUntilSignal(t, cron, started); err != nil {
+		t.Fatal(err)
+	}
+	if !finished {
+		t.Error("expected the run in flight to finish first")
+	}
+}
+
+func TestRunUntilSignalDrainTimeout(t *testing.T) {
+	cron := New()
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	cron.AddProgressFunc(time.Now().Add(50*time.Millisecond), time.Hour, func(p *Progress) {
+		close(started)
+		<-p.Context().Done()
+		close(cancelled)
+	}, "stuck")
+
+	err := runUntilSignal(t, cron, started, WithDrainTimeout(100*time.Millisecond))
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected the drain to time out, got %v", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(ONE_SECOND):
+		t.Error("expected the run still going to be cancelled")
+	}
+}
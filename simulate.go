@@ -0,0 +1,58 @@
+package scheduler
+
+import "time"
+
+// Simulate returns the times in [from, to) a schedule would fire at in a
+// scheduler working in loc, without running anything. It lets tests check
+// how a schedule definition behaves across DST transitions and leap days:
+// intervals are absolute durations, so a daily entry drifts by an hour of
+// wall time over a transition, while its preferred window stays on the wall
+// clock. Like Backfill, it returns at most 10000 times.
+func Simulate(loc *time.Location, startTime time.Time, Interval time.Duration, from, to time.Time, opts ...EntryOption) []time.Time {
+	e := &Entry{setStartTime: startTime, Interval: Interval, location: loc}
+	for _, opt := range opts {
+		opt(e)
+	}
+	nominal := e.occurrences(from, to)
+	if Interval <= 0 && !startTime.Before(from) && startTime.Before(to) {
+		nominal = []time.Time{startTime}
+	}
+	times := make([]time.Time, len(nominal))
+	for i, t := range nominal {
+		times[i] = e.place(t)
+	}
+	return times
+}
+
+// ZoneTransitions returns the times in [from, to) the UTC offset of loc
+// changes at, such as the DST spring-forward and fall-back, to pick the
+// ranges to Simulate over.
+func ZoneTransitions(loc *time.Location, from, to time.Time) []time.Time {
+	var transitions []time.Time
+	offset := func(t time.Time) int {
+		_, off := t.In(loc).Zone()
+		return off
+	}
+	// Offsets don't change more than once within an hour, so step by hours
+	// and search each step that changes for the exact instant.
+	for t := from; t.Before(to); t = t.Add(time.Hour) {
+		next := t.Add(time.Hour)
+		if next.After(to) {
+			next = to
+		}
+		if offset(t) == offset(next) {
+			continue
+		}
+		lo, hi := t, next
+		for hi.Sub(lo) > time.Second {
+			mid := lo.Add(hi.Sub(lo) / 2)
+			if offset(mid) == offset(lo) {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		transitions = append(transitions, hi.Truncate(time.Second))
+	}
+	return transitions
+}
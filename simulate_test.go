@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func newYork(t *testing.T) *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+	return loc
+}
+
+func TestZoneTransitions(t *testing.T) {
+	loc := newYork(t)
+	year := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	got := ZoneTransitions(loc, year, year.AddDate(1, 0, 0))
+	want := []time.Time{
+		time.Date(2030, 3, 10, 7, 0, 0, 0, time.UTC),
+		time.Date(2030, 11, 3, 6, 0, 0, 0, time.UTC),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("expected %v, got %v", want[i], got[i])
+		}
+	}
+}
+
+// A daily interval keeps its 24 hours across spring-forward, so its wall
+// time moves by an hour, while a preferred window stays on the wall clock.
+func TestSimulateSpringForward(t *testing.T) {
+	loc := newYork(t)
+	start := time.Date(2030, 3, 9, 12, 0, 0, 0, loc)
+	from, to := start, start.AddDate(0, 0, 3)
+
+	times := Simulate(loc, start, 24*time.Hour, from, to)
+	if len(times) != 3 {
+		t.Fatalf("expected 3 fire times, got %v", times)
+	}
+	if times[0].Hour() != 12 || times[1].Hour() != 13 || times[2].Hour() != 13 {
+		t.Errorf("expected the wall time to move an hour, got %v", times)
+	}
+
+	windowed := Simulate(loc, start, 24*time.Hour, from, to, WithPreferredWindow(18*time.Hour, 19*time.Hour, 12*time.Hour))
+	for _, ft := range windowed {
+		if ft.Hour() != 18 {
+			t.Errorf("expected the window to hold at 18:00 wall time, got %v", ft)
+		}
+	}
+}
+
+func TestSimulateLeapDay(t *testing.T) {
+	start := time.Date(2028, 2, 27, 9, 0, 0, 0, time.UTC)
+	times := Simulate(time.UTC, start, 24*time.Hour, start, start.AddDate(0, 0, 3))
+	if len(times) != 3 || times[2].Month() != time.February || times[2].Day() != 29 {
+		t.Errorf("expected to fire on the leap day, got %v", times)
+	}
+
+	once := Simulate(time.UTC, start, 0, start, start.Add(time.Hour))
+	if len(once) != 1 || !once[0].Equal(start) {
+		t.Errorf("expected a one-shot to fire once, got %v", once)
+	}
+}
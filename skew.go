@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+// TriggerMessage describes a run for a remote worker, for jobs that hand
+// their runs over to workers through a queue. See Progress.Trigger.
+type TriggerMessage struct {
+	Name      string    `json:"name"`
+	Version   int       `json:"version"`
+	Attempt   int       `json:"attempt"`
+	Sequence  uint64    `json:"sequence,omitempty"`
+	Scheduled time.Time `json:"scheduled"`
+
+	// The clock of the scheduler when the message was made.
+	Sent time.Time `json:"sent"`
+}
+
+// Trigger returns the message describing the current run to a remote
+// worker, stamped with the clock of the scheduler.
+func (p *Progress) Trigger() TriggerMessage {
+	if p == nil {
+		return TriggerMessage{Sent: time.Now()}
+	}
+	m := p.trigger
+	m.Sent = time.Now()
+	return m
+}
+
+// Delivery is how late a TriggerMessage reached a worker, see SkewDetector.
+type Delivery struct {
+	// How late the scheduler sent the message, by its own clock.
+	Dispatch time.Duration
+
+	// How long the message took to arrive, once the skew is taken out.
+	Transit time.Duration
+
+	// The estimated offset of the worker's clock from the scheduler's.
+	Skew time.Duration
+}
+
+// How many messages SkewDetector estimates the skew over.
+const skewWindow = 64
+
+// SkewDetector runs on a worker and tells late delivery of trigger messages
+// apart from clock skew. It estimates the skew as the smallest difference
+// between the receive and send times seen over the recent messages: the one
+// that took the least time in transit. A message taking longer than that is
+// late in delivery. How late the worker itself starts the run is then the
+// time from receiving the message to starting it.
+type SkewDetector struct {
+	mu      sync.Mutex
+	offsets []time.Duration
+	next    int
+}
+
+// Observe accounts for m, received at the given time by the worker's clock.
+func (d *SkewDetector) Observe(m TriggerMessage, received time.Time) Delivery {
+	offset := received.Sub(m.Sent)
+
+	d.mu.Lock()
+	if len(d.offsets) < skewWindow {
+		d.offsets = append(d.offsets, offset)
+	} else {
+		d.offsets[d.next] = offset
+		d.next = (d.next + 1) % skewWindow
+	}
+	skew := offset
+	for _, o := range d.offsets {
+		if o < skew {
+			skew = o
+		}
+	}
+	d.mu.Unlock()
+
+	return Delivery{
+		Dispatch: m.Sent.Sub(m.Scheduled),
+		Transit:  offset - skew,
+		Skew:     skew,
+	}
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSkewDetector(t *testing.T) {
+	var d SkewDetector
+	scheduled := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	// The worker's clock is 2s ahead, and messages take at least 10ms.
+	skew := 2 * time.Second
+	send := func(dispatch, transit time.Duration) Delivery {
+		m := TriggerMessage{Name: "job", Scheduled: scheduled, Sent: scheduled.Add(dispatch)}
+		return d.Observe(m, m.Sent.Add(transit+skew))
+	}
+
+	send(0, 10*time.Millisecond)
+	send(0, 30*time.Millisecond)
+	got := send(time.Second, 500*time.Millisecond)
+	if got.Skew != skew+10*time.Millisecond {
+		t.Errorf("expected the skew estimate from the quickest message, got %v", got.Skew)
+	}
+	if got.Transit != 490*time.Millisecond {
+		t.Errorf("expected the late delivery to show in transit, got %v", got.Transit)
+	}
+	if got.Dispatch != time.Second {
+		t.Errorf("expected the late dispatch apart, got %v", got.Dispatch)
+	}
+}
+
+func TestProgressTrigger(t *testing.T) {
+	cron := New()
+	messages := make(chan TriggerMessage, 1)
+	start := time.Now().Add(50 * time.Millisecond)
+	cron.AddProgressFunc(start, time.Hour, func(p *Progress) {
+		messages <- p.Trigger()
+	}, "remote")
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case m := <-messages:
+		if m.Name != "remote" || m.Version != 1 || m.Attempt != 1 || !m.Scheduled.Equal(start) || m.Sent.Before(start) {
+			t.Errorf("unexpected message %+v", m)
+		}
+	case <-time.After(ONE_SECOND):
+		t.Fatal("job did not run")
+	}
+}
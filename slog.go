@@ -0,0 +1,143 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Subsystem is a part of the scheduler whose messages a logger may log at a
+// level of their own, see SlogLogger.
+type Subsystem string
+
+const (
+	// Decisions about triggers: runs dispatched, queued, smeared, dropped
+	// or skipped.
+	SubsystemDispatch Subsystem = "dispatch"
+
+	// The scheduler and its entries: starting and stopping, entries
+	// replaced, canaries promoted or rolled back, maintenance windows.
+	SubsystemLifecycle Subsystem = "lifecycle"
+
+	// How the runs end, and what the jobs log themselves.
+	SubsystemJobs Subsystem = "jobs"
+)
+
+// SlogLevels are the lowest levels SlogLogger logs for each subsystem. A nil
+// level is slog.LevelInfo. Pass a *slog.LevelVar to change a level while the
+// scheduler runs, for example to trace dispatching for a while:
+//
+//	dispatch := new(slog.LevelVar)
+//	c := New(WithLogger(SlogLogger(slog.Default(), SlogLevels{Dispatch: dispatch})))
+//	...
+//	dispatch.Set(slog.LevelDebug)
+type SlogLevels struct {
+	Dispatch, Lifecycle, Jobs slog.Leveler
+}
+
+// SlogLogger returns a Logger writing to l. Each message gets a "subsystem"
+// attribute, and is logged if it is at or above the level of its subsystem,
+// whatever the level of the handler of l. Only loggers made by SlogLogger
+// get the debug messages of the scheduler, which trace each decision.
+func SlogLogger(l *slog.Logger, levels SlogLevels) Logger {
+	return slogLogger{logger: l, levels: levels, level: levels.Jobs}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+	levels SlogLevels
+
+	// The subsystem the messages are about, if any, and its level.
+	subsystem Subsystem
+	level     slog.Leveler
+}
+
+// forSubsystem implements subsystemLogger.
+func (sl slogLogger) forSubsystem(s Subsystem) Logger {
+	sl.subsystem = s
+	switch s {
+	case SubsystemDispatch:
+		sl.level = sl.levels.Dispatch
+	case SubsystemLifecycle:
+		sl.level = sl.levels.Lifecycle
+	default:
+		sl.level = sl.levels.Jobs
+	}
+	return sl
+}
+
+func (sl slogLogger) Debug(msg string, keysAndValues ...interface{}) {
+	sl.log(slog.LevelDebug, msg, keysAndValues)
+}
+
+func (sl slogLogger) Warn(msg string, keysAndValues ...interface{}) {
+	sl.log(slog.LevelWarn, msg, keysAndValues)
+}
+
+func (sl slogLogger) Info(msg string, keysAndValues ...interface{}) {
+	sl.log(slog.LevelInfo, msg, keysAndValues)
+}
+
+func (sl slogLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	sl.log(slog.LevelError, msg, append([]interface{}{"error", err}, keysAndValues...))
+}
+
+func (sl slogLogger) log(level slog.Level, msg string, keysAndValues []interface{}) {
+	min := slog.LevelInfo
+	if sl.level != nil {
+		min = sl.level.Level()
+	}
+	if level < min {
+		return
+	}
+	r := slog.NewRecord(time.Now(), level, msg, 0)
+	if sl.subsystem != "" {
+		r.AddAttrs(slog.String("subsystem", string(sl.subsystem)))
+	}
+	r.Add(keysAndValues...)
+	// The level of the subsystem decides, not that of the handler.
+	_ = sl.logger.Handler().Handle(context.Background(), r)
+}
+
+// subsystemLogger is implemented by the loggers that treat the subsystems
+// apart, such as SlogLogger.
+type subsystemLogger interface {
+	forSubsystem(Subsystem) Logger
+}
+
+// debugLogger is implemented by the loggers that take debug messages.
+type debugLogger interface {
+	Debug(msg string, keysAndValues ...interface{})
+}
+
+// warnLogger is implemented by the loggers that take warnings apart from
+// routine messages.
+type warnLogger interface {
+	Warn(msg string, keysAndValues ...interface{})
+}
+
+// log returns the logger for the messages of the subsystem.
+func (c *Cron) log(s Subsystem) Logger {
+	if sl, ok := c.logger.(subsystemLogger); ok {
+		return sl.forSubsystem(s)
+	}
+	return c.logger
+}
+
+// debug logs a debug message of the subsystem, if the logger takes those.
+func (c *Cron) debug(s Subsystem, msg string, keysAndValues ...interface{}) {
+	if dl, ok := c.log(s).(debugLogger); ok {
+		dl.Debug(msg, keysAndValues...)
+	}
+}
+
+// warn logs a warning of the subsystem, as a routine message if the logger
+// doesn't take warnings.
+func (c *Cron) warn(s Subsystem, msg string, keysAndValues ...interface{}) {
+	l := c.log(s)
+	if wl, ok := l.(warnLogger); ok {
+		wl.Warn(msg, keysAndValues...)
+		return
+	}
+	l.Info(msg, keysAndValues...)
+}
@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlogLoggerLevelsPerSubsystem(t *testing.T) {
+	var out syncBuffer
+	handler := slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelWarn})
+	dispatch := new(slog.LevelVar)
+	cron := New(WithLogger(SlogLogger(slog.New(handler), SlogLevels{Dispatch: dispatch})))
+	cron.AddProgressFunc(time.Now().Add(50*time.Millisecond), time.Hour, func(p *Progress) {
+		p.Logger().Info("copied rows", "rows", 42)
+	}, "etl")
+	cron.Start()
+	defer cron.Stop()
+
+	waitForHistory(t, cron, "etl", 1)
+	got := out.String()
+	if !strings.Contains(got, `msg="copied rows" subsystem=jobs entry=etl`) || !strings.Contains(got, "rows=42") {
+		t.Errorf("expected the job's message at info, whatever the handler's level, got\n%s", got)
+	}
+	if strings.Contains(got, "run dispatched") {
+		t.Errorf("expected no dispatch traces at info, got\n%s", got)
+	}
+
+	dispatch.Set(slog.LevelDebug)
+	if _, err := cron.RunNow("etl"); err != nil {
+		t.Fatal(err)
+	}
+	got = out.String()
+	if !strings.Contains(got, `level=DEBUG msg="run dispatched" subsystem=dispatch entry=etl`) {
+		t.Errorf("expected dispatch traces once enabled, got\n%s", got)
+	}
+	if strings.Contains(got, `msg="run over"`) {
+		t.Errorf("expected no debug messages of the jobs, got\n%s", got)
+	}
+}
@@ -0,0 +1,143 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+)
+
+// Source triggers the runs of an entry on something other than the time,
+// such as a message arriving or a file changing, see WithSource. Runs
+// triggered by a source go through the same policies as those of the
+// schedule: overlap, retries, budgets, maintenance windows, and are recorded
+// and reported the same, with RunRecord.Sourced set.
+type Source interface {
+	// Watch calls fire each time the entry should run, until ctx is done.
+	// It is called in a goroutine of its own while the scheduler runs. An
+	// error it returns is logged, and the source is not watched again until
+	// the scheduler is restarted.
+	Watch(ctx context.Context, fire func()) error
+}
+
+// SourceFunc is a Source calling itself.
+type SourceFunc func(ctx context.Context, fire func()) error
+
+func (f SourceFunc) Watch(ctx context.Context, fire func()) error { return f(ctx, fire) }
+
+// ChanSource triggers a run for each value received on ch, until it is
+// closed.
+func ChanSource(ch <-chan struct{}) Source {
+	return SourceFunc(func(ctx context.Context, fire func()) error {
+		for {
+			select {
+			case _, ok := <-ch:
+				if !ok {
+					return nil
+				}
+				fire()
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	})
+}
+
+// FileSource triggers a run when the file at path is created or changes,
+// looking at its size and modification time every poll. A file that is
+// there already when the scheduler starts doesn't trigger a run until it
+// changes.
+func FileSource(path string, poll time.Duration) Source {
+	return SourceFunc(func(ctx context.Context, fire func()) error {
+		stat := func() (os.FileInfo, error) {
+			fi, err := os.Stat(path)
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, nil
+			}
+			return fi, err
+		}
+		last, err := stat()
+		if err != nil {
+			return err
+		}
+		ticker := time.NewTicker(poll)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return nil
+			}
+			fi, err := stat()
+			if err != nil {
+				return err
+			}
+			if fi != nil && (last == nil || fi.Size() != last.Size() || !fi.ModTime().Equal(last.ModTime())) {
+				fire()
+			}
+			last = fi
+		}
+	})
+}
+
+// WithSource triggers runs of the entry from the given sources, on top of
+// its schedule. An entry run by its sources only is put on Never:
+//
+//	c.AddFuncOn(Never, ingest, "ingest", WithSource(FileSource("/data/drop", time.Second)))
+func WithSource(sources ...Source) EntryOption {
+	return func(e *Entry) {
+		e.Sources = append(e.Sources, sources...)
+	}
+}
+
+// Never is a Schedule that never fires, for entries that only run from
+// their sources or through RunNow.
+var Never Schedule = never{}
+
+type never struct{}
+
+func (never) Next(time.Time) time.Time { return time.Time{} }
+
+// watchSources starts watching the sources of e. It is called from the run
+// loop, or by Start before the loop starts.
+func (c *Cron) watchSources(e *Entry) {
+	if len(e.Sources) == 0 || e.unwatch != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	e.unwatch = cancel
+	for _, s := range e.Sources {
+		go func(s Source) {
+			err := s.Watch(ctx, func() { c.fireSource(ctx, e) })
+			if err != nil && ctx.Err() == nil {
+				c.log(SubsystemDispatch).Error(err, "watching a source failed", "entry", e.Name)
+			}
+		}(s)
+	}
+}
+
+// unwatchSources stops watching the sources of e. It is called from the run
+// loop.
+func (e *Entry) unwatchSources() {
+	if e.unwatch != nil {
+		e.unwatch()
+		e.unwatch = nil
+	}
+}
+
+// fireSource dispatches a run of e triggered by one of its sources. It hands
+// the run to the loop without waiting for it, and gives up once ctx is done,
+// as the loop may be gone.
+func (c *Cron) fireSource(ctx context.Context, e *Entry) {
+	f := func() {
+		if c.lookup(e.Name) != e || ctx.Err() != nil {
+			return
+		}
+		c.debug(SubsystemDispatch, "source fired", "entry", e.Name)
+		c.dispatchTrigger(e, trigger{scheduled: c.clock.Now(), sourced: true})
+	}
+	select {
+	case c.do <- f:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestChanSource(t *testing.T) {
+	ch := make(chan struct{}, 1)
+	cron := New()
+	cron.AddFuncOn(Never, func() {}, "ingest", WithSource(ChanSource(ch)))
+	cron.Start()
+
+	ch <- struct{}{}
+	waitForHistory(t, cron, "ingest", 1)
+	ch <- struct{}{}
+	runs := waitForHistory(t, cron, "ingest", 2)
+	for _, r := range runs {
+		if !r.Sourced || r.Manual {
+			t.Errorf("expected a sourced run, got %+v", r)
+		}
+	}
+
+	// Stopped, the scheduler no longer listens.
+	cron.Stop()
+	time.Sleep(20 * time.Millisecond)
+	ch <- struct{}{}
+	time.Sleep(50 * time.Millisecond)
+	if len(ch) != 1 {
+		t.Error("expected the source not to be watched once stopped")
+	}
+	if n := len(cron.History("ingest")); n != 2 {
+		t.Errorf("expected 2 runs, got %d", n)
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "drop")
+	cron := New()
+	cron.AddFuncOn(Never, func() {}, "watcher", WithSource(FileSource(path, 10*time.Millisecond)))
+	cron.Start()
+	defer cron.Stop()
+
+	time.Sleep(30 * time.Millisecond)
+	if err := os.WriteFile(path, []byte("data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	waitForHistory(t, cron, "watcher", 1)
+	time.Sleep(50 * time.Millisecond)
+	if n := len(cron.History("watcher")); n != 1 {
+		t.Errorf("expected one run for one change, got %d", n)
+	}
+}
+
+func TestSourceStopsWithEntry(t *testing.T) {
+	ch := make(chan struct{}, 1)
+	cron := New()
+	cron.AddFuncOn(Never, func() {}, "ingest", WithSource(ChanSource(ch)))
+	cron.Start()
+	defer cron.Stop()
+
+	cron.RemoveJob("ingest")
+	time.Sleep(20 * time.Millisecond)
+	ch <- struct{}{}
+	time.Sleep(50 * time.Millisecond)
+	if len(ch) != 1 {
+		t.Error("expected the source of a removed entry not to be watched")
+	}
+}
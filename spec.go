@@ -0,0 +1,155 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseSchedule parses a schedule spec, as Schedules print themselves and
+// the admin API takes them:
+//
+//	daily 02:30             Daily(2, 30, 0)
+//	daily 02:30:15          Daily(2, 30, 15)
+//	weekly monday 09:00     Weekly(time.Monday, 9, 0)
+//	never                   Never
+//	0 3 * * 1-5             ParseCron("0 3 * * 1-5")
+//	@daily                  ParseCron("@daily")
+//	@every 90s              Every(90 * time.Second)
+//	CRON_TZ=UTC 0 3 * * *   ParseCron("CRON_TZ=UTC 0 3 * * *")
+//	quarter-end 17:00       LastBusinessDayOfQuarter(FiscalCalendar{}, 17, 0)
+//	quarter-end 17:00 fiscal april except 2026-06-30
+//	                        LastBusinessDayOfQuarter(FiscalCalendar{StartMonth: time.April, Holidays: ...}, 17, 0)
+//	fiscal-year-start 06:00 fiscal july
+//	                        FirstDayOfFiscalYear(FiscalCalendar{StartMonth: time.July}, 6, 0)
+//
+// Several specs separated by commas are merged, see Merge. The commas of
+// the lists of a crontab expression are told apart by not being followed by
+// a space.
+func ParseSchedule(spec string) (Schedule, error) {
+	return parseSchedule(spec, CronStandard)
+}
+
+// parseSchedule is ParseSchedule, with crontab expressions in format.
+func parseSchedule(spec string, format CronFormat) (Schedule, error) {
+	parts := splitSpecs(spec)
+	if len(parts) > 1 {
+		var m merged
+		for _, part := range parts {
+			s, err := parseSchedule(part, format)
+			if err != nil {
+				return nil, err
+			}
+			m = append(m, s)
+		}
+		return m, nil
+	}
+	fields := strings.Fields(strings.ToLower(spec))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("scheduler: bad schedule %q", spec)
+	}
+	// The keywords first, as their specs may have as many fields as a
+	// crontab expression.
+	switch fields[0] {
+	case "never":
+		if len(fields) == 1 {
+			return Never, nil
+		}
+	case "daily":
+		if len(fields) != 2 {
+			break
+		}
+		hh, mm, ss, err := parseTimeOfDay(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("scheduler: bad schedule %q: %w", spec, err)
+		}
+		return Daily(hh, mm, ss), nil
+	case "weekly":
+		if len(fields) != 3 {
+			break
+		}
+		day, ok := weekdays[fields[1]]
+		if !ok {
+			return nil, fmt.Errorf("scheduler: bad schedule %q: no weekday %q", spec, fields[1])
+		}
+		hh, mm, ss, err := parseTimeOfDay(fields[2])
+		if err != nil || ss != 0 {
+			return nil, fmt.Errorf("scheduler: bad schedule %q: weekly takes hh:mm", spec)
+		}
+		return Weekly(day, hh, mm), nil
+	case "quarter-end", "fiscal-year-start":
+		s, err := parseFiscal(fields)
+		if err != nil {
+			return nil, fmt.Errorf("scheduler: bad schedule %q: %w", spec, err)
+		}
+		return s, nil
+	default:
+		if len(fields) == 5 || len(fields) == 6 || strings.HasPrefix(fields[0], "@") || strings.HasPrefix(fields[0], "cron_tz=") {
+			return ParseCronFormat(spec, format)
+		}
+	}
+	return nil, fmt.Errorf("scheduler: bad schedule %q", spec)
+}
+
+// splitSpecs splits spec at the commas separating the specs it merges:
+// those followed by a space, or by a spec other than a crontab expression.
+func splitSpecs(spec string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(spec); i++ {
+		if spec[i] != ',' {
+			continue
+		}
+		rest := strings.ToLower(spec[i+1:])
+		if rest == "" || rest[0] == ' ' || rest[0] == '\t' ||
+			strings.HasPrefix(rest, "daily") || strings.HasPrefix(rest, "weekly") || strings.HasPrefix(rest, "never") || strings.HasPrefix(rest, "@") ||
+			strings.HasPrefix(rest, "cron_tz=") || strings.HasPrefix(rest, "quarter-end") || strings.HasPrefix(rest, "fiscal-year-start") {
+			parts = append(parts, spec[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, spec[start:])
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// parseTimeOfDay parses hh:mm or hh:mm:ss.
+func parseTimeOfDay(s string) (hh, mm, ss int, err error) {
+	layout := "15:04"
+	if strings.Count(s, ":") == 2 {
+		layout = "15:04:05"
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("bad time of day %q", s)
+	}
+	return t.Hour(), t.Minute(), t.Second(), nil
+}
+
+func (w wallClock) String() string {
+	if w.weekly {
+		return fmt.Sprintf("weekly %s %02d:%02d", strings.ToLower(w.weekday.String()), w.hour, w.min)
+	}
+	if w.sec != 0 {
+		return fmt.Sprintf("daily %02d:%02d:%02d", w.hour, w.min, w.sec)
+	}
+	return fmt.Sprintf("daily %02d:%02d", w.hour, w.min)
+}
+
+func (m merged) String() string {
+	specs := make([]string, len(m))
+	for i, s := range m {
+		specs[i] = fmt.Sprint(s)
+	}
+	return strings.Join(specs, ", ")
+}
+
+func (never) String() string { return "never" }
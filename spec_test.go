@@ -0,0 +1,21 @@
+package scheduler
+
+import "testing"
+
+func TestParseSchedule(t *testing.T) {
+	for _, spec := range []string{"daily 02:30", "daily 02:30:15", "weekly monday 09:00", "never", "daily 01:00, weekly friday 17:30", "0 3 1,15 * 1-5", "daily 01:00, */10 * * * *", "@hourly", "@every 1m30s", "@daily, @every 2h0m0s"} {
+		s, err := ParseSchedule(spec)
+		if err != nil {
+			t.Errorf("%s: %v", spec, err)
+			continue
+		}
+		if got := s.(interface{ String() string }).String(); got != spec {
+			t.Errorf("expected %q to print as itself, got %q", spec, got)
+		}
+	}
+	for _, spec := range []string{"", "hourly", "daily 25:00", "weekly someday 09:00", "weekly monday 09:00:30", "0 3 * * 1-5 *", "@every", "@every soon", "@every -1s", "@fortnightly"} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+}
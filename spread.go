@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"sort"
+	"time"
+)
+
+// WithSpread spreads the runs of the entries with the tag evenly over their
+// interval, instead of all of them running at their start times: the
+// entries sharing an interval get slots that far apart, in the order of
+// their names, from the start of the interval since the Unix epoch, and
+// each entry runs shifted by its Phase to land on its slot. Adding or
+// removing such an entry moves the others of its interval to their new
+// slots. It applies to the entries that run every Interval, not those on a
+// Schedule.
+func WithSpread(tag string) Option {
+	return func(c *Cron) {
+		c.spreadTag = tag
+	}
+}
+
+// spreads reports whether the entry is spread, see WithSpread.
+func (c *Cron) spreads(e *Entry) bool {
+	return c.spreadTag != "" && e.Schedule == nil && e.Interval > 0 && e.HasTag(c.spreadTag)
+}
+
+// spread assigns the phases of the spread entries of the given interval,
+// moving those that are scheduled already to their slot. It is called from
+// the run loop.
+func (c *Cron) spread(interval time.Duration) {
+	if c.spreadTag == "" || interval <= 0 {
+		return
+	}
+	var group []*Entry
+	for _, e := range c.byName {
+		if c.spreads(e) && e.Interval == interval {
+			group = append(group, e)
+		}
+	}
+	sort.Slice(group, func(i, j int) bool { return group[i].Name < group[j].Name })
+	for i, e := range group {
+		slot := time.Duration(int64(interval) * int64(i) / int64(len(group)))
+		start := time.Duration(e.setStartTime.UnixNano() % int64(interval))
+		phase := ((slot-start)%interval + interval) % interval
+		if phase == e.Phase {
+			continue
+		}
+		e.Phase = phase
+		if e.NextTime.IsZero() || e.index < 0 {
+			continue
+		}
+		if e.NextTime = e.place(e.nominal); e.NextTime.Before(e.now()) {
+			e.Next()
+		}
+		c.reschedule(e)
+	}
+}
@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSpread(t *testing.T) {
+	t0 := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(t0.Add(-time.Minute))
+	cron := New(WithClock(clock), WithLocation(time.UTC), WithSpread("spreadable"))
+	for i := 0; i < 4; i++ {
+		cron.AddFunc(t0, time.Hour, func() {}, fmt.Sprintf("job-%d", i), WithTags("spreadable"))
+	}
+	cron.AddFunc(t0, time.Hour, func() {}, "pinned")
+	defer cron.Stop()
+
+	if err := cron.AdvanceTo(t0.Add(59 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		name := fmt.Sprintf("job-%d", i)
+		runs := cron.History(name)
+		if want := t0.Add(time.Duration(i) * 15 * time.Minute); len(runs) != 1 || !runs[0].Scheduled.Equal(want) {
+			t.Errorf("%s: expected a run at %v, got %v", name, want, runs)
+		}
+	}
+	if runs := cron.History("pinned"); len(runs) != 1 || !runs[0].Scheduled.Equal(t0) {
+		t.Errorf("expected the untagged entry to run at its start, got %v", runs)
+	}
+
+	cron.RemoveJob("job-3")
+	for _, e := range cron.Entries() {
+		var want time.Duration
+		switch e.Name {
+		case "job-1":
+			want = 20 * time.Minute
+		case "job-2":
+			want = 40 * time.Minute
+		}
+		if e.Phase != want || !e.NextTime.Equal(t0.Add(time.Hour+want)) {
+			t.Errorf("%s: expected the slot at %v past the hour, got phase %v, next %v", e.Name, want, e.Phase, e.NextTime)
+		}
+	}
+}
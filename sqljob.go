@@ -0,0 +1,278 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ErrAssertionFailed is what a SQLJob fails with, wrapped, when one of its
+// assertions does not hold or its statement fails.
+var ErrAssertionFailed = errors.New("scheduler: sql assertion failed")
+
+// SQLJob runs a SQL statement at each run, for cleanup and data-quality
+// jobs, and asserts on its outcome: a run whose statement fails or whose
+// assertions don't hold fails with ErrAssertionFailed, as OutcomeError, so
+// that retries and alerts apply. What it did and checked ends up in the run
+// history. Add it with AddProgressJob.
+type SQLJob struct {
+	DB        *sql.DB
+	Statement string
+
+	// The arguments of the placeholders of the statement. Those that are
+	// strings are text/templates executed with the RunInfo of the run, as
+	// the Payload of a FunctionJob, so
+	//
+	//	DELETE FROM events WHERE at < ?
+	//
+	// with the argument `{{(.Scheduled.AddDate 0 0 -30).Format "2006-01-02"}}`
+	// keeps thirty days of events as of each run.
+	Args []interface{}
+
+	// Query runs the statement as a query, whose rows are counted and whose
+	// first row has the columns assertions refer to. Otherwise the statement
+	// is executed, and the rows it affected are counted.
+	Query bool
+
+	Assert []SQLAssertion
+
+	// If non-zero, how long the statement may run.
+	Timeout time.Duration
+}
+
+// SQLAssertion is a check of the outcome of a SQLJob, such as "rows >= 1",
+// see ParseSQLAssertion.
+type SQLAssertion struct {
+	// What is checked: "rows", the count of rows, or the column of that name
+	// in the first row of a Query.
+	Target string
+
+	// Op is one of ==, !=, <, <=, > and >=, comparing numbers if both sides
+	// are numbers, the strings otherwise.
+	Op    string
+	Value string
+}
+
+func (a SQLAssertion) String() string {
+	return a.Target + " " + a.Op + " " + a.Value
+}
+
+// ParseSQLAssertion parses an assertion written as "target op value", as in
+// "rows == 0" or "total > 100".
+func ParseSQLAssertion(s string) (SQLAssertion, error) {
+	f := strings.Fields(s)
+	if len(f) != 3 {
+		return SQLAssertion{}, fmt.Errorf("scheduler: sql assertion %q: want target, operator and value", s)
+	}
+	a := SQLAssertion{Target: f[0], Op: f[1], Value: f[2]}
+	if _, err := compareSQL("", a.Op, ""); err != nil {
+		return SQLAssertion{}, fmt.Errorf("scheduler: sql assertion %q: %v", s, err)
+	}
+	return a, nil
+}
+
+func (j SQLJob) Run(p *Progress) {
+	if err := j.run(p); err != nil {
+		fmt.Fprintln(p.Output(), err)
+		p.fail(fmt.Errorf("%w: %v", ErrAssertionFailed, err))
+	}
+}
+
+func (j SQLJob) run(p *Progress) error {
+	args, err := j.args(p.Info())
+	if err != nil {
+		return err
+	}
+	ctx := p.Context()
+	if j.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.Timeout)
+		defer cancel()
+	}
+	var rows int64
+	var first map[string]string
+	if j.Query {
+		rows, first, err = querySQL(ctx, j.DB, j.Statement, args)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(p.Output(), "%d rows\n", rows)
+	} else {
+		res, err := j.DB.ExecContext(ctx, j.Statement, args...)
+		if err != nil {
+			return err
+		}
+		if rows, err = res.RowsAffected(); err != nil {
+			return err
+		}
+		fmt.Fprintf(p.Output(), "%d rows affected\n", rows)
+	}
+	var failed []error
+	for _, a := range j.Assert {
+		got, ok := first[a.Target]
+		if a.Target == "rows" {
+			got, ok = strconv.FormatInt(rows, 10), true
+		}
+		if !ok {
+			failed = append(failed, fmt.Errorf("%s: no such column", a))
+			continue
+		}
+		holds, err := compareSQL(got, a.Op, a.Value)
+		if err == nil && !holds {
+			err = fmt.Errorf("got %s", got)
+		}
+		if err != nil {
+			failed = append(failed, fmt.Errorf("%s: %v", a, err))
+			continue
+		}
+		fmt.Fprintf(p.Output(), "%s: ok\n", a)
+	}
+	return errors.Join(failed...)
+}
+
+// args returns the arguments of the statement for the run described by
+// info.
+func (j SQLJob) args(info RunInfo) ([]interface{}, error) {
+	args := make([]interface{}, len(j.Args))
+	for i, arg := range j.Args {
+		s, ok := arg.(string)
+		if !ok || !strings.Contains(s, "{{") {
+			args[i] = arg
+			continue
+		}
+		tmpl, err := template.New("arg").Funcs(functionTemplateFuncs).Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, info); err != nil {
+			return nil, err
+		}
+		args[i] = b.String()
+	}
+	return args, nil
+}
+
+// querySQL runs a query and returns how many rows it returned, and the columns
+// of the first one, formatted.
+func querySQL(ctx context.Context, db *sql.DB, statement string, args []interface{}) (int64, map[string]string, error) {
+	rs, err := db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rs.Close()
+	cols, err := rs.Columns()
+	if err != nil {
+		return 0, nil, err
+	}
+	var n int64
+	var first map[string]string
+	for rs.Next() {
+		n++
+		if first != nil {
+			continue
+		}
+		vals := make([]interface{}, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		if err := rs.Scan(ptrs...); err != nil {
+			return n, nil, err
+		}
+		first = make(map[string]string, len(cols))
+		for i, col := range cols {
+			if b, ok := vals[i].([]byte); ok {
+				vals[i] = string(b)
+			}
+			first[col] = fmt.Sprint(vals[i])
+		}
+	}
+	return n, first, rs.Err()
+}
+
+// compareSQL returns whether "got op want" holds.
+func compareSQL(got, op, want string) (bool, error) {
+	g, gerr := strconv.ParseFloat(got, 64)
+	w, werr := strconv.ParseFloat(want, 64)
+	numeric := gerr == nil && werr == nil
+	c := strings.Compare(got, want)
+	if numeric {
+		c = 0
+		if g < w {
+			c = -1
+		} else if g > w {
+			c = 1
+		}
+	}
+	switch op {
+	case "==":
+		return c == 0, nil
+	case "!=":
+		return c != 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	case ">=":
+		return c >= 0, nil
+	}
+	return false, fmt.Errorf("unknown operator %q", op)
+}
+
+// SQLJobFactory returns a JobFactory for SQLJobs, for entries defined by
+// their parameters, see RegisterJobFactory: "db" is the key of the
+// database in dbs, "statement" the statement, and "args", a list, "query",
+// a bool, "assert", a list of assertions as parsed by ParseSQLAssertion,
+// and "timeout", a duration such as "30s", are optional. The job of a
+// definition with a bad parameter fails every run.
+func SQLJobFactory(dbs map[string]*sql.DB) JobFactory {
+	return func(params map[string]interface{}) Job {
+		j, err := sqlJob(dbs, params)
+		if err != nil {
+			err = fmt.Errorf("%w: %v", ErrAssertionFailed, err)
+			return AsJob(errJob{ErrFuncJob(func() error { return err })})
+		}
+		return AsJob(j)
+	}
+}
+
+func sqlJob(dbs map[string]*sql.DB, params map[string]interface{}) (SQLJob, error) {
+	name, _ := params["db"].(string)
+	j := SQLJob{DB: dbs[name]}
+	if j.DB == nil {
+		return j, fmt.Errorf("no database %q", name)
+	}
+	j.Statement, _ = params["statement"].(string)
+	j.Query, _ = params["query"].(bool)
+	j.Args, _ = params["args"].([]interface{})
+	if s, _ := params["timeout"].(string); s != "" {
+		var err error
+		if j.Timeout, err = time.ParseDuration(s); err != nil {
+			return j, err
+		}
+	}
+	asserts, _ := params["assert"].([]string)
+	if vs, ok := params["assert"].([]interface{}); ok {
+		for _, v := range vs {
+			s, _ := v.(string)
+			asserts = append(asserts, s)
+		}
+	}
+	for _, s := range asserts {
+		a, err := ParseSQLAssertion(s)
+		if err != nil {
+			return j, err
+		}
+		j.Assert = append(j.Assert, a)
+	}
+	return j, nil
+}
@@ -0,0 +1,135 @@
+package scheduler
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSQL is a database/sql driver answering every statement from its
+// results: the rows of a query, or the rows affected by anything else.
+type fakeSQL struct {
+	mu       sync.Mutex
+	affected int64
+	cols     []string
+	rows     [][]driver.Value
+	args     [][]driver.Value
+}
+
+func (d *fakeSQL) Open(string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeSQL }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.d}, nil }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("no transactions") }
+
+type fakeStmt struct{ d *fakeSQL }
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.args = append(s.d.args, args)
+	return driver.RowsAffected(s.d.affected), nil
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	return &fakeRows{cols: s.d.cols, rows: s.d.rows}, nil
+}
+
+type fakeRows struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.cols }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+var fakeSQLs sync.Map
+
+// openFakeSQL returns a database on a fresh fakeSQL driver.
+func openFakeSQL(t *testing.T, d *fakeSQL) *sql.DB {
+	name := "fake-" + t.Name()
+	if _, dup := fakeSQLs.LoadOrStore(name, d); !dup {
+		sql.Register(name, d)
+	}
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSQLJob(t *testing.T) {
+	d := &fakeSQL{affected: 3, cols: []string{"total", "state"}, rows: [][]driver.Value{{int64(120), []byte("ok")}, {int64(5), []byte("bad")}}}
+	db := openFakeSQL(t, d)
+	cron := New(WithPanicPolicy(PanicDisable))
+	cron.RegisterJobFactory("sql", SQLJobFactory(map[string]*sql.DB{"main": db}))
+	for name, params := range map[string]map[string]interface{}{
+		"cleanup": {"db": "main", "statement": "DELETE FROM events WHERE at < ?",
+			"args": []interface{}{`{{.Scheduled.Format "2006"}}`, 7}, "assert": []interface{}{"rows >= 1"}},
+		"quality": {"db": "main", "statement": "SELECT total, state FROM t", "query": true,
+			"assert": []string{"rows == 2", "total > 100", "state == ok"}},
+		"failing": {"db": "main", "statement": "SELECT total, state FROM t", "query": true,
+			"assert": []string{"total < 100", "missing == 1"}},
+		"nodb": {"db": "other", "statement": "SELECT 1"},
+	} {
+		if err := cron.AddRegisteredJob(time.Now().Add(50*time.Millisecond), time.Hour, "sql", params, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	for name, want := range map[string]Outcome{"cleanup": OutcomeSuccess, "quality": OutcomeSuccess, "failing": OutcomeError, "nodb": OutcomeError} {
+		if run := waitForHistory(t, cron, name, 1)[0]; run.Outcome != want {
+			t.Errorf("%s: expected %s, got %s: %s", name, want, run.Outcome, run.Output)
+		} else if want == OutcomeError && !strings.Contains(run.Error, ErrAssertionFailed.Error()) {
+			t.Errorf("%s: expected ErrAssertionFailed, got %q", name, run.Error)
+		}
+	}
+	// A failed assertion is no panic, so PanicDisable leaves the entry be.
+	if e, _ := cron.Entry("failing"); e.Disabled || e.Panics != 0 {
+		t.Errorf("expected the failing entry enabled without panics, got %v, %d", e.Disabled, e.Panics)
+	}
+	run := waitForHistory(t, cron, "failing", 1)[0]
+	if !strings.Contains(run.Output, "total < 100: got 120") || !strings.Contains(run.Output, "missing == 1: no such column") {
+		t.Errorf("expected both failed assertions in the output, got %q", run.Output)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.args) != 1 || d.args[0][0] != time.Now().Format("2006") || d.args[0][1] != int64(7) {
+		t.Errorf("unexpected arguments %v", d.args)
+	}
+}
+
+func TestParseSQLAssertion(t *testing.T) {
+	if a, err := ParseSQLAssertion("rows  >=  1"); err != nil || a != (SQLAssertion{"rows", ">=", "1"}) {
+		t.Errorf("unexpected %v, %v", a, err)
+	}
+	for _, s := range []string{"rows", "rows ~ 1", "rows == 1 2"} {
+		if _, err := ParseSQLAssertion(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
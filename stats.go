@@ -0,0 +1,93 @@
+package scheduler
+
+import "time"
+
+// EntryStats counts how the runs of an entry went since the entry was added
+// or its counters were last reset, see ResetStats. Unlike Entry.Runs, which
+// WithMaxRuns goes by, they can be zeroed.
+type EntryStats struct {
+	// Runs whose job ran, and how many of those failed: they panicked or
+	// stalled.
+	Runs     int
+	Failures int
+
+	// Runs that were skipped: vetoed, over budget, cached or paused.
+	Skipped int
+
+	// Triggers dropped because too many runs were pending.
+	Dropped int
+
+	// Failed runs that were retried, and those that were not as the retry
+	// budget was spent, see WithRetryBudget.
+	Retries       int
+	RetriesDenied int
+
+	// The retries the budget has left in the current window, or -1 if the
+	// entry has no budget. It is not a counter, so ResetStats leaves it.
+	RetryBudget int
+
+	// When counting started.
+	Since time.Time
+}
+
+// count accounts for a run that ended with outcome.
+func (s *EntryStats) count(outcome Outcome) {
+	switch {
+	case outcome.skipped():
+		s.Skipped++
+	case outcome == OutcomeSuccess:
+		s.Runs++
+	default:
+		s.Runs++
+		s.Failures++
+	}
+}
+
+// Stats returns the counters of the named entry.
+func (c *Cron) Stats(name string) (EntryStats, error) {
+	var stats EntryStats
+	err := c.withEntry(name, func(e *Entry) {
+		stats = e.statsNow()
+	})
+	return stats, err
+}
+
+// ResetStats zeroes the counters of the named entry, say once its incidents
+// are acknowledged, and returns what they were.
+func (c *Cron) ResetStats(name string) (EntryStats, error) {
+	var stats EntryStats
+	err := c.withEntry(name, func(e *Entry) {
+		stats = e.statsNow()
+		e.stats = EntryStats{Since: time.Now()}
+	})
+	return stats, err
+}
+
+// withEntry calls f with the named entry, holding c.mu.
+func (c *Cron) withEntry(name string, f func(e *Entry)) error {
+	c.entriesMu.RLock()
+	defer c.entriesMu.RUnlock()
+	e := c.lookup(name)
+	if e == nil {
+		return ErrNoSuchEntry
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f(e)
+	return nil
+}
+
+// Stats returns the counters of the entry.
+func (v EntryView) Stats() EntryStats {
+	v.c.mu.Lock()
+	defer v.c.mu.Unlock()
+	return v.e.statsNow()
+}
+
+// statsNow returns the counters of e, along with the state of its retry
+// budget. The caller must hold c.mu.
+func (e *Entry) statsNow() EntryStats {
+	stats := e.stats
+	stats.RetryBudget = e.retriesLeft(time.Now())
+	return stats
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	var veto int32
+	cron := New(WithInterceptor(func(ctx context.Context, e *Entry, scheduled time.Time) (context.Context, Verdict) {
+		return ctx, Verdict{Veto: atomic.LoadInt32(&veto) == 1}
+	}))
+	var calls int32
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {
+		if atomic.AddInt32(&calls, 1) == 2 {
+			panic("flaky")
+		}
+	}, "job")
+	cron.Start()
+	defer cron.Stop()
+
+	cron.RunNow("job")
+	cron.RunNow("job")
+	atomic.StoreInt32(&veto, 1)
+	cron.RunNow("job")
+
+	stats, err := cron.Stats("job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Runs != 2 || stats.Failures != 1 || stats.Skipped != 1 || stats.Since.IsZero() {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	old, _ := cron.ResetStats("job")
+	if old != stats {
+		t.Errorf("expected the counters before the reset, got %+v", old)
+	}
+	if stats, _ := cron.Stats("job"); stats.Runs != 0 || stats.Failures != 0 || !stats.Since.After(old.Since) {
+		t.Errorf("expected zeroed counters, got %+v", stats)
+	}
+	if e := cron.Entries()[0]; e.Runs != 3 {
+		t.Errorf("expected the reset to leave Runs alone, got %d", e.Runs)
+	}
+	if _, err := cron.ResetStats("missing"); err != ErrNoSuchEntry {
+		t.Errorf("expected ErrNoSuchEntry, got %v", err)
+	}
+}
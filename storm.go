@@ -0,0 +1,13 @@
+package scheduler
+
+import "time"
+
+// smear returns how far apart to start the runs of n entries that came due
+// at once, zero unless that is a trigger storm. See WithStormProtection.
+func (c *Cron) smear(n int) time.Duration {
+	if c.stormThreshold <= 0 || n <= c.stormThreshold {
+		return 0
+	}
+	c.log(SubsystemDispatch).Info("trigger storm, smearing runs", "due", n, "over", c.stormSmear)
+	return c.stormSmear / time.Duration(n)
+}
@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStormProtection(t *testing.T) {
+	cron := New(WithStormProtection(3, 400*time.Millisecond))
+	start := time.Now().Add(50 * time.Millisecond)
+	for i := 0; i < 8; i++ {
+		cron.AddFunc(start, time.Hour, func() {}, fmt.Sprint(i))
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	var first, last time.Time
+	for i := 0; i < 8; i++ {
+		r := waitForHistory(t, cron, fmt.Sprint(i), 1)[0]
+		if first.IsZero() || r.Start.Before(first) {
+			first = r.Start
+		}
+		if r.Start.After(last) {
+			last = r.Start
+		}
+	}
+	if spread := last.Sub(first); spread < 300*time.Millisecond || spread > 400*time.Millisecond {
+		t.Errorf("expected the runs to be spread over the smear window, got %v", spread)
+	}
+}
+
+func TestStormProtectionBelowThreshold(t *testing.T) {
+	c := New(WithStormProtection(3, time.Second))
+	if d := c.smear(3); d != 0 {
+		t.Errorf("expected no smear at the threshold, got %v", d)
+	}
+	if d := c.smear(4); d != 250*time.Millisecond {
+		t.Errorf("expected 250ms between runs, got %v", d)
+	}
+}
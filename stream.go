@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"bytes"
+	"strings"
+)
+
+const (
+	// How many lines a follower may fall behind before lines are dropped.
+	followBuffer = 256
+
+	// How many of the lines written before Tail was called are replayed.
+	tailBacklog = 10
+)
+
+// Tail streams the output of the run of the named entry that is currently
+// in progress, one line at a time, starting with its last few lines. The
+// channel is closed when the run ends or stop is called. A follower that
+// falls too far behind misses lines rather than slowing the job down.
+//
+// ok is false if the entry is not running.
+func (c *Cron) Tail(name string) (lines <-chan string, stop func(), ok bool) {
+	c.mu.Lock()
+	p := c.live[name]
+	c.mu.Unlock()
+	if p == nil {
+		return nil, func() {}, false
+	}
+	ch := p.output.follow()
+	return ch, func() { p.output.unfollow(ch) }, true
+}
+
+// follow registers a new follower, primed with the backlog.
+func (b *outputBuffer) follow() chan string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ch := make(chan string, followBuffer)
+	complete := b.buf[:bytes.LastIndexByte(b.buf, '\n')+1]
+	if len(complete) > 0 {
+		backlog := strings.Split(string(complete[:len(complete)-1]), "\n")
+		if len(backlog) > tailBacklog {
+			backlog = backlog[len(backlog)-tailBacklog:]
+		}
+		for _, line := range backlog {
+			ch <- line
+		}
+	}
+	if b.closed {
+		close(ch)
+		return ch
+	}
+	if b.followers == nil {
+		b.followers = make(map[chan string]struct{})
+	}
+	b.followers[ch] = struct{}{}
+	return ch
+}
+
+func (b *outputBuffer) unfollow(ch chan string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.followers[ch]; ok {
+		delete(b.followers, ch)
+		close(ch)
+	}
+}
+
+// broadcast passes the complete lines in p on to the followers. The caller
+// must hold b.mu.
+func (b *outputBuffer) broadcast(p []byte) {
+	b.partial = append(b.partial, p...)
+	for {
+		i := bytes.IndexByte(b.partial, '\n')
+		if i < 0 {
+			break
+		}
+		b.send(string(b.partial[:i]))
+		b.partial = b.partial[i+1:]
+	}
+	if over := len(b.partial) - b.limit; over > 0 {
+		b.partial = b.partial[over:]
+	}
+}
+
+func (b *outputBuffer) send(line string) {
+	for ch := range b.followers {
+		select {
+		case ch <- line:
+		default:
+		}
+	}
+}
+
+// close flushes the last incomplete line and ends all follows.
+func (b *outputBuffer) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.partial) > 0 {
+		b.send(string(b.partial))
+		b.partial = nil
+	}
+	for ch := range b.followers {
+		close(ch)
+	}
+	b.followers = nil
+	b.closed = true
+}
@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTailRunningJob(t *testing.T) {
+	cron := New()
+	started := make(chan struct{})
+	proceed := make(chan struct{})
+	cron.AddProgressFunc(time.Now().Add(100*time.Millisecond), time.Hour, func(p *Progress) {
+		fmt.Fprintln(p.Output(), "first")
+		close(started)
+		<-proceed
+		fmt.Fprint(p.Output(), "second\nthird")
+	}, "tailed")
+	cron.Start()
+	defer cron.Stop()
+
+	if _, _, ok := cron.Tail("tailed"); ok {
+		t.Fatal("expected no run to tail before the job starts")
+	}
+	select {
+	case <-started:
+	case <-time.After(ONE_SECOND):
+		t.Fatal("job did not run")
+	}
+
+	lines, stop, ok := cron.Tail("tailed")
+	if !ok {
+		t.Fatal("expected to tail the running job")
+	}
+	defer stop()
+	close(proceed)
+
+	var got []string
+	timeout := time.After(ONE_SECOND)
+	for done := false; !done; {
+		select {
+		case line, open := <-lines:
+			if !open {
+				done = true
+				break
+			}
+			got = append(got, line)
+		case <-timeout:
+			t.Fatalf("stream was not closed, got %q", got)
+		}
+	}
+	if fmt.Sprint(got) != "[first second third]" {
+		t.Errorf("unexpected lines %q", got)
+	}
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// The states SdNotify tells systemd of the most, see sd_notify(3).
+const (
+	SdReady    = "READY=1"
+	SdStopping = "STOPPING=1"
+	SdWatchdog = "WATCHDOG=1"
+)
+
+// SdNotify sends state to systemd through the socket in $NOTIFY_SOCKET, see
+// sd_notify(3). It returns false, and no error, if the process wasn't
+// started by systemd with notifications on.
+func SdNotify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// WithSystemdNotify makes RunUntilSignal tell systemd the service is ready
+// once the scheduler is started, and stopping once it gets a signal. If
+// systemd watches the service, see WatchdogSec in systemd.service(5), it
+// also pings the watchdog while the run loop of the scheduler responds,
+// so that systemd restarts a stuck scheduler.
+func WithSystemdNotify() SignalOption {
+	return func(cfg *signalConfig) {
+		cfg.systemd = true
+	}
+}
+
+// sdWatchdog returns how often systemd expects the watchdog to be pinged,
+// or zero if it doesn't watch this process.
+func sdWatchdog() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// notifySystemd tells systemd c is ready and pings its watchdog. It returns
+// a func that stops the pings and tells systemd c is stopping.
+func (c *Cron) notifySystemd() (stopping func()) {
+	logger := c.log(SubsystemLifecycle)
+	if _, err := SdNotify(SdReady); err != nil {
+		logger.Error(err, "notifying systemd failed")
+	}
+	interval := sdWatchdog() / 2
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if interval <= 0 {
+			return
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				// Going through the run loop, so a stuck one misses pings.
+				c.inLoop(func() {})
+				if _, err := SdNotify(SdWatchdog); err != nil {
+					logger.Error(err, "pinging the systemd watchdog failed")
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		// Let a ping in flight finish, unless the run loop is stuck.
+		select {
+		case <-done:
+		case <-time.After(interval):
+		}
+		if _, err := SdNotify(SdStopping); err != nil {
+			logger.Error(err, "notifying systemd failed")
+		}
+	}
+}
@@ -0,0 +1,60 @@
+//go:build !windows
+
+package scheduler
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSdNotifyWithoutSystemd(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if sent, err := SdNotify(SdReady); sent || err != nil {
+		t.Errorf("expected nothing to be sent, got %v, %v", sent, err)
+	}
+}
+
+func TestRunUntilSignalNotifiesSystemd(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	t.Setenv("NOTIFY_SOCKET", socket)
+	t.Setenv("WATCHDOG_USEC", "100000")
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+
+	done := make(chan error, 1)
+	go func() { done <- RunUntilSignal(New(), WithSystemdNotify()) }()
+
+	read := func() string {
+		buf := make([]byte, 64)
+		conn.SetReadDeadline(time.Now().Add(ONE_SECOND))
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(buf[:n])
+	}
+	if got := read(); got != SdReady {
+		t.Fatalf("expected %s first, got %s", SdReady, got)
+	}
+	if got := read(); got != SdWatchdog {
+		t.Fatalf("expected the watchdog to be pinged, got %s", got)
+	}
+	syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+	for {
+		if got := read(); got == SdStopping {
+			break
+		}
+	}
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
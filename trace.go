@@ -0,0 +1,254 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrReplayDiverged is returned by Replay.Run when the scheduler didn't do
+// what the trace says it did.
+var ErrReplayDiverged = errors.New("scheduler: replay diverged from the trace")
+
+// TraceKind is the kind of a TraceEvent.
+type TraceKind string
+
+const (
+	// The run loop started, with Entries in the order of their next runs.
+	TraceStart TraceKind = "start"
+
+	// The run loop woke up for the Entries due at Scheduled.
+	TraceWake TraceKind = "wake"
+
+	// The run loop decided something about the triggers of a wake-up:
+	// "skipped" all of them, see Faults.Skip, or "smeared" the run of Entry
+	// by Delay, see WithStormProtection.
+	TraceDecision TraceKind = "decision"
+
+	// A trigger of Entry due at Scheduled was "dispatched", "queued" behind
+	// a run going, "dropped" for too many runs pending, or ignored as the
+	// entry was "disabled", its circuit open ("circuit_open"), "retired" or
+	// runs "elsewhere", see WithAffinity.
+	TraceDispatch TraceKind = "dispatch"
+)
+
+// TraceEvent is a line of a trace written by WithTrace.
+type TraceEvent struct {
+	Kind TraceKind `json:"kind"`
+
+	// The time by the Clock of the scheduler.
+	Time time.Time `json:"time"`
+
+	Entry     string        `json:"entry,omitempty"`
+	Entries   []string      `json:"entries,omitempty"`
+	Scheduled time.Time     `json:"scheduled,omitempty"`
+	Decision  string        `json:"decision,omitempty"`
+	Delay     time.Duration `json:"delay,omitempty"`
+}
+
+func (e TraceEvent) String() string {
+	s := fmt.Sprintf("%s at %s", e.Kind, e.Time.Format(time.RFC3339Nano))
+	if e.Entry != "" {
+		s += " entry=" + e.Entry
+	}
+	if len(e.Entries) > 0 {
+		s += " entries=" + strings.Join(e.Entries, ",")
+	}
+	if !e.Scheduled.IsZero() {
+		s += " scheduled=" + e.Scheduled.Format(time.RFC3339Nano)
+	}
+	if e.Decision != "" {
+		s += " decision=" + e.Decision
+	}
+	if e.Delay != 0 {
+		s += " delay=" + e.Delay.String()
+	}
+	return s
+}
+
+// same reports whether the events tell the same, whatever the locations
+// of their times. Only the times of the wake-ups count, as the decisions
+// following one take a while on the system clock but none on a FakeClock.
+func (e TraceEvent) same(o TraceEvent) bool {
+	if e.Kind == TraceWake && !e.Time.Equal(o.Time) {
+		return false
+	}
+	return e.Kind == o.Kind && e.Entry == o.Entry &&
+		strings.Join(e.Entries, ",") == strings.Join(o.Entries, ",") &&
+		e.Scheduled.Equal(o.Scheduled) && e.Decision == o.Decision && e.Delay == o.Delay
+}
+
+// tracer records the trace of the run loop, to a writer or, for Replay, in
+// memory.
+type tracer struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	events []TraceEvent
+}
+
+// newTracer returns a tracer writing to w.
+func newTracer(w io.Writer) *tracer {
+	return &tracer{enc: json.NewEncoder(w)}
+}
+
+// trace records e, if tracing.
+func (c *Cron) trace(e TraceEvent) {
+	if c.tracer == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = c.clock.Now()
+	}
+	tr := c.tracer
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.enc == nil {
+		tr.events = append(tr.events, e)
+		return
+	}
+	if err := tr.enc.Encode(e); err != nil {
+		c.log(SubsystemLifecycle).Error(err, "writing trace failed, tracing stopped")
+		tr.enc = json.NewEncoder(io.Discard)
+	}
+}
+
+// traceNames returns the names of the entries.
+func traceNames(entries []*Entry) []string {
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Name
+	}
+	return names
+}
+
+// ReadTrace reads a trace written by WithTrace.
+func ReadTrace(r io.Reader) ([]TraceEvent, error) {
+	var events []TraceEvent
+	dec := json.NewDecoder(r)
+	for {
+		var e TraceEvent
+		if err := dec.Decode(&e); err == io.EOF {
+			return events, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("scheduler: reading trace: %w", err)
+		}
+		events = append(events, e)
+	}
+}
+
+// Replay re-runs the wake-ups of a trace written by WithTrace, on a
+// FakeClock, to reproduce what the scheduler did:
+//
+//	r, err := NewReplay(trace)
+//	...
+//	c := New(WithClock(r.Clock()))
+//	// Add the entries the trace was recorded with.
+//	err = r.Run(c)
+//
+// The jobs run for real. Faults must be given a seeded Rand to replay.
+type Replay struct {
+	events []TraceEvent
+	clock  *FakeClock
+}
+
+// NewReplay reads the trace to replay. It starts at the first run loop
+// start in it.
+func NewReplay(trace io.Reader) (*Replay, error) {
+	events, err := ReadTrace(trace)
+	if err != nil {
+		return nil, err
+	}
+	for i, e := range events {
+		if e.Kind == TraceStart {
+			return &Replay{events: events[i:], clock: NewFakeClock(e.Time)}, nil
+		}
+	}
+	return nil, errors.New("scheduler: the trace has no start")
+}
+
+// Clock returns the clock to replay on, showing the time the trace starts.
+func (r *Replay) Clock() *FakeClock {
+	return r.clock
+}
+
+// Run starts c, which must use the Clock of the replay and not be running,
+// moves the clock through the wake-ups of the trace, and stops c. It
+// returns an error wrapping ErrReplayDiverged at the first event that
+// differs from the trace.
+func (r *Replay) Run(c *Cron) error {
+	if c.clock != Clock(r.clock) {
+		return errors.New("scheduler: replaying on another clock")
+	}
+	c.tracer = &tracer{}
+	defer func() { c.tracer = nil }()
+
+	c.Start()
+	defer c.Stop()
+	// The run loop waits on the channel After last returned, so it wakes
+	// up by the time that goes off, recording the wake-up before it calls
+	// After again.
+	afters, wake := r.clock.awaitAfter(0)
+	wakes := 0
+	for _, e := range r.events[1:] {
+		if e.Kind == TraceStart {
+			break
+		}
+		if e.Kind != TraceWake {
+			continue
+		}
+		wakes++
+		for c.tracer.wakes() < wakes {
+			if wake.After(e.Time) {
+				return fmt.Errorf("%w: expected %s, got a wake-up at %s", ErrReplayDiverged, e, wake.Format(time.RFC3339Nano))
+			}
+			r.clock.Set(e.Time)
+			afters, wake = r.clock.awaitAfter(afters)
+		}
+	}
+	// Let the loop go through the triggers of the last wake-up.
+	for !wake.After(r.clock.Now()) {
+		afters, wake = r.clock.awaitAfter(afters)
+	}
+	return r.compare(c.tracer.replayed())
+}
+
+// wakes returns how many wake-ups were recorded so far.
+func (tr *tracer) wakes() int {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	n := 0
+	for _, e := range tr.events {
+		if e.Kind == TraceWake {
+			n++
+		}
+	}
+	return n
+}
+
+// replayed returns the events recorded so far.
+func (tr *tracer) replayed() []TraceEvent {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return append([]TraceEvent(nil), tr.events...)
+}
+
+// compare returns the first difference between the trace and the replayed
+// events.
+func (r *Replay) compare(replayed []TraceEvent) error {
+	for i, want := range r.events {
+		if i > 0 && want.Kind == TraceStart {
+			break
+		}
+		if i >= len(replayed) {
+			return fmt.Errorf("%w: missing %s", ErrReplayDiverged, want)
+		}
+		if got := replayed[i]; !got.same(want) {
+			return fmt.Errorf("%w: expected %s, got %s", ErrReplayDiverged, want, got)
+		}
+	}
+	return nil
+}
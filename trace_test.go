@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+// record runs the entries added by setup on a fake clock through the given
+// steps, and returns the trace.
+func record(t *testing.T, start time.Time, setup func(c *Cron), steps ...time.Duration) *bytes.Buffer {
+	var trace bytes.Buffer
+	clock := NewFakeClock(start)
+	cron := New(WithClock(clock), WithTrace(&trace))
+	setup(cron)
+	cron.Start()
+	afters, _ := clock.awaitAfter(0)
+	for _, step := range steps {
+		clock.Advance(step)
+		// Wait for the wake-ups due by then.
+		for {
+			var wake time.Time
+			afters, wake = clock.awaitAfter(afters)
+			if wake.After(clock.Now()) {
+				break
+			}
+		}
+	}
+	cron.Stop()
+	return &trace
+}
+
+func TestReplayReproducesTrace(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	setup := func(c *Cron) {
+		c.AddFunc(start.Add(time.Minute), time.Minute, func() {}, "a")
+		c.AddFunc(start.Add(90*time.Second), 2*time.Minute, func() {}, "b")
+	}
+	trace := record(t, start, setup, time.Minute, time.Minute, 3*time.Minute)
+
+	events, err := ReadTrace(bytes.NewReader(trace.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wakes := 0
+	for _, e := range events {
+		if e.Kind == TraceWake {
+			wakes++
+		}
+	}
+	if events[0].Kind != TraceStart || wakes != 7 {
+		t.Fatalf("expected a start and 7 wake-ups, got %v", events)
+	}
+
+	r, err := NewReplay(bytes.NewReader(trace.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cron := New(WithClock(r.Clock()))
+	setup(cron)
+	if err := r.Run(cron); err != nil {
+		t.Errorf("expected the replay to match, got %v", err)
+	}
+}
+
+func TestReplayDiverges(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	trace := record(t, start, func(c *Cron) {
+		c.AddFunc(start.Add(time.Minute), time.Minute, func() {}, "a")
+	}, time.Minute, time.Minute)
+
+	r, err := NewReplay(trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cron := New(WithClock(r.Clock()))
+	cron.AddFunc(start.Add(time.Minute), 2*time.Minute, func() {}, "a")
+	if err := r.Run(cron); !errors.Is(err, ErrReplayDiverged) {
+		t.Errorf("expected the replay to diverge, got %v", err)
+	}
+}
+
+// A trace recorded on the system clock replays on a FakeClock.
+func TestReplayRealTrace(t *testing.T) {
+	var trace bytes.Buffer
+	start := time.Now().Truncate(time.Second).Add(time.Second)
+	setup := func(c *Cron) {
+		c.AddFunc(start, 100*time.Millisecond, func() {}, "tick")
+	}
+	cron := New(WithTrace(&trace))
+	setup(cron)
+	cron.Start()
+	waitForHistory(t, cron, "tick", 3)
+	cron.Stop()
+
+	r, err := NewReplay(&trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay := New(WithClock(r.Clock()))
+	setup(replay)
+	if err := r.Run(replay); err != nil {
+		t.Errorf("expected the replay to match, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// How many definitions of each entry are kept for Rollback.
+const definitionLimit = 10
+
+// ErrNoSuchEntry is returned for an entry name the Cron doesn't know.
+var ErrNoSuchEntry = errors.New("scheduler: no such entry")
+
+// UpdateJob redefines an existing entry, bumping its version. Unlike
+// AddJob, it fails if there is no entry with that name.
+func (c *Cron) UpdateJob(startTime time.Time, Interval time.Duration, cmd Job, name string, opts ...EntryOption) error {
+	return c.Schedule(startTime, Interval, cmd, name, append(opts, func(e *Entry) {
+		e.update = true
+		e.mustExist = true
+	})...)
+}
+
+// Definitions returns the definitions of the named entry that are kept for
+// Rollback, oldest first. The current one is last.
+func (c *Cron) Definitions(name string) []*Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var defs []*Entry
+	for _, d := range c.definitions[name] {
+		defs = append(defs, d.definition())
+	}
+	return defs
+}
+
+// Rollback puts the named entry back to the definition it had at the given
+// version. The entry gets a new version, so history shows the rollback.
+func (c *Cron) Rollback(name string, version int) error {
+	var def *Entry
+	c.mu.Lock()
+	for _, d := range c.definitions[name] {
+		if d.Version == version {
+			def = d.definition()
+		}
+	}
+	c.mu.Unlock()
+	if def == nil {
+		return fmt.Errorf("scheduler: no version %d of %q to roll back to", version, name)
+	}
+
+	def.rollback = true
+	return c.admit(def)
+}
+
+// keepDefinition remembers the definition of the entry for Rollback.
+func (c *Cron) keepDefinition(e *Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.definitions == nil {
+		c.definitions = make(map[string][]*Entry)
+	}
+	defs := append(c.definitions[e.Name], e.definition())
+	if len(defs) > definitionLimit {
+		defs = defs[len(defs)-definitionLimit:]
+	}
+	c.definitions[e.Name] = defs
+}
@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUpdateAndRollback(t *testing.T) {
+	var mu sync.Mutex
+	var events []Event
+	cron := New(WithEventHandler(func(e Event) {
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+	}))
+	if err := cron.UpdateJob(time.Now(), time.Hour, FuncJob(func() {}), "job"); err != ErrNoSuchEntry {
+		t.Errorf("expected ErrNoSuchEntry, got %v", err)
+	}
+
+	cron.AddFunc(time.Now(), time.Hour, func() {}, "job")
+	if err := cron.UpdateJob(time.Now(), 2*time.Hour, FuncJob(func() {}), "job"); err != nil {
+		t.Fatal(err)
+	}
+	if e := cron.Entries()[0]; e.Version != 2 || e.Interval != 2*time.Hour {
+		t.Errorf("expected version 2 every 2h, got version %d every %v", e.Version, e.Interval)
+	}
+
+	cron.Start()
+	defer cron.Stop()
+	if err := cron.Rollback("job", 1); err != nil {
+		t.Fatal(err)
+	}
+	if e := cron.Entries()[0]; e.Version != 3 || e.Interval != time.Hour {
+		t.Errorf("expected version 3 every 1h, got version %d every %v", e.Version, e.Interval)
+	}
+	if err := cron.Rollback("job", 7); err == nil {
+		t.Error("expected an error rolling back to an unknown version")
+	}
+	if defs := cron.Definitions("job"); len(defs) != 3 {
+		t.Errorf("expected 3 definitions, got %d", len(defs))
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	want := []EventType{EventAdded, EventUpdated, EventRolledBack}
+	if len(events) != len(want) {
+		t.Fatalf("expected events %v, got %+v", want, events)
+	}
+	for i, e := range events {
+		if e.Type != want[i] || e.Version != i+1 {
+			t.Errorf("event %d: expected %s of version %d, got %s of %d", i, want[i], i+1, e.Type, e.Version)
+		}
+	}
+}
+
+func TestRunHistoryHasVersion(t *testing.T) {
+	cron := New()
+	cron.AddFunc(time.Now(), time.Hour, func() {}, "job")
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() {}, "job")
+	cron.Start()
+	defer cron.Stop()
+
+	if run := waitForHistory(t, cron, "job", 1)[0]; run.Version != 2 {
+		t.Errorf("expected the run of version 2, got %d", run.Version)
+	}
+}
+
+// UpdateJob racing RemoveJob either redefines the entry or fails, it never
+// adds it back.
+func TestUpdateWhileRemoving(t *testing.T) {
+	cron := New()
+	cron.Start()
+	defer cron.Stop()
+
+	for i := 0; i < 100; i++ {
+		cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "job")
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			cron.UpdateJob(time.Now().Add(time.Hour), time.Hour, FuncJob(func() {}), "job")
+		}()
+		go func() {
+			defer wg.Done()
+			cron.RemoveJob("job")
+		}()
+		wg.Wait()
+		if _, ok := cron.Entry("job"); ok {
+			t.Fatal("expected UpdateJob not to add back the entry removed")
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import "time"
+
+// View is a read-only accessor over the live state of the entries. Unlike
+// Entries, it copies nothing and doesn't go through the run loop, so it is
+// cheap enough for dashboards that poll it often.
+type View struct {
+	c *Cron
+}
+
+// View returns a read-only accessor over the live entries.
+func (c *Cron) View() View {
+	return View{c}
+}
+
+// Len returns the number of entries.
+func (v View) Len() int {
+	v.c.entriesMu.RLock()
+	defer v.c.entriesMu.RUnlock()
+	return len(v.c.entries)
+}
+
+// Names returns the names of the entries, soonest to run first.
+func (v View) Names() []string {
+	v.c.entriesMu.RLock()
+	defer v.c.entriesMu.RUnlock()
+	names := make([]string, len(v.c.entries))
+	for i, e := range v.c.entries.sorted() {
+		names[i] = e.Name
+	}
+	return names
+}
+
+// Entry returns the named entry. The EntryView keeps showing the last state
+// of the entry once it is removed or replaced.
+func (v View) Entry(name string) (EntryView, bool) {
+	v.c.entriesMu.RLock()
+	defer v.c.entriesMu.RUnlock()
+	e := v.c.lookup(name)
+	if e == nil {
+		return EntryView{}, false
+	}
+	return EntryView{v.c, e}, true
+}
+
+// EntryView is a read-only accessor over the live state of an entry.
+type EntryView struct {
+	c *Cron
+	e *Entry
+}
+
+// Name returns the name of the entry.
+func (v EntryView) Name() string { return v.e.Name }
+
+// Version returns the version of the entry, see UpdateJob.
+func (v EntryView) Version() int { return v.e.Version }
+
+// NextTime returns the next time the entry is due to run, or the zero time
+// if it won't run again.
+func (v EntryView) NextTime() time.Time {
+	v.c.entriesMu.RLock()
+	defer v.c.entriesMu.RUnlock()
+	return v.e.NextTime
+}
+
+// LastOutcome returns how the last run of the entry ended.
+func (v EntryView) LastOutcome() Outcome {
+	v.c.mu.Lock()
+	defer v.c.mu.Unlock()
+	return v.e.LastOutcome
+}
+
+// Runs returns how many runs of the entry were dispatched.
+func (v EntryView) Runs() int {
+	v.c.mu.Lock()
+	defer v.c.mu.Unlock()
+	return v.e.Runs
+}
+
+// Pending returns how many runs of the entry are waiting to start.
+func (v EntryView) Pending() int {
+	v.c.mu.Lock()
+	defer v.c.mu.Unlock()
+	return v.e.Pending
+}
+
+// Disabled reports whether the entry is disabled.
+func (v EntryView) Disabled() bool {
+	v.c.mu.Lock()
+	defer v.c.mu.Unlock()
+	return v.e.Disabled
+}
+
+// Progress returns the latest progress report of the current run of the
+// entry.
+func (v EntryView) Progress() ProgressReport {
+	v.c.mu.Lock()
+	defer v.c.mu.Unlock()
+	return v.e.progress.Last()
+}
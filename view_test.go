@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestView(t *testing.T) {
+	cron := New()
+	cron.AddFunc(time.Now().Add(50*time.Millisecond), time.Hour, func() {}, "job")
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "later")
+	cron.Start()
+	defer cron.Stop()
+
+	waitForHistory(t, cron, "job", 1)
+	view := cron.View()
+	if n := view.Len(); n != 2 {
+		t.Fatalf("expected 2 entries, got %d", n)
+	}
+	e, ok := view.Entry("job")
+	if !ok {
+		t.Fatal("expected to find the entry")
+	}
+	if e.Runs() != 1 || e.LastOutcome() != OutcomeSuccess || !e.NextTime().After(time.Now()) {
+		t.Errorf("unexpected state: runs=%d outcome=%q next=%v", e.Runs(), e.LastOutcome(), e.NextTime())
+	}
+	if _, ok := view.Entry("missing"); ok {
+		t.Error("expected no such entry")
+	}
+}
+
+// Read the view while the run loop changes the entries, for the race
+// detector.
+func TestViewConcurrentWithLoop(t *testing.T) {
+	cron := New()
+	cron.Start()
+	defer cron.Stop()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			for _, name := range cron.View().Names() {
+				if e, ok := cron.View().Entry(name); ok {
+					e.NextTime()
+					e.Runs()
+				}
+			}
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		cron.AddFunc(time.Now(), time.Hour, func() {}, "job")
+		cron.RemoveJob("job")
+	}
+	wg.Wait()
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import "time"
+
+// wallClock is a Schedule firing at a time of day, every day or on a day of
+// the week, on the wall clock of the location it is asked in. Across DST
+// transitions it keeps its time of day, unlike a 24 hour Interval.
+type wallClock struct {
+	weekly  bool
+	weekday time.Weekday
+
+	hour, min, sec int
+}
+
+// Daily returns a Schedule firing every day at hh:mm:ss, for AddFuncOn:
+//
+//	c.AddFuncOn(Daily(2, 30, 0), compact, "compact")
+//
+// The time of day is in the location of the scheduler, see WithLocation. On
+// a day that skips it, such as 02:30 on a spring-forward day, the run comes
+// as many minutes later as the clock skipped.
+func Daily(hh, mm, ss int) Schedule {
+	return wallClock{hour: hh, min: mm, sec: ss}
+}
+
+// Weekly returns a Schedule firing every week on weekday at hh:mm, like
+// Daily.
+func Weekly(weekday time.Weekday, hh, mm int) Schedule {
+	return wallClock{weekly: true, weekday: weekday, hour: hh, min: mm}
+}
+
+func (w wallClock) Next(t time.Time) time.Time {
+	y, m, d := t.Date()
+	if w.weekly {
+		d += (int(w.weekday) - int(t.Weekday()) + 7) % 7
+	}
+	step := 1
+	if w.weekly {
+		step = 7
+	}
+	for {
+		if next := wallTime(y, m, d, w.hour, w.min, w.sec, t.Location()); next.After(t) {
+			return next
+		}
+		d += step
+	}
+}
+
+// wallTime returns the time hh:mm:ss on the given day in loc, or, on a day
+// the clock skips it, as many minutes later as it skipped.
+func wallTime(y int, m time.Month, d, hh, mm, ss int, loc *time.Location) time.Time {
+	t := time.Date(y, m, d, hh, mm, ss, 0, loc)
+	if t.Hour() != hh || t.Minute() != mm {
+		// The clock skipped that time of day. Which side of the gap
+		// time.Date lands on depends on the zone, so take the time of day
+		// by the offsets from before and after the transition, and the
+		// later of the two, which is past it.
+		_, before := t.Add(-3 * time.Hour).Zone()
+		_, after := t.Add(3 * time.Hour).Zone()
+		t = time.Date(y, m, d, hh, mm, ss, 0, time.FixedZone("", min(before, after))).In(loc)
+	}
+	return t
+}
@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDailyAcrossDST(t *testing.T) {
+	loc := newYork(t)
+	daily := Daily(2, 30, 0)
+	at := func(m time.Month, d, hh, mm int) time.Time { return time.Date(2030, m, d, hh, mm, 0, 0, loc) }
+
+	for _, tc := range []struct{ from, want time.Time }{
+		{at(3, 8, 12, 0), at(3, 9, 2, 30)},
+		// 02:30 doesn't exist on spring-forward day.
+		{at(3, 9, 12, 0), at(3, 10, 3, 30)},
+		{at(3, 10, 12, 0), at(3, 11, 2, 30)},
+		{at(11, 2, 12, 0), at(11, 3, 2, 30)},
+		{at(3, 9, 2, 30), at(3, 10, 3, 30)},
+	} {
+		if got := daily.Next(tc.from); !got.Equal(tc.want) {
+			t.Errorf("after %v: expected %v, got %v", tc.from, tc.want, got)
+		}
+	}
+}
+
+// East of UTC, time.Date lands after the gap rather than before it.
+func TestDailyAcrossDSTEastOfUTC(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+	at := func(d, hh, mm int) time.Time { return time.Date(2026, 3, d, hh, mm, 0, 0, loc) }
+	daily := Daily(2, 30, 0)
+	for _, tc := range []struct{ from, want time.Time }{
+		{at(29, 0, 0), at(29, 3, 30)},
+		{at(29, 3, 30), at(30, 2, 30)},
+	} {
+		if got := daily.Next(tc.from); !got.Equal(tc.want) {
+			t.Errorf("after %v: expected %v, got %v", tc.from, tc.want, got)
+		}
+	}
+}
+
+func TestWeekly(t *testing.T) {
+	weekly := Weekly(time.Monday, 9, 0)
+	wed := time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)
+	if got, want := weekly.Next(wed), time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	mon := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+	if got, want := weekly.Next(mon), time.Date(2030, 1, 14, 9, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestAddFuncOn(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	cron := New(WithLocation(tokyo))
+	cron.AddFuncOn(Daily(2, 30, 0), func() {}, "nightly")
+	cron.Start()
+	defer cron.Stop()
+
+	next := cron.Entries()[0].NextTime
+	if next.Hour() != 2 || next.Minute() != 30 || next.Location() != tokyo {
+		t.Errorf("expected 02:30 JST, got %v", next)
+	}
+	if !next.After(time.Now()) || next.Sub(time.Now()) > 24*time.Hour {
+		t.Errorf("expected the next 02:30, got %v", next)
+	}
+}
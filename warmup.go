@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+// warmup caps how many runs go at once for a while after the scheduler
+// starts, see WithWarmup.
+type warmup struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+
+	period   time.Duration
+	from, to int
+
+	start   time.Time
+	running int
+}
+
+func newWarmup(period time.Duration, from, to int) *warmup {
+	if from < 1 {
+		from = 1
+	}
+	if to < from {
+		to = from
+	}
+	w := &warmup{period: period, from: from, to: to}
+	w.changed = sync.NewCond(&w.mu)
+	return w
+}
+
+// restart starts the ramp over, as of now.
+func (w *warmup) restart() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.start = time.Now()
+	w.changed.Broadcast()
+}
+
+// limit returns how many runs may go at once at now, and whether the ramp
+// is still going.
+func (w *warmup) limit(now time.Time) (int, bool) {
+	elapsed := now.Sub(w.start)
+	if w.start.IsZero() || elapsed >= w.period {
+		return 0, false
+	}
+	return w.from + int(int64(w.to-w.from)*int64(elapsed)/int64(w.period)), true
+}
+
+// acquire waits until the ramp lets another run go.
+func (w *warmup) acquire() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for {
+		limit, ramping := w.limit(time.Now())
+		if !ramping || w.running < limit {
+			w.running++
+			return
+		}
+		// Check again when the limit next goes up, or a run is over.
+		step := w.period / time.Duration(w.to-w.from+1)
+		timer := time.AfterFunc(step, func() {
+			w.mu.Lock()
+			w.changed.Broadcast()
+			w.mu.Unlock()
+		})
+		w.changed.Wait()
+		timer.Stop()
+	}
+}
+
+func (w *warmup) release() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.running--
+	w.changed.Broadcast()
+}
@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWarmupRampsConcurrency(t *testing.T) {
+	cron := New(WithWarmup(400*time.Millisecond, 1, 5))
+	var mu sync.Mutex
+	running, most, early := 0, 0, 0
+	due := time.Now().Add(50 * time.Millisecond)
+	started := due
+	for i := 0; i < 12; i++ {
+		cron.AddFunc(due, time.Hour, func() {
+			mu.Lock()
+			running++
+			if running > most {
+				most = running
+			}
+			if time.Since(started) < 30*time.Millisecond && running > early {
+				early = running
+			}
+			mu.Unlock()
+			time.Sleep(100 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}, fmt.Sprint("job", i))
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	for i := 0; i < 12; i++ {
+		waitForHistory(t, cron, fmt.Sprint("job", i), 1)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if early != 1 {
+		t.Errorf("expected one run at a time at first, got %d", early)
+	}
+	if most <= 1 || most > 5 {
+		t.Errorf("expected the ramp to let up to 5 runs go at once, got %d", most)
+	}
+}
@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// How long Trigger remembers an idempotency key.
+const idempotencyTTL = 24 * time.Hour
+
+// Trigger runs the named entry right away, through the usual dispatch like
+// RunNow, without waiting for the run, for upstream systems to kick an entry
+// early, such as once its data has arrived. A non-empty key makes it
+// idempotent: Trigger called again with a key it has seen for the entry
+// within a day doesn't start a run, and returns false. The key of a run
+// that was not dispatched is not kept, so the call may be retried.
+func (c *Cron) Trigger(name, key string) (started bool, err error) {
+	if key != "" {
+		now := time.Now()
+		id := name + "\x00" + key
+		c.mu.Lock()
+		for k, at := range c.triggerKeys {
+			if now.Sub(at) > idempotencyTTL {
+				delete(c.triggerKeys, k)
+			}
+		}
+		if _, seen := c.triggerKeys[id]; seen {
+			c.mu.Unlock()
+			return false, nil
+		}
+		if c.triggerKeys == nil {
+			c.triggerKeys = make(map[string]time.Time)
+		}
+		c.triggerKeys[id] = now
+		c.mu.Unlock()
+		defer func() {
+			if err != nil {
+				c.mu.Lock()
+				delete(c.triggerKeys, id)
+				c.mu.Unlock()
+			}
+		}()
+	}
+	if err := c.runNow(name, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// triggerResult is the response to POST /trigger/{name}.
+type triggerResult struct {
+	Name    string `json:"name"`
+	Key     string `json:"key,omitempty"`
+	Started bool   `json:"started"`
+}
+
+func (a *Admin) trigger(w http.ResponseWriter, r *http.Request, name string) {
+	key := r.Header.Get("Idempotency-Key")
+	if key == "" {
+		key = r.URL.Query().Get("key")
+	}
+	started, err := a.cron.Trigger(name, key)
+	switch {
+	case errors.Is(err, ErrNoSuchEntry):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	status := http.StatusOK
+	if started {
+		status = http.StatusAccepted
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(triggerResult{Name: name, Key: key, Started: started})
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTrigger(t *testing.T) {
+	cron := New()
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "job")
+	cron.Start()
+	defer cron.Stop()
+
+	if started, err := cron.Trigger("job", "k1"); !started || err != nil {
+		t.Fatalf("expected the first trigger to start, got %v, %v", started, err)
+	}
+	waitForHistory(t, cron, "job", 1)
+	if started, err := cron.Trigger("job", "k1"); started || err != nil {
+		t.Errorf("expected a repeated key not to start, got %v, %v", started, err)
+	}
+	if started, err := cron.Trigger("job", "k2"); !started || err != nil {
+		t.Errorf("expected a new key to start, got %v, %v", started, err)
+	}
+	waitForHistory(t, cron, "job", 2)
+	if _, err := cron.Trigger("missing", "k1"); err != ErrNoSuchEntry {
+		t.Errorf("expected ErrNoSuchEntry, got %v", err)
+	}
+}
+
+func TestAdminTrigger(t *testing.T) {
+	cron := New()
+	cron.AddFunc(time.Now().Add(time.Hour), time.Hour, func() {}, "job")
+	cron.Start()
+	defer cron.Stop()
+	admin := NewAdmin(cron, TokenAuth(map[string]Role{"r": RoleReader, "hook": RoleTrigger, "op": RoleOperator}))
+
+	for _, tc := range []struct {
+		method, path, token string
+		code                int
+	}{
+		{"POST", "/trigger/job?key=a", "r", http.StatusForbidden},
+		{"POST", "/trigger/job?key=a", "hook", http.StatusAccepted},
+		{"POST", "/trigger/job?key=a", "op", http.StatusOK},
+		{"POST", "/trigger/missing", "hook", http.StatusNotFound},
+		{"POST", "/entries/job/run", "hook", http.StatusForbidden},
+		// A webhook token reads nothing.
+		{"GET", "/entries", "hook", http.StatusForbidden},
+		{"GET", "/history", "hook", http.StatusForbidden},
+		{"GET", "/running", "hook", http.StatusForbidden},
+		{"GET", "/entries", "op", http.StatusOK},
+	} {
+		if w := adminRequest(t, admin, tc.method, tc.path, tc.token); w.Code != tc.code {
+			t.Errorf("%s %s as %q: expected %d, got %d", tc.method, tc.path, tc.token, tc.code, w.Code)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"hash/fnv"
+	"time"
+)
+
+// DailyWindow is a time of day range, as offsets from midnight in the
+// location of the schedule. A window whose To is not after its From runs
+// past midnight, so {22h, 2h} is 22:00-02:00.
+type DailyWindow struct {
+	From, To time.Duration
+}
+
+// WithPreferredWindow makes the entry flexible: each run may start up to
+// flex later than scheduled, and is placed within the window when the two
+// overlap, for example "every evening at 20:00, any time tonight, ideally
+// between 01:00 and 05:00" is
+//
+//	c.AddFunc(eightPM, 24*time.Hour, job, "compact",
+//		WithPreferredWindow(1*time.Hour, 5*time.Hour, 10*time.Hour))
+//
+// Each entry gets its own spot within the window, derived from its name, so
+// flexible entries spread over the window instead of piling up at its
+// start. When a run cannot reach the window it runs as scheduled.
+func WithPreferredWindow(from, to, flex time.Duration) EntryOption {
+	return func(e *Entry) {
+		e.Preferred = DailyWindow{From: from, To: to}
+		e.Flex = flex
+	}
+}
+
+// place returns when a run due at nominal should start, shifted by the
+// phase of the entry, in the location of the scheduler.
+func (e *Entry) place(nominal time.Time) time.Time {
+	nominal = nominal.Add(e.Phase)
+	// In drops the monotonic reading, so leave times alone that are in the
+	// location already.
+	if e.location != nil && nominal.Location() != e.location {
+		nominal = nominal.In(e.location)
+	}
+	if e.Flex <= 0 {
+		return nominal
+	}
+	latest := nominal.Add(e.Flex)
+	y, m, d := nominal.Date()
+	// Start the day before, as its window may run past midnight.
+	for day := time.Date(y, m, d-1, 0, 0, 0, 0, nominal.Location()); !day.After(latest); day = day.AddDate(0, 0, 1) {
+		from, to := e.Preferred.on(day)
+		if !to.After(nominal) || from.After(latest) {
+			continue
+		}
+		spot := from.Add(time.Duration(nameHash(e.Name) % uint64(to.Sub(from))))
+		if spot.Before(nominal) {
+			spot = nominal
+		}
+		if spot.After(latest) {
+			spot = latest
+		}
+		return spot
+	}
+	return nominal
+}
+
+// on returns the span of the window on the given day. The offsets are wall
+// clock ones, so the window keeps its hours on the days DST starts or ends.
+func (w DailyWindow) on(day time.Time) (from, to time.Time) {
+	y, m, d := day.Date()
+	from = time.Date(y, m, d, 0, 0, int(w.From/time.Second), int(w.From%time.Second), day.Location())
+	to = time.Date(y, m, d, 0, 0, int(w.To/time.Second), int(w.To%time.Second), day.Location())
+	if !to.After(from) {
+		to = to.AddDate(0, 0, 1)
+	}
+	return from, to
+}
+
+func nameHash(name string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return h.Sum64()
+}
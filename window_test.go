@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPreferredWindowPlacement(t *testing.T) {
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2024, 3, day, hour, min, 0, 0, time.UTC)
+	}
+	e := &Entry{Name: "compact"}
+	WithPreferredWindow(1*time.Hour, 5*time.Hour, 10*time.Hour)(e)
+
+	got := e.place(at(1, 20, 0))
+	if got.Before(at(2, 1, 0)) || !got.Before(at(2, 5, 0)) {
+		t.Errorf("expected a start within 01:00-05:00 the next day, got %v", got)
+	}
+	if again := e.place(at(1, 20, 0)); !again.Equal(got) {
+		t.Errorf("expected a stable placement, got %v then %v", got, again)
+	}
+
+	// Too little flex to reach the window: run as scheduled.
+	WithPreferredWindow(1*time.Hour, 5*time.Hour, time.Hour)(e)
+	if got := e.place(at(1, 20, 0)); !got.Equal(at(1, 20, 0)) {
+		t.Errorf("expected no change, got %v", got)
+	}
+
+	// Already in a window crossing midnight: never earlier than scheduled.
+	WithPreferredWindow(22*time.Hour, 2*time.Hour, time.Hour)(e)
+	if got := e.place(at(1, 23, 30)); got.Before(at(1, 23, 30)) || got.After(at(2, 0, 30)) {
+		t.Errorf("expected a start between 23:30 and 00:30, got %v", got)
+	}
+}
+
+func TestPreferredWindowSpreadsEntries(t *testing.T) {
+	nominal := time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)
+	spots := map[time.Time]bool{}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		e := &Entry{Name: name}
+		WithPreferredWindow(1*time.Hour, 5*time.Hour, 10*time.Hour)(e)
+		spots[e.place(nominal)] = true
+	}
+	if len(spots) < 4 {
+		t.Errorf("expected the entries to spread over the window, got %v", spots)
+	}
+}
+
+// The interval keeps counting from the scheduled time, not the placed one.
+func TestPreferredWindowKeepsCadence(t *testing.T) {
+	e := &Entry{Name: "compact", Interval: 24 * time.Hour, setStartTime: time.Now().Add(time.Hour)}
+	WithPreferredWindow(0, time.Hour, 23*time.Hour)(e)
+	e.Next()
+	first := e.NextTime
+	e.Next()
+	if d := e.nominal.Sub(e.setStartTime); d != 24*time.Hour {
+		t.Errorf("expected the nominal time to advance by the interval, got %v", d)
+	}
+	if d := e.NextTime.Sub(first); d != 24*time.Hour {
+		t.Errorf("expected runs a day apart, got %v", d)
+	}
+}
+
+func TestWithLocation(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	cron := New(WithLocation(tokyo))
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	cron.AddFunc(start, time.Hour, func() {}, "job")
+	cron.Start()
+	defer cron.Stop()
+
+	next := cron.Entries()[0].NextTime
+	if next.Location() != tokyo || !next.Equal(start) || next.Hour() != 9 {
+		t.Errorf("expected the start time in JST, got %v", next)
+	}
+}
+
+// The daily window is in the location of the scheduler, not that of the
+// start time.
+func TestWithLocationWindow(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	cron := New(WithLocation(tokyo))
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	cron.AddFunc(start, 24*time.Hour, func() {}, "job", WithPreferredWindow(12*time.Hour, 13*time.Hour, 12*time.Hour))
+	cron.Start()
+	defer cron.Stop()
+
+	next := cron.Entries()[0].NextTime
+	if next.Hour() != 12 || next.Location() != tokyo {
+		t.Errorf("expected a spot between 12:00 and 13:00 JST, got %v", next)
+	}
+}